@@ -1,17 +1,22 @@
 package main
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/barannkoca/banking-backend/config"
 	"github.com/barannkoca/banking-backend/internal/api"
+	"github.com/barannkoca/banking-backend/internal/authz"
 	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/events"
+	"github.com/barannkoca/banking-backend/internal/models"
 	"github.com/barannkoca/banking-backend/internal/processing"
 	"github.com/barannkoca/banking-backend/internal/repository"
 	"github.com/barannkoca/banking-backend/internal/services"
 	"github.com/barannkoca/banking-backend/pkg/graceful"
 	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/barannkoca/banking-backend/pkg/utils"
 	"go.uber.org/zap"
 )
 
@@ -61,6 +66,11 @@ func main() {
 	userRepo := repository.NewUserRepository(database.GetDB())
 	transactionRepo := repository.NewTransactionRepository(database.GetDB())
 	balanceRepo := repository.NewBalanceRepository(database.GetDB())
+	jobRepo := repository.NewJobRepository(database.GetDB())
+	deadLetterRepo := repository.NewDeadLetterRepository(database.GetDB())
+	batchRepo := repository.NewBatchRepository(database.GetDB())
+	paymentImportRepo := repository.NewPaymentImportRepository(database.GetDB())
+	categoryRuleRepo := repository.NewCategoryRuleRepository(database.GetDB())
 
 	// Initialize Redis cache service
 	cacheService, err := services.NewRedisCacheService("localhost:6379", "", 0)
@@ -77,21 +87,182 @@ func main() {
 	// Initialize audit service
 	auditService := services.NewAuditService(log)
 
-	userService := services.NewUserService(userRepo, auditService)
-	transactionService := services.NewTransactionService(transactionRepo, balanceRepo, auditService, cacheService, log)
+	userService := services.NewUserService(userRepo, auditService, services.PasswordPolicy{
+		MinLength:      cfg.Password.MinLength,
+		MaxLength:      cfg.Password.MaxLength,
+		RequireUpper:   cfg.Password.RequireUpper,
+		RequireLower:   cfg.Password.RequireLower,
+		RequireNumber:  cfg.Password.RequireNumber,
+		RequireSpecial: cfg.Password.RequireSpecial,
+		RejectCommon:   cfg.Password.RejectCommon,
+		HistorySize:    cfg.Password.HistorySize,
+		MaxAge:         cfg.Password.MaxAge,
+	})
+	// Load the JWT signing keys; tokens cannot be issued or validated without them
+	signingKeyEncryptionKey, err := cfg.JWT.SigningKeyEncryptionKey()
+	if err != nil {
+		log.Fatal("Invalid JWT key configuration",
+			zap.Error(err),
+			zap.String("type", "config_error"),
+		)
+	}
+	jwtConfig := &utils.JWTConfig{
+		Keys:               utils.NewKeySet(),
+		AccessTokenExpiry:  cfg.JWT.AccessTokenExpiry,
+		RefreshTokenExpiry: cfg.JWT.RefreshTokenExpiry,
+	}
+	signingKeyService := services.NewSigningKeyService(jwtConfig.Keys, signingKeyEncryptionKey, services.SigningKeyPolicy{
+		Algorithm:        cfg.JWT.SigningAlgorithm,
+		RotationInterval: cfg.JWT.KeyRotationInterval,
+		Overlap:          cfg.JWT.KeyOverlap,
+	}, log)
+	if err := signingKeyService.Load(context.Background()); err != nil {
+		log.Fatal("Failed to load JWT signing keys",
+			zap.Error(err),
+			zap.String("type", "signing_key_error"),
+		)
+	}
+	signingKeyService.Start()
+
+	tokenService := services.NewTokenService(userRepo, auditService, jwtConfig, log)
+	sessionService := services.NewSessionService(userRepo, auditService, log)
+	mfaKey, err := cfg.MFA.Key(cfg.JWT.Secret)
+	if err != nil {
+		log.Fatal("Invalid MFA configuration",
+			zap.Error(err),
+			zap.String("type", "config_error"),
+		)
+	}
+	mfaService := services.NewMFAService(userRepo, auditService, jwtConfig, mfaKey, cfg.MFA.Issuer, cfg.MFA.ChallengeTTL, log)
+	lockoutService := services.NewLockoutService(userRepo, auditService, services.NewLoggingLockoutNotifier(log), services.LockoutPolicy{
+		MaxUserAttempts: cfg.Lockout.MaxUserAttempts,
+		MaxIPAttempts:   cfg.Lockout.MaxIPAttempts,
+		Duration:        cfg.Lockout.Duration,
+		MaxDuration:     cfg.Lockout.MaxDuration,
+		DelayBase:       cfg.Lockout.DelayBase,
+		MaxDelay:        cfg.Lockout.MaxDelay,
+		ResetAfter:      cfg.Lockout.ResetAfter,
+	}, log)
+	notificationService := services.NewLogNotificationService(log)
+	accountService := services.NewAccountService(userRepo, userService, notificationService, auditService, cfg.Account.PasswordResetTTL, cfg.Account.EmailVerificationTTL, cfg.Account.ResendInterval, log)
+	stepUpService := services.NewStepUpService(userService, userRepo, mfaService, tokenService, auditService, jwtConfig, cfg.StepUp.TokenTTL, cfg.StepUp.ChallengeTTL, cfg.StepUp.MaxAttempts, log)
+	apiClientService := services.NewAPIClientService(jwtConfig, cfg.OAuth.ClientTokenTTL, auditService, log)
+	apiKeyService := services.NewAPIKeyService(auditService, cfg.APIKeys.MaxLifetime, cfg.APIKeys.MaxPerUser, log)
+	categorizer := services.NewCategorizer(categoryRuleRepo, log)
+
+	// Initialize event broker for the real-time balance and transaction stream
+	broker := events.NewBroker(events.Config{
+		MaxConnectionsPerUser: cfg.Stream.MaxConnectionsPerUser,
+		MaxConnections:        cfg.Stream.MaxConnections,
+		SendBufferSize:        cfg.Stream.SendBufferSize,
+		ReplayBufferSize:      cfg.Stream.ReplayBufferSize,
+	}, log)
+
+	// Role permissions with per-role amount limits
+	policy := authz.NewPolicy(map[authz.Permission]map[models.UserRole]float64{
+		authz.AccountsCredit: {models.RoleTeller: cfg.Authz.TellerCreditLimit},
+	})
+	transactionService := services.NewTransactionService(transactionRepo, balanceRepo, auditService, cacheService, categorizer, broker, policy, log)
 	balanceService := services.NewBalanceService(balanceRepo, auditService, cacheService)
 
-	// Initialize worker pool for transaction processing
-	workerPool := processing.NewWorkerPool(5, 100, log) // 5 workers, 100 job queue size
+	// Services attached to jobs restored from storage
+	jobServices := processing.JobServices{
+		TransactionService: transactionService,
+		BalanceService:     balanceService,
+		AuditService:       auditService,
+	}
+
+	// Initialize job queue for the worker pool
+	var jobQueue processing.JobQueue
+	switch cfg.JobQueue.Backend {
+	case "memory":
+		memoryQueue := processing.NewMemoryJobQueue(cfg.JobQueue.MaxQueueSize, log)
+		memoryQueue.SetDeadLetterRepository(deadLetterRepo)
+		jobQueue = memoryQueue
+	default:
+		jobQueue = processing.NewPostgresJobQueue(database.GetDB(), jobServices, processing.PostgresQueueConfig{
+			VisibilityTimeout: cfg.JobQueue.VisibilityTimeout,
+			MaxDeliveries:     cfg.JobQueue.MaxDeliveries,
+			PollInterval:      cfg.JobQueue.PollInterval,
+			MaxLeasedPerUser:  cfg.JobQueue.MaxLeasedPerUser,
+		}, log)
+	}
+	log.Info("Job queue initialized", zap.String("backend", cfg.JobQueue.Backend))
+
+	// Initialize autoscaling worker pool for transaction processing
+	workerPool := processing.NewAutoscalingWorkerPool(processing.PoolConfig{
+		MinWorkers:        cfg.WorkerPool.MinWorkers,
+		MaxWorkers:        cfg.WorkerPool.MaxWorkers,
+		MaxPendingJobs:    cfg.WorkerPool.MaxPendingJobs,
+		ScaleUpQueueDepth: cfg.WorkerPool.ScaleUpQueueDepth,
+		ScaleUpLatency:    cfg.WorkerPool.ScaleUpLatency,
+		ScaleDownAfter:    cfg.WorkerPool.ScaleDownAfter,
+		ScaleInterval:     cfg.WorkerPool.ScaleInterval,
+	}, jobQueue, log)
+
+	// Initialize job service for async transaction tracking
+	jobService := services.NewJobService(jobRepo, transactionService, balanceService, auditService, workerPool, log)
+
+	// Initialize dead-letter service for failed job administration
+	deadLetterService := services.NewDeadLetterService(deadLetterRepo, jobRepo, auditService, jobServices, workerPool, log)
+
+	// Initialize batch service for bulk transfers
+	batchService := services.NewBatchService(batchRepo, userRepo, transactionService, jobService, auditService, workerPool, cfg.Batch.MaxItems, log)
+
+	// Initialize payment file import service for CSV and pain.001 uploads
+	paymentImportService := services.NewPaymentImportService(paymentImportRepo, userRepo, batchService, transactionService, auditService, cfg.App.Currency, log)
+	statementService := services.NewStatementService(userRepo, balanceRepo, auditService, cfg.App.Currency, log)
+	searchService := services.NewSearchService(log)
+	categoryService := services.NewCategoryService(categoryRuleRepo, userRepo, categorizer, auditService, log)
+	analyticsService := services.NewAnalyticsService(log)
+
+	// Initialize maker-checker approvals; these operations run only after a second user approves
+	approvalService := services.NewApprovalService(notificationService, auditService, map[models.ApprovalType]services.ApprovalAction{
+		models.ApprovalTypeRoleChange:     services.RoleChangeAction(userService),
+		models.ApprovalTypeCredit:         services.CreditAction(transactionService, jobService),
+		models.ApprovalTypeRefund:         services.RefundAction(transactionService, jobService),
+		models.ApprovalTypeAccountFreeze:  services.AccountFreezeAction(balanceService.(*services.BalanceService)),
+		models.ApprovalTypeDeadLetterEdit: services.DeadLetterEditAction(deadLetterService, transactionService),
+	}, cfg.Approvals.RequestTTL, log)
+	deadLetterService.SetApprovalService(approvalService)
+	approvalService.Start()
+
+	// Resume jobs left over from a previous run
+	if err := jobService.RecoverJobs(context.Background()); err != nil {
+		log.Warn("Failed to recover pending jobs",
+			zap.Error(err),
+			zap.String("type", "job_recovery_warning"),
+		)
+	}
+
+	// Categorize transactions recorded before categorization existed
+	go func() {
+		if _, err := categoryService.Backfill(context.Background()); err != nil {
+			log.Warn("Failed to backfill transaction categories",
+				zap.Error(err),
+				zap.String("type", "category_backfill_warning"),
+			)
+		}
+	}()
 
 	// Initialize custom router with all middleware
-	r := api.SetupRouter(userService, transactionService, balanceService.(*services.BalanceService), auditService, workerPool)
+	r := api.SetupRouter(userService, tokenService, sessionService, mfaService, stepUpService, lockoutService, accountService, signingKeyService, apiClientService, apiKeyService, transactionService, balanceService.(*services.BalanceService), auditService, workerPool, jobService, deadLetterService, batchService, paymentImportService, statementService, searchService, categoryService, analyticsService, approvalService, broker, cfg.Stream.HeartbeatInterval, cfg.Stream.MaxConnectionDuration, cfg.StepUp.AmountThreshold)
+	// Client IPs feed lockouts, rate limits and API key allow-lists; only the configured
+	// proxies may supply them through X-Forwarded-For
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxy configuration",
+			zap.Error(err),
+			zap.String("type", "config_error"),
+		)
+	}
 
 	// Create HTTP server
 	server := &http.Server{
 		Addr:    ":" + cfg.Server.Port,
 		Handler: r,
 	}
+	// Streaming connections end when the broker closes, so Shutdown does not wait for them
+	server.RegisterOnShutdown(broker.Close)
 
 	// Initialize graceful shutdown handler
 	shutdownHandler := graceful.NewShutdownHandler(server, 30*time.Second)
@@ -101,6 +272,8 @@ func main() {
 		log.Info("Shutting down worker pool...")
 		return workerPool.Shutdown(30 * time.Second)
 	})
+	shutdownHandler.AddCleanupTask(signingKeyService.Stop)
+	shutdownHandler.AddCleanupTask(approvalService.Stop)
 	shutdownHandler.AddCleanupTask(graceful.CleanupTransactionQueue())
 	shutdownHandler.AddCleanupTask(graceful.CleanupAuditLogs())
 
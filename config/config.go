@@ -1,22 +1,40 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Database  DatabaseConfig
-	Server    ServerConfig
-	JWT       JWTConfig
-	App       AppConfig
-	RateLimit RateLimitConfig
-	Security  SecurityConfig
+	Database   DatabaseConfig
+	Server     ServerConfig
+	JWT        JWTConfig
+	App        AppConfig
+	RateLimit  RateLimitConfig
+	Security   SecurityConfig
+	JobQueue   JobQueueConfig
+	WorkerPool WorkerPoolConfig
+	Batch      BatchConfig
+	Stream     StreamConfig
+	MFA        MFAConfig
+	StepUp     StepUpConfig
+	Lockout    LockoutConfig
+	Account    AccountTokensConfig
+	Password   PasswordPolicyConfig
+	OAuth      OAuthConfig
+	APIKeys    APIKeyConfig
+	Authz      AuthzConfig
+	Approvals  ApprovalConfig
 }
 
 // DatabaseConfig holds database configuration
@@ -31,19 +49,27 @@ type DatabaseConfig struct {
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port string
-	Host string
+	Port           string
+	Host           string
+	TrustedProxies []string // IPs or CIDRs whose X-Forwarded-For is trusted; empty trusts none
 }
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret string
+	Secret              string // only used to derive encryption keys outside production
+	AccessTokenExpiry   time.Duration
+	RefreshTokenExpiry  time.Duration
+	SigningAlgorithm    string // RS256 or EdDSA
+	KeyEncryptionKey    string // base64, 32 bytes; encrypts stored private signing keys
+	KeyRotationInterval time.Duration
+	KeyOverlap          time.Duration // verification period after a key stops signing
 }
 
 // AppConfig holds application configuration
 type AppConfig struct {
 	Environment string
 	LogLevel    string
+	Currency    string // ISO 4217 code of all balances, used in ISO 20022 messages
 }
 
 // RateLimitConfig holds rate limiting configuration
@@ -64,6 +90,138 @@ type SecurityConfig struct {
 	EnableCSP      bool
 }
 
+// JobQueueConfig holds worker pool job queue configuration
+type JobQueueConfig struct {
+	Backend           string // "postgres" or "memory"
+	MaxQueueSize      int    // only used by the memory backend
+	VisibilityTimeout time.Duration
+	MaxDeliveries     int
+	PollInterval      time.Duration
+	MaxLeasedPerUser  int // leased jobs per submitter, 0 means no limit
+}
+
+// WorkerPoolConfig holds worker pool sizing and autoscaling configuration
+type WorkerPoolConfig struct {
+	MinWorkers        int
+	MaxWorkers        int
+	MaxPendingJobs    int
+	ScaleUpQueueDepth int
+	ScaleUpLatency    time.Duration
+	ScaleDownAfter    time.Duration
+	ScaleInterval     time.Duration
+}
+
+// BatchConfig holds batch transfer configuration
+type BatchConfig struct {
+	MaxItems int
+}
+
+// StreamConfig holds real-time event stream configuration
+type StreamConfig struct {
+	HeartbeatInterval     time.Duration
+	MaxConnectionsPerUser int
+	MaxConnections        int
+	MaxConnectionDuration time.Duration
+	SendBufferSize        int // events queued per connection before it is dropped as too slow
+	ReplayBufferSize      int // recent events kept per user for Last-Event-ID resume
+}
+
+// MFAConfig holds two-factor authentication configuration
+type MFAConfig struct {
+	Issuer        string        // shown in authenticator apps
+	EncryptionKey string        // base64 encoded 32-byte AES key for TOTP secrets at rest
+	ChallengeTTL  time.Duration // lifetime of the token between password and TOTP steps
+}
+
+// StepUpConfig holds step-up (re-authentication) configuration for sensitive operations
+type StepUpConfig struct {
+	AmountThreshold float64       // outgoing transfers above this amount need a step-up token
+	TokenTTL        time.Duration // lifetime of the elevated token
+	ChallengeTTL    time.Duration // lifetime of the step-up challenge
+	MaxAttempts     int           // wrong credentials before a challenge is used up
+}
+
+// LockoutConfig holds failed-login throttling configuration
+type LockoutConfig struct {
+	MaxUserAttempts int           // failed logins before an account is locked
+	MaxIPAttempts   int           // failed logins before a client IP is locked
+	Duration        time.Duration // first lockout; each further lockout doubles it
+	MaxDuration     time.Duration
+	DelayBase       time.Duration // wait enforced after the second failure, doubling per failure
+	MaxDelay        time.Duration
+	ResetAfter      time.Duration // failures older than this are forgotten
+}
+
+// AccountTokensConfig holds password reset and email verification token configuration
+type AccountTokensConfig struct {
+	PasswordResetTTL     time.Duration
+	EmailVerificationTTL time.Duration
+	ResendInterval       time.Duration // minimum wait between verification emails
+}
+
+// PasswordPolicyConfig holds the rules for new passwords
+type PasswordPolicyConfig struct {
+	MinLength      int
+	MaxLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireNumber  bool
+	RequireSpecial bool
+	RejectCommon   bool          // check the embedded common/breached password list
+	HistorySize    int           // recent passwords, including the current one, that cannot be reused
+	MaxAge         time.Duration // 0 disables forced rotation
+}
+
+// OAuthConfig holds OAuth2 client credentials configuration
+type OAuthConfig struct {
+	ClientTokenTTL time.Duration // lifetime of API client access tokens
+}
+
+// APIKeyConfig holds personal API key configuration
+type APIKeyConfig struct {
+	MaxLifetime time.Duration // longest and default key lifetime
+	MaxPerUser  int           // active keys per user
+}
+
+// AuthzConfig holds role limits of the permission policy
+type AuthzConfig struct {
+	TellerCreditLimit float64 // largest credit a teller may make to another user's account
+}
+
+// ApprovalConfig holds maker-checker approval configuration
+type ApprovalConfig struct {
+	RequestTTL time.Duration // how long a request may await a decision
+}
+
+// SigningKeyEncryptionKey returns the AES key for stored JWT signing keys. Without
+// JWT_KEY_ENCRYPTION_KEY a key is derived from the JWT secret, which validate only allows
+// outside production.
+func (j JWTConfig) SigningKeyEncryptionKey() ([]byte, error) {
+	if j.KeyEncryptionKey == "" {
+		sum := sha256.Sum256([]byte("jwt-signing-keys:" + j.Secret))
+		return sum[:], nil
+	}
+	key, err := base64.StdEncoding.DecodeString(j.KeyEncryptionKey)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("JWT_KEY_ENCRYPTION_KEY must be 32 bytes, base64 encoded")
+	}
+	return key, nil
+}
+
+// Key returns the AES key for TOTP secrets. Without MFA_ENCRYPTION_KEY a key is derived
+// from the JWT secret, which validate only allows outside production.
+func (m MFAConfig) Key(jwtSecret string) ([]byte, error) {
+	if m.EncryptionKey == "" {
+		sum := sha256.Sum256([]byte("mfa:" + jwtSecret))
+		return sum[:], nil
+	}
+	key, err := base64.StdEncoding.DecodeString(m.EncryptionKey)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("MFA_ENCRYPTION_KEY must be 32 bytes, base64 encoded")
+	}
+	return key, nil
+}
+
 var cfg *Config
 
 // Load loads configuration from environment variables and .env file
@@ -85,13 +243,22 @@ func Load() (*Config, error) {
 		Server: ServerConfig{
 			Port: getEnv("SERVER_PORT", "8080"),
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
+			// Without trusted proxies the client IP is the peer address, so it cannot be spoofed
+			TrustedProxies: getEnvAsList("TRUSTED_PROXIES", nil),
 		},
 		JWT: JWTConfig{
-			Secret: getEnv("JWT_SECRET", "your-super-secure-jwt-secret-key-here"),
+			Secret:              getEnv("JWT_SECRET", "your-super-secure-jwt-secret-key-here"),
+			AccessTokenExpiry:   getEnvAsDuration("JWT_ACCESS_TOKEN_EXPIRY", 15*time.Minute),
+			RefreshTokenExpiry:  getEnvAsDuration("JWT_REFRESH_TOKEN_EXPIRY", 7*24*time.Hour),
+			SigningAlgorithm:    getEnv("JWT_SIGNING_ALGORITHM", "RS256"),
+			KeyEncryptionKey:    getEnv("JWT_KEY_ENCRYPTION_KEY", ""),
+			KeyRotationInterval: getEnvAsDuration("JWT_KEY_ROTATION_INTERVAL", 30*24*time.Hour),
+			KeyOverlap:          getEnvAsDuration("JWT_KEY_OVERLAP", 8*24*time.Hour),
 		},
 		App: AppConfig{
 			Environment: getEnv("ENVIRONMENT", "development"),
 			LogLevel:    getEnv("LOG_LEVEL", "info"),
+			Currency:    getEnv("CURRENCY", "TRY"),
 		},
 		RateLimit: RateLimitConfig{
 			GlobalRequestsPerSecond:  getEnvAsFloat("RATE_LIMIT_GLOBAL_RPS", 10.0),
@@ -107,6 +274,83 @@ func Load() (*Config, error) {
 			EnableHSTS:     getEnvAsBool("ENABLE_HSTS", true),
 			EnableCSP:      getEnvAsBool("ENABLE_CSP", true),
 		},
+		JobQueue: JobQueueConfig{
+			Backend:           getEnv("JOB_QUEUE_BACKEND", "postgres"),
+			MaxQueueSize:      getEnvAsInt("JOB_QUEUE_MAX_SIZE", 100),
+			VisibilityTimeout: getEnvAsDuration("JOB_QUEUE_VISIBILITY_TIMEOUT", 30*time.Second),
+			MaxDeliveries:     getEnvAsInt("JOB_QUEUE_MAX_DELIVERIES", 5),
+			PollInterval:      getEnvAsDuration("JOB_QUEUE_POLL_INTERVAL", 500*time.Millisecond),
+			MaxLeasedPerUser:  getEnvAsInt("JOB_QUEUE_MAX_LEASED_PER_USER", 20),
+		},
+		WorkerPool: WorkerPoolConfig{
+			MinWorkers:        getEnvAsInt("WORKER_POOL_MIN_WORKERS", 2),
+			MaxWorkers:        getEnvAsInt("WORKER_POOL_MAX_WORKERS", 20),
+			MaxPendingJobs:    getEnvAsInt("WORKER_POOL_MAX_PENDING_JOBS", 1000),
+			ScaleUpQueueDepth: getEnvAsInt("WORKER_POOL_SCALE_UP_QUEUE_DEPTH", 5),
+			ScaleUpLatency:    getEnvAsDuration("WORKER_POOL_SCALE_UP_LATENCY", 2*time.Second),
+			ScaleDownAfter:    getEnvAsDuration("WORKER_POOL_SCALE_DOWN_AFTER", 30*time.Second),
+			ScaleInterval:     getEnvAsDuration("WORKER_POOL_SCALE_INTERVAL", time.Second),
+		},
+		Batch: BatchConfig{
+			MaxItems: getEnvAsInt("BATCH_MAX_ITEMS", 500),
+		},
+		MFA: MFAConfig{
+			Issuer:        getEnv("MFA_ISSUER", "Banking Backend"),
+			EncryptionKey: getEnv("MFA_ENCRYPTION_KEY", ""),
+			ChallengeTTL:  getEnvAsDuration("MFA_CHALLENGE_TTL", 5*time.Minute),
+		},
+		StepUp: StepUpConfig{
+			AmountThreshold: getEnvAsFloat("STEP_UP_AMOUNT_THRESHOLD", 10000),
+			TokenTTL:        getEnvAsDuration("STEP_UP_TOKEN_TTL", 5*time.Minute),
+			ChallengeTTL:    getEnvAsDuration("STEP_UP_CHALLENGE_TTL", 5*time.Minute),
+			MaxAttempts:     getEnvAsInt("STEP_UP_CHALLENGE_MAX_ATTEMPTS", 3),
+		},
+		Lockout: LockoutConfig{
+			MaxUserAttempts: getEnvAsInt("LOCKOUT_USER_MAX_ATTEMPTS", 5),
+			MaxIPAttempts:   getEnvAsInt("LOCKOUT_IP_MAX_ATTEMPTS", 20),
+			Duration:        getEnvAsDuration("LOCKOUT_DURATION", 15*time.Minute),
+			MaxDuration:     getEnvAsDuration("LOCKOUT_MAX_DURATION", 24*time.Hour),
+			DelayBase:       getEnvAsDuration("LOCKOUT_DELAY_BASE", time.Second),
+			MaxDelay:        getEnvAsDuration("LOCKOUT_MAX_DELAY", 30*time.Second),
+			ResetAfter:      getEnvAsDuration("LOCKOUT_RESET_AFTER", time.Hour),
+		},
+		Account: AccountTokensConfig{
+			PasswordResetTTL:     getEnvAsDuration("PASSWORD_RESET_TTL", 30*time.Minute),
+			EmailVerificationTTL: getEnvAsDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+			ResendInterval:       getEnvAsDuration("EMAIL_VERIFICATION_RESEND_INTERVAL", time.Minute),
+		},
+		Password: PasswordPolicyConfig{
+			MinLength:      getEnvAsInt("PASSWORD_MIN_LENGTH", 8),
+			MaxLength:      getEnvAsInt("PASSWORD_MAX_LENGTH", 72),
+			RequireUpper:   getEnvAsBool("PASSWORD_REQUIRE_UPPER", true),
+			RequireLower:   getEnvAsBool("PASSWORD_REQUIRE_LOWER", true),
+			RequireNumber:  getEnvAsBool("PASSWORD_REQUIRE_NUMBER", true),
+			RequireSpecial: getEnvAsBool("PASSWORD_REQUIRE_SPECIAL", true),
+			RejectCommon:   getEnvAsBool("PASSWORD_REJECT_COMMON", true),
+			HistorySize:    getEnvAsInt("PASSWORD_HISTORY_SIZE", 5),
+			MaxAge:         getEnvAsDuration("PASSWORD_MAX_AGE", 90*24*time.Hour),
+		},
+		OAuth: OAuthConfig{
+			ClientTokenTTL: getEnvAsDuration("OAUTH_CLIENT_TOKEN_TTL", 15*time.Minute),
+		},
+		APIKeys: APIKeyConfig{
+			MaxLifetime: getEnvAsDuration("API_KEY_MAX_LIFETIME", 365*24*time.Hour),
+			MaxPerUser:  getEnvAsInt("API_KEY_MAX_PER_USER", 10),
+		},
+		Authz: AuthzConfig{
+			TellerCreditLimit: getEnvAsFloat("TELLER_CREDIT_LIMIT", 10000),
+		},
+		Approvals: ApprovalConfig{
+			RequestTTL: getEnvAsDuration("APPROVAL_REQUEST_TTL", 24*time.Hour),
+		},
+		Stream: StreamConfig{
+			HeartbeatInterval:     getEnvAsDuration("STREAM_HEARTBEAT_INTERVAL", 15*time.Second),
+			MaxConnectionsPerUser: getEnvAsInt("STREAM_MAX_CONNECTIONS_PER_USER", 5),
+			MaxConnections:        getEnvAsInt("STREAM_MAX_CONNECTIONS", 10000),
+			MaxConnectionDuration: getEnvAsDuration("STREAM_MAX_CONNECTION_DURATION", time.Hour),
+			SendBufferSize:        getEnvAsInt("STREAM_SEND_BUFFER_SIZE", 64),
+			ReplayBufferSize:      getEnvAsInt("STREAM_REPLAY_BUFFER_SIZE", 100),
+		},
 	}
 
 	// Validate required configurations
@@ -177,11 +421,162 @@ func (c *Config) validate() error {
 	if port, err := strconv.Atoi(c.Server.Port); err != nil || port <= 0 || port > 65535 {
 		return fmt.Errorf("invalid SERVER_PORT: %s", c.Server.Port)
 	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("invalid TRUSTED_PROXIES entry: %s", proxy)
+		}
+	}
+
+	// Job queue validation
+	if c.JobQueue.Backend != "postgres" && c.JobQueue.Backend != "memory" {
+		return fmt.Errorf("invalid JOB_QUEUE_BACKEND: %s", c.JobQueue.Backend)
+	}
+	if c.JobQueue.MaxLeasedPerUser < 0 {
+		return fmt.Errorf("JOB_QUEUE_MAX_LEASED_PER_USER must not be negative")
+	}
+
+	// Worker pool validation
+	if c.WorkerPool.MinWorkers < 1 {
+		return fmt.Errorf("WORKER_POOL_MIN_WORKERS must be at least 1")
+	}
+	if c.WorkerPool.MaxWorkers < c.WorkerPool.MinWorkers {
+		return fmt.Errorf("WORKER_POOL_MAX_WORKERS must not be less than WORKER_POOL_MIN_WORKERS")
+	}
+
+	// Currency validation
+	if len(c.App.Currency) != 3 {
+		return fmt.Errorf("CURRENCY must be a 3-letter ISO 4217 code")
+	}
+
+	// Batch validation
+	if c.Batch.MaxItems < 1 {
+		return fmt.Errorf("BATCH_MAX_ITEMS must be at least 1")
+	}
+
+	// Stream validation
+	if c.Stream.HeartbeatInterval <= 0 {
+		return fmt.Errorf("STREAM_HEARTBEAT_INTERVAL must be positive")
+	}
+	if c.Stream.MaxConnectionsPerUser < 1 || c.Stream.MaxConnections < c.Stream.MaxConnectionsPerUser {
+		return fmt.Errorf("STREAM_MAX_CONNECTIONS_PER_USER must be at least 1 and not exceed STREAM_MAX_CONNECTIONS")
+	}
+	if c.Stream.MaxConnectionDuration <= c.Stream.HeartbeatInterval {
+		return fmt.Errorf("STREAM_MAX_CONNECTION_DURATION must be longer than STREAM_HEARTBEAT_INTERVAL")
+	}
+	if c.Stream.SendBufferSize < 1 || c.Stream.ReplayBufferSize < 0 {
+		return fmt.Errorf("STREAM_SEND_BUFFER_SIZE must be at least 1 and STREAM_REPLAY_BUFFER_SIZE not negative")
+	}
+
+	// MFA validation
+	if _, err := c.MFA.Key(c.JWT.Secret); err != nil {
+		return err
+	}
+	if c.MFA.EncryptionKey == "" {
+		if c.App.Environment == "production" {
+			return fmt.Errorf("MFA_ENCRYPTION_KEY is required in production")
+		}
+		log.Println("Warning: MFA_ENCRYPTION_KEY not set, deriving TOTP secret key from JWT secret")
+	}
+	if c.MFA.ChallengeTTL <= 0 {
+		return fmt.Errorf("MFA_CHALLENGE_TTL must be positive")
+	}
+
+	// Step-up validation
+	if c.StepUp.AmountThreshold < 0 {
+		return fmt.Errorf("STEP_UP_AMOUNT_THRESHOLD cannot be negative")
+	}
+	if c.StepUp.TokenTTL <= 0 || c.StepUp.ChallengeTTL <= 0 {
+		return fmt.Errorf("STEP_UP_TOKEN_TTL and STEP_UP_CHALLENGE_TTL must be positive")
+	}
+	if c.StepUp.MaxAttempts < 1 {
+		return fmt.Errorf("STEP_UP_CHALLENGE_MAX_ATTEMPTS must be at least 1")
+	}
+
+	// Lockout validation
+	if c.Lockout.MaxUserAttempts < 1 || c.Lockout.MaxIPAttempts < 1 {
+		return fmt.Errorf("LOCKOUT_USER_MAX_ATTEMPTS and LOCKOUT_IP_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.Lockout.Duration <= 0 || c.Lockout.MaxDuration < c.Lockout.Duration {
+		return fmt.Errorf("LOCKOUT_DURATION must be positive and not exceed LOCKOUT_MAX_DURATION")
+	}
+	if c.Lockout.DelayBase < 0 || c.Lockout.MaxDelay < c.Lockout.DelayBase {
+		return fmt.Errorf("LOCKOUT_DELAY_BASE must not be negative or exceed LOCKOUT_MAX_DELAY")
+	}
+	if c.Lockout.ResetAfter <= 0 {
+		return fmt.Errorf("LOCKOUT_RESET_AFTER must be positive")
+	}
+
+	// Account token validation
+	if c.Account.PasswordResetTTL <= 0 || c.Account.EmailVerificationTTL <= 0 {
+		return fmt.Errorf("PASSWORD_RESET_TTL and EMAIL_VERIFICATION_TTL must be positive")
+	}
+	if c.Account.ResendInterval < 0 {
+		return fmt.Errorf("EMAIL_VERIFICATION_RESEND_INTERVAL must not be negative")
+	}
+
+	// Password policy validation
+	if c.Password.MinLength < 6 || c.Password.MaxLength < c.Password.MinLength {
+		return fmt.Errorf("PASSWORD_MIN_LENGTH must be at least 6 and not exceed PASSWORD_MAX_LENGTH")
+	}
+	if c.Password.MaxLength > 72 {
+		return fmt.Errorf("PASSWORD_MAX_LENGTH must not exceed 72, the bcrypt input limit")
+	}
+	if c.Password.HistorySize < 0 || c.Password.HistorySize > 24 {
+		return fmt.Errorf("PASSWORD_HISTORY_SIZE must be between 0 and 24")
+	}
+	if c.Password.MaxAge < 0 {
+		return fmt.Errorf("PASSWORD_MAX_AGE must not be negative")
+	}
+
+	// OAuth validation
+	if c.OAuth.ClientTokenTTL <= 0 || c.OAuth.ClientTokenTTL > 24*time.Hour {
+		return fmt.Errorf("OAUTH_CLIENT_TOKEN_TTL must be positive and at most 24h")
+	}
+
+	// API key validation
+	if c.APIKeys.MaxLifetime <= 0 {
+		return fmt.Errorf("API_KEY_MAX_LIFETIME must be positive")
+	}
+	if c.APIKeys.MaxPerUser < 1 {
+		return fmt.Errorf("API_KEY_MAX_PER_USER must be at least 1")
+	}
+
+	// Authorization validation
+	if c.Authz.TellerCreditLimit < 0 {
+		return fmt.Errorf("TELLER_CREDIT_LIMIT must not be negative")
+	}
+
+	// Approval validation
+	if c.Approvals.RequestTTL <= 0 || c.Approvals.RequestTTL > 30*24*time.Hour {
+		return fmt.Errorf("APPROVAL_REQUEST_TTL must be positive and at most 720h")
+	}
 
 	// JWT validation
 	if len(c.JWT.Secret) < 32 {
 		log.Println("Warning: JWT secret is shorter than 32 characters")
 	}
+	if c.JWT.AccessTokenExpiry <= 0 || c.JWT.RefreshTokenExpiry <= c.JWT.AccessTokenExpiry {
+		return fmt.Errorf("JWT_ACCESS_TOKEN_EXPIRY must be positive and shorter than JWT_REFRESH_TOKEN_EXPIRY")
+	}
+	if c.JWT.SigningAlgorithm != "RS256" && c.JWT.SigningAlgorithm != "EdDSA" {
+		return fmt.Errorf("JWT_SIGNING_ALGORITHM must be RS256 or EdDSA")
+	}
+	if _, err := c.JWT.SigningKeyEncryptionKey(); err != nil {
+		return err
+	}
+	if c.JWT.KeyEncryptionKey == "" {
+		if c.App.Environment == "production" {
+			return fmt.Errorf("JWT_KEY_ENCRYPTION_KEY is required in production")
+		}
+		log.Println("Warning: JWT_KEY_ENCRYPTION_KEY not set, deriving signing key encryption key from JWT secret")
+	}
+	if c.JWT.KeyRotationInterval < 2*time.Hour {
+		return fmt.Errorf("JWT_KEY_ROTATION_INTERVAL must be at least 2h")
+	}
+	// Tokens signed just before a rotation must verify until they expire
+	if c.JWT.KeyOverlap < c.JWT.RefreshTokenExpiry {
+		return fmt.Errorf("JWT_KEY_OVERLAP must be at least JWT_REFRESH_TOKEN_EXPIRY")
+	}
 
 	return nil
 }
@@ -214,6 +609,31 @@ func getEnvAsBool(key string, fallback bool) bool {
 	return fallback
 }
 
+// getEnvAsDuration gets environment variable as time.Duration with fallback
+func getEnvAsDuration(key string, fallback time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if durationVal, err := time.ParseDuration(value); err == nil {
+			return durationVal
+		}
+	}
+	return fallback
+}
+
+// getEnvAsList gets a comma separated environment variable as a list with fallback
+func getEnvAsList(key string, fallback []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // getEnvAsFloat gets environment variable as float64 with fallback
 func getEnvAsFloat(key string, fallback float64) float64 {
 	if value := os.Getenv(key); value != "" {
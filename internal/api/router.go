@@ -4,8 +4,11 @@ import (
 	"time"
 
 	v1 "github.com/barannkoca/banking-backend/internal/api/v1"
+	"github.com/barannkoca/banking-backend/internal/authz"
+	"github.com/barannkoca/banking-backend/internal/events"
 	"github.com/barannkoca/banking-backend/internal/interfaces"
 	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/models"
 	"github.com/barannkoca/banking-backend/internal/processing"
 	"github.com/barannkoca/banking-backend/internal/services"
 	"github.com/gin-gonic/gin"
@@ -14,10 +17,32 @@ import (
 // SetupRouter configures the main application router with all middleware and routes
 func SetupRouter(
 	userService *services.UserService,
+	tokenService *services.TokenService,
+	sessionService *services.SessionService,
+	mfaService *services.MFAService,
+	stepUpService *services.StepUpService,
+	lockoutService *services.LockoutService,
+	accountService *services.AccountService,
+	signingKeyService *services.SigningKeyService,
+	apiClientService *services.APIClientService,
+	apiKeyService *services.APIKeyService,
 	transactionService *services.TransactionService,
 	balanceService *services.BalanceService,
 	auditService interfaces.AuditService,
 	workerPool *processing.WorkerPool,
+	jobService *services.JobService,
+	deadLetterService *services.DeadLetterService,
+	batchService *services.BatchService,
+	paymentImportService *services.PaymentImportService,
+	statementService *services.StatementService,
+	searchService *services.SearchService,
+	categoryService *services.CategoryService,
+	analyticsService *services.AnalyticsService,
+	approvalService *services.ApprovalService,
+	broker *events.Broker,
+	streamHeartbeat time.Duration,
+	maxStreamDuration time.Duration,
+	stepUpAmountThreshold float64,
 ) *gin.Engine {
 	// Create router with custom configuration
 	r := gin.New()
@@ -26,10 +51,39 @@ func SetupRouter(
 	gin.SetMode(gin.ReleaseMode) // Production mode
 
 	// Initialize handlers
-	authHandler := v1.NewAuthHandler(userService)
-	userHandler := v1.NewUserHandler(userService)
-	transactionHandler := v1.NewTransactionHandler(transactionService, balanceService, auditService, workerPool)
-	balanceHandler := v1.NewBalanceHandler(balanceService)
+	authHandler := v1.NewAuthHandler(userService, tokenService, mfaService, lockoutService, accountService)
+	sessionHandler := v1.NewSessionHandler(sessionService)
+	mfaHandler := v1.NewMFAHandler(mfaService, lockoutService)
+	stepUpHandler := v1.NewStepUpHandler(stepUpService, lockoutService)
+	lockoutHandler := v1.NewLockoutHandler(lockoutService)
+	accountHandler := v1.NewAccountHandler(accountService)
+	jwksHandler := v1.NewJWKSHandler(signingKeyService)
+	apiClientHandler := v1.NewAPIClientHandler(apiClientService)
+	apiKeyHandler := v1.NewAPIKeyHandler(apiKeyService)
+	userHandler := v1.NewUserHandler(userService, approvalService)
+	transactionHandler := v1.NewTransactionHandler(transactionService, balanceService, auditService, workerPool, jobService, statementService, approvalService)
+	balanceHandler := v1.NewBalanceHandler(balanceService, statementService)
+	jobHandler := v1.NewJobHandler(jobService)
+	deadLetterHandler := v1.NewDeadLetterHandler(deadLetterService)
+	batchHandler := v1.NewBatchHandler(batchService, workerPool)
+	paymentImportHandler := v1.NewPaymentImportHandler(paymentImportService, workerPool)
+	searchHandler := v1.NewSearchHandler(searchService)
+	categoryHandler := v1.NewCategoryHandler(categoryService)
+	analyticsHandler := v1.NewAnalyticsHandler(analyticsService)
+	approvalHandler := v1.NewApprovalHandler(approvalService, transactionService, userService)
+	streamHandler := v1.NewStreamHandler(broker, streamHeartbeat, maxStreamDuration)
+
+	// Step-up authentication for sensitive operations
+	stepUp := middleware.StepUpMiddleware(stepUpService, nil)
+	stepUpAboveThreshold := middleware.StepUpMiddleware(stepUpService, middleware.AmountAboveThreshold(stepUpAmountThreshold))
+
+	// Staff read endpoints that API clients may call with the matching scope
+	permissionOrScope := func(permission authz.Permission, scope string) gin.HandlerFunc {
+		return middleware.PermissionOrClientScopeMiddleware(tokenService, apiClientService, permission, scope)
+	}
+
+	// Money-moving operations require a verified email address
+	verifiedEmail := middleware.RequireVerifiedEmail(accountService)
 
 	// Global middleware stack
 	r.Use(gin.Recovery()) // Panic recovery
@@ -56,56 +110,189 @@ func SetupRouter(
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/login/mfa", authHandler.CompleteMFALogin)
 			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/password/forgot", accountHandler.ForgotPassword)
+			auth.POST("/password/reset", accountHandler.ResetPassword)
+			auth.POST("/email/verify", accountHandler.VerifyEmail)
+		}
+
+		// Authenticated session routes
+		session := v1.Group("/auth")
+		session.Use(middleware.AuthenticationMiddleware(tokenService, nil)) // JWT only; API keys cannot manage the account
+		credentialCheck := middleware.AuthenticationRateLimitMiddleware()   // routes that verify a password or code
+		{
+			session.POST("/logout", authHandler.Logout)                                              // POST /api/v1/auth/logout
+			session.POST("/logout-all", authHandler.LogoutAll)                                       // POST /api/v1/auth/logout-all
+			session.GET("/sessions", sessionHandler.ListSessions)                                    // GET /api/v1/auth/sessions
+			session.DELETE("/sessions/:id", sessionHandler.RevokeSession)                            // DELETE /api/v1/auth/sessions/{id}
+			session.POST("/mfa/enroll", mfaHandler.Enroll)                                           // POST /api/v1/auth/mfa/enroll
+			session.POST("/mfa/verify", credentialCheck, mfaHandler.Verify)                          // POST /api/v1/auth/mfa/verify
+			session.POST("/mfa/disable", credentialCheck, mfaHandler.Disable)                        // POST /api/v1/auth/mfa/disable
+			session.POST("/mfa/recovery-codes", credentialCheck, mfaHandler.RegenerateRecoveryCodes) // POST /api/v1/auth/mfa/recovery-codes
+			session.POST("/step-up/challenge", stepUpHandler.CreateChallenge)                        // POST /api/v1/auth/step-up/challenge
+			session.POST("/step-up", credentialCheck, stepUpHandler.CompleteStepUp)                  // POST /api/v1/auth/step-up
+			session.POST("/email/verify/resend", accountHandler.ResendVerification)                  // POST /api/v1/auth/email/verify/resend
+			session.POST("/password/change", accountHandler.ChangePassword)                          // POST /api/v1/auth/password/change
+			session.POST("/api-keys", stepUp, apiKeyHandler.CreateKey)                               // POST /api/v1/auth/api-keys
+			session.GET("/api-keys", apiKeyHandler.ListKeys)                                         // GET /api/v1/auth/api-keys
+			session.DELETE("/api-keys/:id", apiKeyHandler.RevokeKey)                                 // DELETE /api/v1/auth/api-keys/{id}
 		}
 
 		// Protected routes (require authentication)
 		protected := v1.Group("")
-		protected.Use(middleware.AuthenticationMiddleware())         // JWT authentication
-		protected.Use(middleware.BankingRateLimitMiddleware())       // Banking-specific rate limiting
-		protected.Use(middleware.BankingSecurityHeadersMiddleware()) // Enhanced security for banking
-		protected.Use(middleware.BankingTrackingMiddleware())        // Enhanced tracking for banking
+		protected.Use(middleware.AuthenticationMiddleware(tokenService, apiKeyService)) // JWT or API key authentication
+		protected.Use(middleware.PasswordExpiryMiddleware(userService))                 // Forced password rotation
+		protected.Use(middleware.BankingRateLimitMiddleware())                          // Banking-specific rate limiting
+		protected.Use(middleware.BankingSecurityHeadersMiddleware())                    // Enhanced security for banking
+		protected.Use(middleware.BankingTrackingMiddleware())                           // Enhanced tracking for banking
 		{
 			// User Management Endpoints
 			users := protected.Group("/users")
+			users.Use(middleware.APIKeyScopeMiddleware(models.ScopeUsersRead, models.ScopeUsersWrite))
 			{
-				users.GET("", userHandler.GetUsers)          // GET /api/v1/users
-				users.GET("/:id", userHandler.GetUser)       // GET /api/v1/users/{id}
-				users.PUT("/:id", userHandler.UpdateUser)    // PUT /api/v1/users/{id}
-				users.DELETE("/:id", userHandler.DeleteUser) // DELETE /api/v1/users/{id}
+				users.GET("", middleware.RequirePermission(authz.UsersList), userHandler.GetUsers) // GET /api/v1/users
+				users.GET("/:id", userHandler.GetUser)                                             // GET /api/v1/users/{id}
+				users.PUT("/:id", stepUp, userHandler.UpdateUser)                                  // PUT /api/v1/users/{id}
+				users.DELETE("/:id", userHandler.DeleteUser)                                       // DELETE /api/v1/users/{id}
 			}
 
 			// Transaction Endpoints
 			transactions := protected.Group("/transactions")
+			transactions.Use(middleware.APIKeyScopeMiddleware(models.ScopeTransactionsRead, models.ScopeTransactionsWrite))
 			{
-				transactions.POST("/credit", transactionHandler.CreditTransaction)     // POST /api/v1/transactions/credit
-				transactions.POST("/debit", transactionHandler.DebitTransaction)       // POST /api/v1/transactions/debit
-				transactions.POST("/transfer", transactionHandler.TransferTransaction) // POST /api/v1/transactions/transfer
-				transactions.GET("/history", transactionHandler.GetTransactionHistory) // GET /api/v1/transactions/history
-				transactions.GET("/:id", transactionHandler.GetTransaction)            // GET /api/v1/transactions/{id}
+				transactions.POST("/credit", verifiedEmail, transactionHandler.CreditTransaction)                                 // POST /api/v1/transactions/credit
+				transactions.POST("/debit", verifiedEmail, stepUpAboveThreshold, transactionHandler.DebitTransaction)             // POST /api/v1/transactions/debit
+				transactions.POST("/transfer", verifiedEmail, stepUpAboveThreshold, transactionHandler.TransferTransaction)       // POST /api/v1/transactions/transfer
+				transactions.POST("/async/:type", verifiedEmail, stepUpAboveThreshold, transactionHandler.SubmitAsyncTransaction) // POST /api/v1/transactions/async/{credit|debit|transfer}
+				transactions.POST("/batch", verifiedEmail, stepUpAboveThreshold, batchHandler.SubmitBatch)                        // POST /api/v1/transactions/batch
+				transactions.GET("/batch/:id", batchHandler.GetBatch)                                                             // GET /api/v1/transactions/batch/{id}
+				transactions.POST("/imports", paymentImportHandler.UploadImport)                                                  // POST /api/v1/transactions/imports
+				transactions.GET("/imports/:id", paymentImportHandler.GetImport)                                                  // GET /api/v1/transactions/imports/{id}
+				transactions.POST("/imports/:id/confirm", verifiedEmail, stepUp, paymentImportHandler.ConfirmImport)              // POST /api/v1/transactions/imports/{id}/confirm
+				transactions.DELETE("/imports/:id", paymentImportHandler.CancelImport)                                            // DELETE /api/v1/transactions/imports/{id}
+				transactions.GET("/imports/:id/report", paymentImportHandler.GetImportReport)                                     // GET /api/v1/transactions/imports/{id}/report
+				transactions.GET("/history", transactionHandler.GetTransactionHistory)                                            // GET /api/v1/transactions/history
+				transactions.GET("/:id", transactionHandler.GetTransaction)                                                       // GET /api/v1/transactions/{id}
+				transactions.PUT("/:id/category", categoryHandler.SetTransactionCategory)                                         // PUT /api/v1/transactions/{id}/category
 			}
 
 			// Balance Endpoints
 			balances := protected.Group("/balances")
+			balances.Use(middleware.APIKeyScopeMiddleware(models.ScopeBalancesRead, ""))
 			{
 				balances.GET("/current", balanceHandler.GetCurrentBalance)       // GET /api/v1/balances/current
 				balances.GET("/historical", balanceHandler.GetHistoricalBalance) // GET /api/v1/balances/historical
 				balances.GET("/at-time", balanceHandler.GetBalanceAtTime)        // GET /api/v1/balances/at-time
 			}
+
+			// Category Endpoints
+			categories := protected.Group("/categories")
+			categories.Use(middleware.APIKeyScopeMiddleware(models.ScopeCategoriesRead, models.ScopeCategoriesWrite))
+			{
+				categories.GET("", categoryHandler.ListCategories)          // GET /api/v1/categories
+				categories.GET("/rules", categoryHandler.ListRules)         // GET /api/v1/categories/rules
+				categories.POST("/rules", categoryHandler.CreateRule)       // POST /api/v1/categories/rules
+				categories.PUT("/rules/:id", categoryHandler.UpdateRule)    // PUT /api/v1/categories/rules/{id}
+				categories.DELETE("/rules/:id", categoryHandler.DeleteRule) // DELETE /api/v1/categories/rules/{id}
+			}
+
+			// Analytics Endpoints
+			analytics := protected.Group("/analytics")
+			analytics.Use(middleware.APIKeyScopeMiddleware(models.ScopeAnalyticsRead, ""))
+			{
+				analytics.GET("/spending", analyticsHandler.GetSpending) // GET /api/v1/analytics/spending
+			}
+
+			// Search Endpoints
+			protected.GET("/search", middleware.APIKeyScopeMiddleware(models.ScopeTransactionsRead, ""), searchHandler.Search) // GET /api/v1/search
+
+			// Real-time Event Stream Endpoints
+			stream := protected.Group("/stream")
+			stream.Use(middleware.APIKeyScopeMiddleware(models.ScopeTransactionsRead, ""))
+			{
+				stream.GET("", streamHandler.Stream)             // GET /api/v1/stream (Server-Sent Events)
+				stream.GET("/ws", streamHandler.StreamWebSocket) // GET /api/v1/stream/ws (WebSocket)
+			}
+
+			// Async Job Endpoints
+			jobs := protected.Group("/jobs")
+			jobs.Use(middleware.APIKeyScopeMiddleware(models.ScopeTransactionsRead, ""))
+			{
+				jobs.GET("/:id", jobHandler.GetJob) // GET /api/v1/jobs/{id}
+			}
 		}
 
-		// Admin routes (require admin role)
+		// Admin routes (each requires a permission)
 		admin := v1.Group("/admin")
-		admin.Use(middleware.AuthenticationMiddleware())
-		admin.Use(middleware.AdminAuthorizationMiddleware()) // Admin role check
+		admin.Use(middleware.AuthenticationMiddleware(tokenService, nil))
+		{
+			admin.POST("/system/maintenance", middleware.RequirePermission(authz.SystemMaintain), adminSystemMaintenanceHandler)
+
+			// User session and security management
+			manageSessions := middleware.RequirePermission(authz.SessionsManage)
+			manageSecurity := middleware.RequirePermission(authz.SecurityManage)
+			admin.DELETE("/users/:id/sessions", manageSessions, sessionHandler.AdminRevokeAllSessions)         // DELETE /api/v1/admin/users/{id}/sessions
+			admin.DELETE("/users/:id/sessions/:session_id", manageSessions, sessionHandler.AdminRevokeSession) // DELETE /api/v1/admin/users/{id}/sessions/{session_id}
+			admin.DELETE("/users/:id/mfa", manageSecurity, mfaHandler.AdminReset)                              // DELETE /api/v1/admin/users/{id}/mfa
+			admin.GET("/users/:id/lockout", manageSecurity, lockoutHandler.AdminGetLockout)                    // GET /api/v1/admin/users/{id}/lockout
+			admin.DELETE("/users/:id/lockout", manageSecurity, lockoutHandler.AdminUnlock)                     // DELETE /api/v1/admin/users/{id}/lockout
+
+			// Dead-letter job management
+			deadLetters := admin.Group("/jobs/dead-letter")
+			deadLetters.Use(middleware.RequirePermission(authz.JobsManage))
+			{
+				deadLetters.GET("", deadLetterHandler.ListDeadLetters)              // GET /api/v1/admin/jobs/dead-letter
+				deadLetters.GET("/:id", deadLetterHandler.GetDeadLetter)            // GET /api/v1/admin/jobs/dead-letter/{id}
+				deadLetters.PUT("/:id", deadLetterHandler.UpdateDeadLetter)         // PUT /api/v1/admin/jobs/dead-letter/{id}
+				deadLetters.POST("/:id/replay", deadLetterHandler.ReplayDeadLetter) // POST /api/v1/admin/jobs/dead-letter/{id}/replay
+				deadLetters.DELETE("/:id", deadLetterHandler.DiscardDeadLetter)     // DELETE /api/v1/admin/jobs/dead-letter/{id}
+			}
+
+			// API client management
+			apiClients := admin.Group("/api-clients")
+			apiClients.Use(middleware.RequirePermission(authz.APIClientsManage))
+			{
+				apiClients.POST("", apiClientHandler.CreateClient)       // POST /api/v1/admin/api-clients
+				apiClients.GET("", apiClientHandler.ListClients)         // GET /api/v1/admin/api-clients
+				apiClients.DELETE("/:id", apiClientHandler.RevokeClient) // DELETE /api/v1/admin/api-clients/{id}
+			}
+
+			// Operations that run only after a second user approves them
+			refund := middleware.RequirePermission(authz.AccountsRefund)
+			freeze := middleware.RequirePermission(authz.AccountsFreeze)
+			admin.POST("/transactions/:id/refund", refund, approvalHandler.RequestRefund) // POST /api/v1/admin/transactions/{id}/refund
+			admin.POST("/accounts/:id/freeze", freeze, approvalHandler.FreezeAccount)     // POST /api/v1/admin/accounts/{id}/freeze
+			admin.POST("/accounts/:id/unfreeze", freeze, approvalHandler.UnfreezeAccount) // POST /api/v1/admin/accounts/{id}/unfreeze
+
+			// Maker-checker approval requests
+			approvals := admin.Group("/approvals")
+			approvals.Use(middleware.RequirePermission(authz.ApprovalsDecide))
+			{
+				approvals.GET("", approvalHandler.ListApprovals)                // GET /api/v1/admin/approvals
+				approvals.GET("/:id", approvalHandler.GetApproval)              // GET /api/v1/admin/approvals/{id}
+				approvals.POST("/:id/approve", stepUp, approvalHandler.Approve) // POST /api/v1/admin/approvals/{id}/approve
+				approvals.POST("/:id/reject", approvalHandler.Reject)           // POST /api/v1/admin/approvals/{id}/reject
+			}
+		}
+
+		// Admin read routes, also open to API clients holding the route's scope. Each route
+		// authenticates itself, so a client token is never accepted by other admin routes.
+		adminRead := v1.Group("/admin")
 		{
-			admin.GET("/users", adminGetUsersHandler)
-			admin.GET("/transactions", adminGetTransactionsHandler)
-			admin.GET("/audit-logs", adminGetAuditLogsHandler)
-			admin.POST("/system/maintenance", adminSystemMaintenanceHandler)
+			adminRead.GET("/users", permissionOrScope(authz.UsersList, models.ScopeUsersRead), adminGetUsersHandler)
+			adminRead.GET("/transactions", permissionOrScope(authz.AccountsRead, models.ScopeTransactionsRead), adminGetTransactionsHandler)
+			adminRead.GET("/audit-logs", permissionOrScope(authz.AuditRead, models.ScopeAuditRead), adminGetAuditLogsHandler)
+			adminRead.GET("/users/:id/sessions", permissionOrScope(authz.SessionsManage, models.ScopeSessionsRead), sessionHandler.AdminListSessions) // GET /api/v1/admin/users/{id}/sessions
 		}
 	}
 
+	// Public keys for verifying issued tokens (RFC 7517)
+	r.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
+	// OAuth2 client credentials token endpoint for API clients
+	r.POST("/oauth/token", middleware.AuthenticationRateLimitMiddleware(), apiClientHandler.IssueToken)
+
 	// Health check endpoints
 	health := r.Group("/health")
 	{
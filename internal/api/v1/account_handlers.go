@@ -0,0 +1,190 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AccountHandler handles password change, password reset and email verification requests
+type AccountHandler struct {
+	accountService *services.AccountService
+}
+
+// NewAccountHandler creates a new AccountHandler instance
+func NewAccountHandler(accountService *services.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// ForgotPassword handles POST /api/v1/auth/password/forgot. The response is the same
+// whether or not the address belongs to a user.
+func (h *AccountHandler) ForgotPassword(c *gin.Context) {
+	var req models.PasswordForgotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"message": "Geçerli bir e-posta adresi gerekli",
+		})
+		return
+	}
+
+	if err := h.accountService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		// Still answer 202: an error here would reveal that the address exists
+		logger.GetLogger().Error("Password reset request failed",
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "password_reset_error"),
+		)
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "E-posta adresi kayıtlıysa şifre sıfırlama bağlantısı gönderildi",
+	})
+}
+
+// ResetPassword handles POST /api/v1/auth/password/reset
+func (h *AccountHandler) ResetPassword(c *gin.Context) {
+	var req models.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"message": "Token ve yeni şifre gerekli",
+		})
+		return
+	}
+
+	if err := h.accountService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Şifre sıfırlandı, tüm oturumlar kapatıldı",
+	})
+}
+
+// ChangePassword handles POST /api/v1/auth/password/change
+func (h *AccountHandler) ChangePassword(c *gin.Context) {
+	token, ok := currentToken(c)
+	if !ok {
+		return
+	}
+
+	var req models.PasswordChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"message": "Mevcut ve yeni şifre gerekli",
+		})
+		return
+	}
+
+	revoked, err := h.accountService.ChangePassword(c.Request.Context(), token.userID, token.sessionID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Şifre değiştirildi, diğer oturumlar kapatıldı",
+		"sessions_revoked": revoked,
+	})
+}
+
+// VerifyEmail handles POST /api/v1/auth/email/verify
+func (h *AccountHandler) VerifyEmail(c *gin.Context) {
+	var req models.EmailVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"message": "Doğrulama token'ı gerekli",
+		})
+		return
+	}
+
+	user, err := h.accountService.VerifyEmail(c.Request.Context(), req.Token)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "E-posta adresi doğrulandı",
+		"data":    user.ToResponse(),
+	})
+}
+
+// ResendVerification handles POST /api/v1/auth/email/verify/resend
+func (h *AccountHandler) ResendVerification(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.accountService.SendVerification(c.Request.Context(), userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Doğrulama e-postası gönderildi",
+	})
+}
+
+// respondError maps account service errors to HTTP responses
+func (h *AccountHandler) respondError(c *gin.Context, err error) {
+	var policyErr *services.PasswordPolicyError
+	switch {
+	case errors.As(err, &policyErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Password policy violation",
+			"message": policyErr.Reason,
+		})
+	case errors.Is(err, services.ErrInvalidCurrentPassword):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid current password",
+			"message": "Mevcut şifre hatalı",
+		})
+	case errors.Is(err, services.ErrInvalidAccountToken):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid token",
+			"message": "Bağlantı geçersiz, kullanılmış veya süresi dolmuş",
+		})
+	case errors.Is(err, services.ErrEmailAlreadyVerified):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Email already verified",
+			"message": "E-posta adresi zaten doğrulanmış",
+		})
+	case errors.Is(err, services.ErrVerificationRecentlySent):
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":   "Verification recently sent",
+			"message": "Doğrulama e-postası kısa süre önce gönderildi, lütfen bekleyin",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "User not found",
+			"message": "Kullanıcı bulunamadı",
+		})
+	default:
+		middleware.IncrementErrorCount(c)
+
+		logger.GetLogger().Error("Account operation failed",
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "account_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Operation failed",
+			"message": "İşlem başarısız oldu",
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AnalyticsHandler handles spending analytics requests
+type AnalyticsHandler struct {
+	analyticsService *services.AnalyticsService
+}
+
+// NewAnalyticsHandler creates a new AnalyticsHandler instance
+func NewAnalyticsHandler(analyticsService *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetSpending handles GET /api/v1/analytics/spending
+func (h *AnalyticsHandler) GetSpending(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	period := models.SpendingPeriod(c.DefaultQuery("period", string(models.SpendingPeriodMonth)))
+	at := time.Now()
+	if value := c.Query("date"); value != "" {
+		parsed, ok := parseDateOrTime(value, at, false)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid date parameter, use YYYY-MM-DD or RFC3339",
+				"message": "Geçersiz tarih",
+			})
+			return
+		}
+		at = parsed
+	}
+
+	report, err := h.analyticsService.Spending(c.Request.Context(), userID, period, at)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSpendingPeriod) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   err.Error(),
+				"message": "Geçersiz dönem",
+			})
+			return
+		}
+
+		middleware.IncrementErrorCount(c)
+
+		logger.GetLogger().Error("Failed to get spending analytics",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+			zap.String("type", "spending_analytics_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve spending analytics",
+			"message": "Harcama analizi alınamadı",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Harcama analizi başarıyla getirildi",
+		"data":    report,
+	})
+}
@@ -0,0 +1,190 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// APIClientHandler handles API client registration and the OAuth2 token endpoint
+type APIClientHandler struct {
+	apiClientService *services.APIClientService
+}
+
+// NewAPIClientHandler creates a new APIClientHandler instance
+func NewAPIClientHandler(apiClientService *services.APIClientService) *APIClientHandler {
+	return &APIClientHandler{
+		apiClientService: apiClientService,
+	}
+}
+
+// IssueToken handles POST /oauth/token with the client credentials grant (RFC 6749
+// section 4.4). Credentials are accepted with HTTP Basic authentication or in the form body.
+func (h *APIClientHandler) IssueToken(c *gin.Context) {
+	// Token responses must not be cached (RFC 6749 section 5.1)
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	if grantType := c.PostForm("grant_type"); grantType != "client_credentials" {
+		oauthError(c, http.StatusBadRequest, "unsupported_grant_type", "Yalnızca client_credentials desteklenir")
+		return
+	}
+
+	clientID, secret, ok := c.Request.BasicAuth()
+	if !ok {
+		clientID, secret = c.PostForm("client_id"), c.PostForm("client_secret")
+	}
+	if clientID == "" || secret == "" {
+		c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		oauthError(c, http.StatusUnauthorized, "invalid_client", "İstemci kimlik bilgileri gerekli")
+		return
+	}
+
+	token, err := h.apiClientService.IssueToken(c.Request.Context(), clientID, secret, strings.Fields(c.PostForm("scope")))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidClient):
+			logger.GetLogger().Warn("API client authentication failed",
+				zap.String("client_id", clientID),
+				zap.String("ip", c.ClientIP()),
+				zap.String("type", "oauth_invalid_client"),
+			)
+			c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+			oauthError(c, http.StatusUnauthorized, "invalid_client", "İstemci kimlik bilgileri geçersiz")
+		case errors.Is(err, services.ErrInvalidScope):
+			oauthError(c, http.StatusBadRequest, "invalid_scope", "İstenen yetki geçersiz veya istemciye tanımlı değil")
+		default:
+			middleware.IncrementErrorCount(c)
+
+			logger.GetLogger().Error("Client token issuance failed",
+				zap.String("client_id", clientID),
+				zap.Error(err),
+				zap.String("type", "oauth_error"),
+			)
+			oauthError(c, http.StatusInternalServerError, "server_error", "Token oluşturulamadı")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token": token.AccessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(token.ExpiresIn.Seconds()),
+		"scope":        strings.Join(token.Scopes, " "),
+	})
+}
+
+// CreateClient handles POST /api/v1/admin/api-clients
+func (h *APIClientHandler) CreateClient(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.APIClientCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"message": "İstemci adı ve en az bir yetki gerekli",
+		})
+		return
+	}
+
+	client, secret, err := h.apiClientService.CreateClient(c.Request.Context(), adminID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "API istemcisi oluşturuldu, istemci sırrını güvenli bir yerde saklayın; tekrar gösterilmeyecek",
+		"data": models.APIClientCreateResponse{
+			APIClientResponse: client.ToResponse(),
+			ClientSecret:      secret,
+		},
+	})
+}
+
+// ListClients handles GET /api/v1/admin/api-clients
+func (h *APIClientHandler) ListClients(c *gin.Context) {
+	clients, err := h.apiClientService.ListClients(c.Request.Context())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	responses := make([]models.APIClientResponse, len(clients))
+	for i := range clients {
+		responses[i] = clients[i].ToResponse()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API istemcileri başarıyla getirildi",
+		"data":    responses,
+	})
+}
+
+// RevokeClient handles DELETE /api/v1/admin/api-clients/{id}
+func (h *APIClientHandler) RevokeClient(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.apiClientService.RevokeClient(c.Request.Context(), adminID, id); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API istemcisi iptal edildi",
+	})
+}
+
+// respondError maps API client service errors to HTTP responses
+func (h *APIClientHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidScope):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid scope",
+			"message": "Geçersiz yetki: " + strings.Join(models.APIClientScopes, ", ") + " kullanılabilir",
+		})
+	case errors.Is(err, services.ErrAPIClientNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "API client not found",
+			"message": "API istemcisi bulunamadı veya zaten iptal edilmiş",
+		})
+	default:
+		middleware.IncrementErrorCount(c)
+
+		logger.GetLogger().Error("API client operation failed",
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "api_client_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Operation failed",
+			"message": "İşlem başarısız oldu",
+		})
+	}
+}
+
+// oauthError writes an RFC 6749 error response with a Turkish message alongside
+func oauthError(c *gin.Context, status int, code, message string) {
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": message,
+	})
+}
@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// APIKeyHandler handles personal API key management
+type APIKeyHandler struct {
+	apiKeyService *services.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler instance
+func NewAPIKeyHandler(apiKeyService *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateKey handles POST /api/v1/auth/api-keys
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.APIKeyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"message": "Anahtar adı ve en az bir yetki gerekli",
+		})
+		return
+	}
+
+	apiKey, key, err := h.apiKeyService.CreateKey(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "API anahtarı oluşturuldu, anahtarı güvenli bir yerde saklayın; tekrar gösterilmeyecek",
+		"data": models.APIKeyCreateResponse{
+			APIKeyResponse: apiKey.ToResponse(),
+			Key:            key,
+		},
+	})
+}
+
+// ListKeys handles GET /api/v1/auth/api-keys
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	responses := make([]models.APIKeyResponse, len(keys))
+	for i := range keys {
+		responses[i] = keys[i].ToResponse()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API anahtarları başarıyla getirildi",
+		"data":    responses,
+	})
+}
+
+// RevokeKey handles DELETE /api/v1/auth/api-keys/{id}
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.apiKeyService.RevokeKey(c.Request.Context(), userID, id); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API anahtarı iptal edildi",
+	})
+}
+
+// respondError maps API key service errors to HTTP responses
+func (h *APIKeyHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidScope):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid scope",
+			"message": "Geçersiz yetki: " + strings.Join(models.APIKeyScopes, ", ") + " kullanılabilir",
+		})
+	case errors.Is(err, services.ErrInvalidIPAllowList):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid IP allow-list",
+			"message": "İzin verilen adresler geçerli IP adresi veya CIDR olmalıdır",
+		})
+	case errors.Is(err, services.ErrInvalidAPIKeyExpiry):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid expiry",
+			"message": "Son kullanma tarihi gelecekte ve izin verilen azami süre içinde olmalıdır",
+		})
+	case errors.Is(err, services.ErrAPIKeyLimitReached):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "API key limit reached",
+			"message": "Aktif API anahtarı sınırına ulaşıldı, kullanılmayan bir anahtarı iptal edin",
+		})
+	case errors.Is(err, services.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "API key not found",
+			"message": "API anahtarı bulunamadı veya zaten iptal edilmiş",
+		})
+	default:
+		middleware.IncrementErrorCount(c)
+
+		logger.GetLogger().Error("API key operation failed",
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "api_key_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Operation failed",
+			"message": "İşlem başarısız oldu",
+		})
+	}
+}
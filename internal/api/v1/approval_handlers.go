@@ -0,0 +1,337 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/barannkoca/banking-backend/internal/authz"
+	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ApprovalHandler handles maker-checker approval requests and the operations that need them
+type ApprovalHandler struct {
+	approvalService    *services.ApprovalService
+	transactionService *services.TransactionService
+	userService        *services.UserService
+}
+
+// NewApprovalHandler creates a new ApprovalHandler instance
+func NewApprovalHandler(approvalService *services.ApprovalService, transactionService *services.TransactionService, userService *services.UserService) *ApprovalHandler {
+	return &ApprovalHandler{
+		approvalService:    approvalService,
+		transactionService: transactionService,
+		userService:        userService,
+	}
+}
+
+// ListApprovals handles GET /api/v1/admin/approvals
+func (h *ApprovalHandler) ListApprovals(c *gin.Context) {
+	status := c.DefaultQuery("status", string(models.ApprovalStatusPending))
+	if status == "all" {
+		status = ""
+	} else if !models.IsValidApprovalStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid status",
+			"message": "Geçersiz onay durumu",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	requests, err := h.approvalService.ListRequests(c.Request.Context(), models.ApprovalStatus(status), limit, offset)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	responses := make([]models.ApprovalRequestResponse, len(requests))
+	for i := range requests {
+		responses[i] = requests[i].ToResponse()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Onay talepleri başarıyla getirildi",
+		"data":    responses,
+		"pagination": gin.H{
+			"limit":  limit,
+			"offset": offset,
+			"count":  len(responses),
+		},
+	})
+}
+
+// GetApproval handles GET /api/v1/admin/approvals/{id}
+func (h *ApprovalHandler) GetApproval(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.approvalService.GetRequest(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Onay talebi başarıyla getirildi",
+		"data":    request.ToResponse(),
+	})
+}
+
+// Approve handles POST /api/v1/admin/approvals/{id}/approve. The operation runs at once;
+// if it fails the request is closed as failed and the error is returned. Credits and refunds
+// are queued as a job instead, answered with 202 and the job to poll.
+func (h *ApprovalHandler) Approve(c *gin.Context) {
+	checker, id, note, ok := h.parseDecision(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.approvalService.Approve(c.Request.Context(), checker, id, note)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	if request.Status == models.ApprovalStatusFailed {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Approved operation failed",
+			"message": "Talep onaylandı ancak işlem gerçekleştirilemedi",
+			"data":    request.ToResponse(),
+		})
+		return
+	}
+	if request.JobID != nil {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Talep onaylandı, işlem kuyruğa alındı",
+			"data":    request.ToResponse(),
+			"job_id":  request.JobID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Talep onaylandı ve işlem gerçekleştirildi",
+		"data":    request.ToResponse(),
+	})
+}
+
+// Reject handles POST /api/v1/admin/approvals/{id}/reject
+func (h *ApprovalHandler) Reject(c *gin.Context) {
+	checker, id, note, ok := h.parseDecision(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.approvalService.Reject(c.Request.Context(), checker, id, note)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Talep reddedildi",
+		"data":    request.ToResponse(),
+	})
+}
+
+// RequestRefund handles POST /api/v1/admin/transactions/{id}/refund. The refund runs once
+// another user approves it.
+func (h *ApprovalHandler) RequestRefund(c *gin.Context) {
+	makerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	transactionID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	reason, ok := bindApprovalReason(c)
+	if !ok {
+		return
+	}
+
+	transaction, err := h.transactionService.GetTransactionByID(c.Request.Context(), transactionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transaction not found",
+			"message": "İşlem bulunamadı",
+		})
+		return
+	}
+
+	// The refund returns money to whoever paid; deposits are taken back from the receiver
+	subjectID := transaction.ToUserID
+	if transaction.FromUserID != nil {
+		subjectID = transaction.FromUserID
+	}
+	if !transaction.IsRefundable() || subjectID == nil {
+		h.respondError(c, services.ErrTransactionNotRefundable)
+		return
+	}
+
+	request, err := h.approvalService.Submit(c.Request.Context(), makerID, models.ApprovalTypeRefund, *subjectID,
+		models.RefundPayload{TransactionID: transactionID}, reason)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	respondApprovalRequested(c, request, "İade talebi oluşturuldu, ikinci bir yetkilinin onayı bekleniyor")
+}
+
+// FreezeAccount handles POST /api/v1/admin/accounts/{id}/freeze
+func (h *ApprovalHandler) FreezeAccount(c *gin.Context) {
+	h.requestFreeze(c, true)
+}
+
+// UnfreezeAccount handles POST /api/v1/admin/accounts/{id}/unfreeze
+func (h *ApprovalHandler) UnfreezeAccount(c *gin.Context) {
+	h.requestFreeze(c, false)
+}
+
+// requestFreeze submits freezing or unfreezing an account for approval
+func (h *ApprovalHandler) requestFreeze(c *gin.Context, frozen bool) {
+	makerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	accountID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	reason, ok := bindApprovalReason(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.userService.GetUserByID(c.Request.Context(), accountID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Account not found",
+			"message": "Hesap bulunamadı",
+		})
+		return
+	}
+
+	request, err := h.approvalService.Submit(c.Request.Context(), makerID, models.ApprovalTypeAccountFreeze, accountID,
+		models.AccountFreezePayload{AccountID: accountID, Frozen: frozen}, reason)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	message := "Hesap dondurma talebi oluşturuldu, ikinci bir yetkilinin onayı bekleniyor"
+	if !frozen {
+		message = "Hesap dondurma kaldırma talebi oluşturuldu, ikinci bir yetkilinin onayı bekleniyor"
+	}
+	respondApprovalRequested(c, request, message)
+}
+
+// parseDecision reads the checker, the request ID and the optional decision note
+func (h *ApprovalHandler) parseDecision(c *gin.Context) (authz.Subject, uuid.UUID, string, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return authz.Subject{}, uuid.Nil, "", false
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return authz.Subject{}, uuid.Nil, "", false
+	}
+
+	var req models.ApprovalDecisionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"message": "Not en fazla 500 karakter olabilir",
+			})
+			return authz.Subject{}, uuid.Nil, "", false
+		}
+	}
+	return currentSubject(c, userID), id, req.Note, true
+}
+
+// bindApprovalReason reads the maker's reason for an operation needing approval
+func bindApprovalReason(c *gin.Context) (string, bool) {
+	var req models.ApprovalReasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"message": "Talep gerekçesi gerekli (5-500 karakter)",
+		})
+		return "", false
+	}
+	return req.Reason, true
+}
+
+// respondApprovalRequested responds 202 for an operation waiting for approval
+func respondApprovalRequested(c *gin.Context, request *models.ApprovalRequest, message string) {
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": message,
+		"data":    request.ToResponse(),
+	})
+}
+
+// respondError maps approval service errors to HTTP responses
+func (h *ApprovalHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrApprovalNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Approval request not found",
+			"message": "Onay talebi bulunamadı",
+		})
+	case errors.Is(err, services.ErrApprovalNotPending):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Approval request already decided",
+			"message": "Onay talebi zaten sonuçlandırılmış",
+		})
+	case errors.Is(err, services.ErrApprovalExpired):
+		c.JSON(http.StatusGone, gin.H{
+			"error":   "Approval request expired",
+			"message": "Onay talebinin süresi dolmuş",
+		})
+	case errors.Is(err, services.ErrSelfApproval):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Cannot decide own request",
+			"message": "Kendi oluşturduğunuz veya sizi ilgilendiren bir talebi onaylayamaz veya reddedemezsiniz",
+		})
+	case errors.Is(err, authz.ErrPermissionDenied):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Access denied",
+			"message": "Bu talebi sonuçlandırma yetkiniz yok",
+		})
+	case errors.Is(err, services.ErrTransactionNotRefundable):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Transaction not refundable",
+			"message": "Yalnızca tamamlanmış ve iade edilmemiş para yatırma, çekme ve transfer işlemleri iade edilebilir",
+		})
+	default:
+		middleware.IncrementErrorCount(c)
+
+		logger.GetLogger().Error("Approval operation failed",
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "approval_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Operation failed",
+			"message": "İşlem başarısız oldu",
+		})
+	}
+}
@@ -1,27 +1,37 @@
 package v1
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/barannkoca/banking-backend/internal/models"
 	"github.com/barannkoca/banking-backend/internal/services"
 	"github.com/barannkoca/banking-backend/pkg/logger"
-	"github.com/barannkoca/banking-backend/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // AuthHandler handles authentication-related requests
 type AuthHandler struct {
-	userService *services.UserService
-	jwtConfig   *utils.JWTConfig
+	userService    *services.UserService
+	tokenService   *services.TokenService
+	mfaService     *services.MFAService
+	lockoutService *services.LockoutService
+	accountService *services.AccountService
 }
 
 // NewAuthHandler creates a new AuthHandler instance
-func NewAuthHandler(userService *services.UserService) *AuthHandler {
+func NewAuthHandler(userService *services.UserService, tokenService *services.TokenService, mfaService *services.MFAService, lockoutService *services.LockoutService, accountService *services.AccountService) *AuthHandler {
 	return &AuthHandler{
-		userService: userService,
-		jwtConfig:   utils.DefaultJWTConfig(),
+		userService:    userService,
+		tokenService:   tokenService,
+		mfaService:     mfaService,
+		lockoutService: lockoutService,
+		accountService: accountService,
 	}
 }
 
@@ -50,7 +60,6 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		Username: req.Username,
 		Email:    req.Email,
 		Password: req.Password,
-		Role:     req.Role,
 	}
 
 	// Create user
@@ -64,6 +73,16 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			zap.String("type", "auth_creation_error"),
 		)
 
+		var policyErr *services.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			c.JSON(http.StatusBadRequest, models.NewAuthError(
+				"Password policy violation",
+				policyErr.Reason,
+				"WEAK_PASSWORD",
+			))
+			return
+		}
+
 		// Handle specific errors
 		switch err.Error() {
 		case "email already exists":
@@ -88,25 +107,22 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Generate tokens
-	accessToken, err := utils.GenerateAccessToken(user, h.jwtConfig)
-	if err != nil {
-		logger.GetLogger().Error("Access token generation failed",
+	// Registration succeeds even if the email cannot be sent; the user can request a new one
+	if err := h.accountService.SendVerification(c.Request.Context(), user.ID); err != nil {
+		logger.GetLogger().Error("Verification email failed",
 			zap.String("user_id", user.ID.String()),
 			zap.Error(err),
-			zap.String("type", "token_generation_error"),
+			zap.String("type", "email_verification_error"),
 		)
-		c.JSON(http.StatusInternalServerError, models.NewAuthError(
-			"Token generation failed",
-			"Token oluşturma hatası",
-			"TOKEN_ERROR",
-		))
-		return
 	}
 
-	refreshToken, err := utils.GenerateRefreshToken(user, h.jwtConfig)
+	// Generate tokens in a new session
+	tokens, err := h.tokenService.IssueTokens(c.Request.Context(), user, &models.SessionClient{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	})
 	if err != nil {
-		logger.GetLogger().Error("Refresh token generation failed",
+		logger.GetLogger().Error("Token generation failed",
 			zap.String("user_id", user.ID.String()),
 			zap.Error(err),
 			zap.String("type", "token_generation_error"),
@@ -121,9 +137,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	// Create response
 	response := models.NewAuthResponse(
-		accessToken,
-		refreshToken,
-		int64(h.jwtConfig.AccessTokenExpiry.Seconds()),
+		tokens.AccessToken,
+		tokens.RefreshToken,
+		tokens.ExpiresIn,
 		user,
 	)
 
@@ -160,6 +176,13 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	// Delayed or locked accounts and IPs are rejected before the password is checked; the
+	// attempt counts as failed unless the password is right
+	accountID := h.lockoutService.ResolveUserID(c.Request.Context(), req.UsernameOrEmail)
+	if !h.beginLoginAttempt(c, accountID) {
+		return
+	}
+
 	// Authenticate user
 	user, err := h.userService.AuthenticateUser(c.Request.Context(), req.UsernameOrEmail, req.Password)
 	if err != nil {
@@ -178,10 +201,91 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Generate tokens
-	accessToken, err := utils.GenerateAccessToken(user, h.jwtConfig)
+	h.refundLoginAttempt(c, accountID)
+
+	// Users with two-factor authentication get a challenge instead of tokens
+	if user.MFAEnabled {
+		h.respondMFAChallenge(c, user)
+		return
+	}
+
+	h.respondLogin(c, user, req.DeviceName)
+}
+
+// CompleteMFALogin handles the second login step with a TOTP or recovery code
+func (h *AuthHandler) CompleteMFALogin(c *gin.Context) {
+	var req models.MFALoginRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.GetLogger().Warn("Invalid MFA login request",
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "auth_validation_error"),
+		)
+
+		c.JSON(http.StatusBadRequest, models.NewAuthError(
+			"Validation failed",
+			"Geçersiz giriş verileri",
+			"VALIDATION_ERROR",
+		))
+		return
+	}
+
+	// Wrong codes count against the account like wrong passwords
+	accountID := h.mfaService.ChallengeUserID(req.MFAToken)
+	if !h.beginLoginAttempt(c, accountID) {
+		return
+	}
+
+	user, method, err := h.mfaService.CompleteChallenge(c.Request.Context(), req.MFAToken, req.Code)
+	if !errors.Is(err, services.ErrInvalidMFACode) {
+		h.refundLoginAttempt(c, accountID)
+	}
+	if err != nil {
+		logger.GetLogger().Warn("MFA login failed",
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "auth_mfa_failed"),
+		)
+
+		switch {
+		case errors.Is(err, services.ErrInvalidMFACode):
+			c.JSON(http.StatusUnauthorized, models.NewAuthError(
+				"Invalid MFA code",
+				"Geçersiz doğrulama kodu",
+				"INVALID_MFA_CODE",
+			))
+		case errors.Is(err, services.ErrInvalidMFAChallenge), errors.Is(err, services.ErrMFANotEnabled):
+			c.JSON(http.StatusUnauthorized, models.NewAuthError(
+				"Invalid MFA challenge",
+				"Doğrulama oturumu geçersiz veya süresi dolmuş, lütfen tekrar giriş yapın",
+				"INVALID_MFA_CHALLENGE",
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewAuthError(
+				"MFA verification failed",
+				"İki faktörlü doğrulama hatası",
+				"MFA_ERROR",
+			))
+		}
+		return
+	}
+
+	logger.GetLogger().Info("MFA challenge completed",
+		zap.String("user_id", user.ID.String()),
+		zap.String("method", string(method)),
+		zap.String("type", "auth_mfa_success"),
+	)
+
+	h.respondLogin(c, user, req.DeviceName)
+}
+
+// respondMFAChallenge responds to the password step of a login that needs a second factor
+func (h *AuthHandler) respondMFAChallenge(c *gin.Context, user *models.User) {
+	mfaToken, expiresIn, err := h.mfaService.CreateChallenge(user)
 	if err != nil {
-		logger.GetLogger().Error("Access token generation failed",
+		logger.GetLogger().Error("MFA challenge generation failed",
 			zap.String("user_id", user.ID.String()),
 			zap.Error(err),
 			zap.String("type", "token_generation_error"),
@@ -194,9 +298,112 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	refreshToken, err := utils.GenerateRefreshToken(user, h.jwtConfig)
+	logger.GetLogger().Info("Password verified, MFA required",
+		zap.String("user_id", user.ID.String()),
+		zap.String("ip", c.ClientIP()),
+		zap.String("type", "auth_mfa_required"),
+	)
+
+	c.JSON(http.StatusOK, models.NewAuthSuccess(
+		"İki faktörlü doğrulama gerekli",
+		&models.MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+			ExpiresIn:   expiresIn,
+		},
+	))
+}
+
+// beginLoginAttempt counts a login attempt against the account (if known) and client IP, or
+// rejects it if either is throttled
+func (h *AuthHandler) beginLoginAttempt(c *gin.Context, accountID *uuid.UUID) bool {
+	return beginAttempt(c, h.lockoutService, accountID, c.ClientIP())
+}
+
+// refundLoginAttempt hands back a login attempt that did not fail on wrong credentials
+func (h *AuthHandler) refundLoginAttempt(c *gin.Context, accountID *uuid.UUID) {
+	refundAttempt(c, h.lockoutService, accountID, c.ClientIP())
+}
+
+// beginAttempt counts a credential attempt before it is verified, or rejects it with 429
+// (progressive delay) or 423 (lockout) if the account or IP address is throttled. An empty
+// ipAddress counts against the account only.
+func beginAttempt(c *gin.Context, lockoutService *services.LockoutService, accountID *uuid.UUID, ipAddress string) bool {
+	err := lockoutService.Attempt(c.Request.Context(), accountID, ipAddress)
+	if err == nil {
+		return true
+	}
+
+	var throttled *services.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		logger.GetLogger().Error("Login throttle check failed",
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "auth_lockout_error"),
+		)
+		c.JSON(http.StatusServiceUnavailable, models.NewAuthError(
+			"Authentication unavailable",
+			"Kimlik doğrulama şu anda yapılamıyor",
+			"AUTH_UNAVAILABLE",
+		))
+		return false
+	}
+
+	logger.GetLogger().Warn("Login attempt throttled",
+		zap.String("scope", string(throttled.Scope)),
+		zap.Bool("locked", throttled.Locked),
+		zap.Duration("retry_after", throttled.RetryAfter),
+		zap.String("ip", c.ClientIP()),
+		zap.String("type", "auth_throttled"),
+	)
+
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	if throttled.Locked {
+		c.JSON(http.StatusLocked, models.NewAuthError(
+			"Account temporarily locked",
+			"Çok fazla başarısız giriş denemesi, hesap geçici olarak kilitlendi",
+			"ACCOUNT_LOCKED",
+		))
+	} else {
+		c.JSON(http.StatusTooManyRequests, models.NewAuthError(
+			"Too many failed attempts",
+			"Çok fazla başarısız deneme, lütfen biraz bekleyin",
+			"LOGIN_DELAYED",
+		))
+	}
+	return false
+}
+
+// refundAttempt hands back an attempt counted by beginAttempt whose credentials were right
+func refundAttempt(c *gin.Context, lockoutService *services.LockoutService, accountID *uuid.UUID, ipAddress string) {
+	if err := lockoutService.Refund(c.Request.Context(), accountID, ipAddress); err != nil {
+		logger.GetLogger().Error("Failed to refund login attempt",
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "auth_lockout_error"),
+		)
+	}
+}
+
+// respondLogin starts a new session for an authenticated user and responds with its tokens
+func (h *AuthHandler) respondLogin(c *gin.Context, user *models.User, deviceName string) {
+	// The login completed all steps; earlier failures of the account no longer count
+	if err := h.lockoutService.RecordSuccess(c.Request.Context(), user.ID); err != nil {
+		logger.GetLogger().Error("Failed to reset login failures",
+			zap.String("user_id", user.ID.String()),
+			zap.Error(err),
+			zap.String("type", "auth_lockout_error"),
+		)
+	}
+
+	// Generate tokens in a new session
+	tokens, err := h.tokenService.IssueTokens(c.Request.Context(), user, &models.SessionClient{
+		Device:    deviceName,
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	})
 	if err != nil {
-		logger.GetLogger().Error("Refresh token generation failed",
+		logger.GetLogger().Error("Token generation failed",
 			zap.String("user_id", user.ID.String()),
 			zap.Error(err),
 			zap.String("type", "token_generation_error"),
@@ -211,9 +418,9 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	// Create response
 	response := models.NewAuthResponse(
-		accessToken,
-		refreshToken,
-		int64(h.jwtConfig.AccessTokenExpiry.Seconds()),
+		tokens.AccessToken,
+		tokens.RefreshToken,
+		tokens.ExpiresIn,
 		user,
 	)
 
@@ -250,8 +457,8 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// Refresh token pair
-	accessToken, refreshToken, err := utils.RefreshTokenPair(req.RefreshToken, h.jwtConfig)
+	// Rotate the refresh token; the presented one cannot be used again
+	tokens, err := h.tokenService.Refresh(c.Request.Context(), req.RefreshToken, c.ClientIP())
 	if err != nil {
 		logger.GetLogger().Warn("Token refresh failed",
 			zap.String("ip", c.ClientIP()),
@@ -259,19 +466,34 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 			zap.String("type", "token_refresh_error"),
 		)
 
-		c.JSON(http.StatusUnauthorized, models.NewAuthError(
-			"Invalid refresh token",
-			"Geçersiz refresh token",
-			"INVALID_REFRESH_TOKEN",
-		))
+		switch {
+		case errors.Is(err, services.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, models.NewAuthError(
+				"Refresh token reuse detected",
+				"Refresh token daha önce kullanılmış, oturum sonlandırıldı",
+				"REFRESH_TOKEN_REUSED",
+			))
+		case errors.Is(err, services.ErrInvalidRefreshToken):
+			c.JSON(http.StatusUnauthorized, models.NewAuthError(
+				"Invalid refresh token",
+				"Geçersiz refresh token",
+				"INVALID_REFRESH_TOKEN",
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewAuthError(
+				"Token refresh failed",
+				"Token yenileme hatası",
+				"TOKEN_ERROR",
+			))
+		}
 		return
 	}
 
 	// Create response
 	response := models.NewAuthRefreshResponse(
-		accessToken,
-		refreshToken,
-		int64(h.jwtConfig.AccessTokenExpiry.Seconds()),
+		tokens.AccessToken,
+		tokens.RefreshToken,
+		tokens.ExpiresIn,
 	)
 
 	logger.GetLogger().Info("Token refreshed successfully",
@@ -285,21 +507,30 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	))
 }
 
-// Logout handles user logout (optional - for token blacklisting)
+// Logout handles user logout: the current session and access token are revoked
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// Get user from context
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.NewAuthError(
-			"Authentication required",
-			"Kimlik doğrulama gerekli",
-			"AUTH_REQUIRED",
+	token, ok := currentToken(c)
+	if !ok {
+		return
+	}
+
+	if err := h.tokenService.Logout(c.Request.Context(), token.userID, token.sessionID, token.tokenID, token.expiresAt); err != nil {
+		logger.GetLogger().Error("Logout failed",
+			zap.String("user_id", token.userID.String()),
+			zap.Error(err),
+			zap.String("type", "auth_logout_error"),
+		)
+		c.JSON(http.StatusInternalServerError, models.NewAuthError(
+			"Logout failed",
+			"Çıkış işlemi başarısız",
+			"LOGOUT_ERROR",
 		))
 		return
 	}
 
 	logger.GetLogger().Info("User logged out",
-		zap.String("user_id", userID.(string)),
+		zap.String("user_id", token.userID.String()),
+		zap.String("session_id", token.sessionID.String()),
 		zap.String("ip", c.ClientIP()),
 		zap.String("type", "auth_logout"),
 	)
@@ -309,3 +540,67 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		gin.H{"message": "User logged out successfully"},
 	))
 }
+
+// LogoutAll handles logging out every session of the current user
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	token, ok := currentToken(c)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.tokenService.LogoutAll(c.Request.Context(), token.userID, token.tokenID, token.expiresAt)
+	if err != nil {
+		logger.GetLogger().Error("Logout of all sessions failed",
+			zap.String("user_id", token.userID.String()),
+			zap.Error(err),
+			zap.String("type", "auth_logout_error"),
+		)
+		c.JSON(http.StatusInternalServerError, models.NewAuthError(
+			"Logout failed",
+			"Çıkış işlemi başarısız",
+			"LOGOUT_ERROR",
+		))
+		return
+	}
+
+	logger.GetLogger().Info("User logged out of all sessions",
+		zap.String("user_id", token.userID.String()),
+		zap.Int64("sessions_revoked", revoked),
+		zap.String("ip", c.ClientIP()),
+		zap.String("type", "auth_logout_all"),
+	)
+
+	c.JSON(http.StatusOK, models.NewAuthSuccess(
+		"Tüm oturumlardan çıkış yapıldı",
+		gin.H{"sessions_revoked": revoked},
+	))
+}
+
+// tokenContext identifies the access token of an authenticated request
+type tokenContext struct {
+	userID    uuid.UUID
+	sessionID uuid.UUID
+	tokenID   uuid.UUID
+	expiresAt time.Time
+}
+
+// currentToken reads the access token details set by AuthenticationMiddleware or responds with 401
+func currentToken(c *gin.Context) (*tokenContext, bool) {
+	userID, userErr := uuid.Parse(c.GetString("user_id"))
+	sessionID, sessionErr := uuid.Parse(c.GetString("session_id"))
+	tokenID, tokenErr := uuid.Parse(c.GetString("token_id"))
+	if userErr != nil || sessionErr != nil || tokenErr != nil {
+		c.JSON(http.StatusUnauthorized, models.NewAuthError(
+			"Authentication required",
+			"Kimlik doğrulama gerekli",
+			"AUTH_REQUIRED",
+		))
+		return nil, false
+	}
+	return &tokenContext{
+		userID:    userID,
+		sessionID: sessionID,
+		tokenID:   tokenID,
+		expiresAt: c.GetTime("token_expires_at"),
+	}, true
+}
@@ -16,13 +16,15 @@ import (
 
 // BalanceHandler handles balance-related requests
 type BalanceHandler struct {
-	balanceService *services.BalanceService
+	balanceService   *services.BalanceService
+	statementService *services.StatementService
 }
 
 // NewBalanceHandler creates a new BalanceHandler instance
-func NewBalanceHandler(balanceService *services.BalanceService) *BalanceHandler {
+func NewBalanceHandler(balanceService *services.BalanceService, statementService *services.StatementService) *BalanceHandler {
 	return &BalanceHandler{
-		balanceService: balanceService,
+		balanceService:   balanceService,
+		statementService: statementService,
 	}
 }
 
@@ -48,6 +50,14 @@ func (h *BalanceHandler) GetCurrentBalance(c *gin.Context) {
 		return
 	}
 
+	// Serve a camt.053 statement of today's bookings when the client asks for one
+	if wantsStatement(c) {
+		now := time.Now()
+		year, month, day := now.Date()
+		respondStatement(c, h.statementService, userID, time.Date(year, month, day, 0, 0, 0, 0, now.Location()), now)
+		return
+	}
+
 	// Get current balance
 	balance, err := h.balanceService.GetBalance(c.Request.Context(), userID)
 	if err != nil {
@@ -0,0 +1,222 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/barannkoca/banking-backend/internal/authz"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/processing"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// BatchHandler handles batch transfer requests
+type BatchHandler struct {
+	batchService *services.BatchService
+	workerPool   *processing.WorkerPool
+}
+
+// NewBatchHandler creates a new BatchHandler instance
+func NewBatchHandler(batchService *services.BatchService, workerPool *processing.WorkerPool) *BatchHandler {
+	return &BatchHandler{
+		batchService: batchService,
+		workerPool:   workerPool,
+	}
+}
+
+// SubmitBatch handles POST /api/v1/transactions/batch
+func (h *BatchHandler) SubmitBatch(c *gin.Context) {
+	// Get current user from context
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authentication required",
+			"message": "Kimlik doğrulama gerekli",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(currentUserID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid user ID",
+			"message": "Geçersiz kullanıcı ID'si",
+		})
+		return
+	}
+
+	var req models.BatchTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.GetLogger().Warn("Invalid batch request",
+			zap.String("user_id", userID.String()),
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "batch_validation_error"),
+		)
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Geçersiz toplu transfer verisi",
+		})
+		return
+	}
+
+	batch, err := h.batchService.SubmitBatch(c.Request.Context(), userID, &req)
+	if err != nil {
+		var validationErr *services.BatchValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			items := (&models.Batch{Items: validationErr.Items}).ToResponse().Items
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   validationErr.Error(),
+				"message": "Toplu transferde geçersiz kalemler var, hiçbir işlem yapılmadı",
+				"items":   items,
+			})
+		case errors.Is(err, services.ErrBatchTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":     err.Error(),
+				"message":   "Toplu transfer kalem sınırını aşıyor",
+				"max_items": h.batchService.MaxItems(),
+			})
+		case errors.Is(err, services.ErrInsufficientFunds):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   err.Error(),
+				"message": "Toplu transfer toplamı için yetersiz bakiye",
+			})
+		case errors.Is(err, processing.ErrQueueFull):
+			respondQueueSaturated(c, h.workerPool.SuggestedRetryAfter())
+		default:
+			logger.GetLogger().Error("Failed to submit batch",
+				zap.String("user_id", userID.String()),
+				zap.Error(err),
+				zap.String("ip", c.ClientIP()),
+				zap.String("type", "batch_submit_error"),
+			)
+
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to submit batch",
+				"message": "Toplu transfer başlatılamadı",
+			})
+		}
+		return
+	}
+
+	logger.GetLogger().Info("Batch transfer submitted",
+		zap.String("user_id", userID.String()),
+		zap.String("batch_id", batch.ID.String()),
+		zap.String("mode", string(batch.Mode)),
+		zap.Int("item_count", batch.ItemCount),
+		zap.String("ip", c.ClientIP()),
+		zap.String("type", "batch_submitted"),
+	)
+
+	statusURL := "/api/v1/transactions/batch/" + batch.ID.String()
+	c.Header("Location", statusURL)
+
+	// All-or-nothing batches are already settled, best-effort batches run on the worker pool
+	status := http.StatusCreated
+	message := "Toplu transfer tamamlandı"
+	if !batch.IsFinished() {
+		status = http.StatusAccepted
+		message = "Toplu transfer kuyruğa alındı"
+	}
+
+	c.JSON(status, gin.H{
+		"message":    message,
+		"status_url": statusURL,
+		"data":       batch.ToResponse(),
+	})
+}
+
+// GetBatch handles GET /api/v1/transactions/batch/{id}
+func (h *BatchHandler) GetBatch(c *gin.Context) {
+	// Get current user from context
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authentication required",
+			"message": "Kimlik doğrulama gerekli",
+		})
+		return
+	}
+
+	batchIDStr := c.Param("id")
+	batchID, err := uuid.Parse(batchIDStr)
+	if err != nil {
+		logger.GetLogger().Warn("Invalid batch ID format",
+			zap.String("batch_id", batchIDStr),
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "batch_id_validation_error"),
+		)
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid batch ID",
+			"message": "Geçersiz toplu transfer ID'si",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(currentUserID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid user ID",
+			"message": "Geçersiz kullanıcı ID'si",
+		})
+		return
+	}
+
+	batch, err := h.batchService.GetBatch(c.Request.Context(), batchID)
+	if err != nil {
+		if errors.Is(err, services.ErrBatchNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Batch not found",
+				"message": "Toplu transfer bulunamadı",
+			})
+			return
+		}
+
+		logger.GetLogger().Error("Failed to retrieve batch",
+			zap.String("batch_id", batchID.String()),
+			zap.Error(err),
+			zap.String("ip", c.ClientIP()),
+			zap.String("type", "batch_retrieval_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve batch",
+			"message": "Toplu transfer alınamadı",
+		})
+		return
+	}
+
+	// Users can only view their own batches unless they can read any account
+	if !authz.Allowed(currentSubject(c, userID), authz.AccountsRead, batch.UserID) {
+		logger.GetLogger().Warn("Unauthorized batch access attempt",
+			zap.String("user_id", userID.String()),
+			zap.String("batch_id", batchID.String()),
+			zap.String("ip", c.ClientIP()),
+			zap.String("type", "batch_access_unauthorized"),
+		)
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Access denied",
+			"message": "Bu toplu transfere erişim izniniz yok",
+		})
+		return
+	}
+
+	// Tell pollers when to come back while items are still running
+	if !batch.IsFinished() {
+		c.Header("Retry-After", "1")
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Toplu transfer durumu başarıyla getirildi",
+		"data":    batch.ToResponse(),
+	})
+}
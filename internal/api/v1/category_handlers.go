@@ -0,0 +1,249 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/barannkoca/banking-backend/internal/authz"
+	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// CategoryHandler handles transaction categories, categorization rules and overrides
+type CategoryHandler struct {
+	categoryService *services.CategoryService
+}
+
+// NewCategoryHandler creates a new CategoryHandler instance
+func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+// ListCategories handles GET /api/v1/categories
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Kategoriler başarıyla getirildi",
+		"data":    models.TransactionCategories,
+	})
+}
+
+// ListRules handles GET /api/v1/categories/rules
+func (h *CategoryHandler) ListRules(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	rules, err := h.categoryService.ListRules(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Kategori kuralları başarıyla getirildi",
+		"data":    rules,
+	})
+}
+
+// CreateRule handles POST /api/v1/categories/rules
+func (h *CategoryHandler) CreateRule(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CategoryRuleRequest
+	if !bindCategoryRequest(c, &req) {
+		return
+	}
+
+	rule, recategorized, err := h.categoryService.CreateRule(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	logger.GetLogger().Info("Category rule created",
+		zap.String("user_id", userID.String()),
+		zap.String("rule_id", rule.ID.String()),
+		zap.Int64("recategorized", recategorized),
+		zap.String("type", "category_rule_created"),
+	)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":       "Kategori kuralı başarıyla oluşturuldu",
+		"data":          rule,
+		"recategorized": recategorized,
+	})
+}
+
+// UpdateRule handles PUT /api/v1/categories/rules/{id}
+func (h *CategoryHandler) UpdateRule(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	ruleID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.CategoryRuleRequest
+	if !bindCategoryRequest(c, &req) {
+		return
+	}
+
+	rule, recategorized, err := h.categoryService.UpdateRule(c.Request.Context(), userID, ruleID, &req)
+	if err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Kategori kuralı başarıyla güncellendi",
+		"data":          rule,
+		"recategorized": recategorized,
+	})
+}
+
+// DeleteRule handles DELETE /api/v1/categories/rules/{id}
+func (h *CategoryHandler) DeleteRule(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	ruleID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	recategorized, err := h.categoryService.DeleteRule(c.Request.Context(), userID, ruleID)
+	if err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Kategori kuralı başarıyla silindi",
+		"recategorized": recategorized,
+	})
+}
+
+// SetTransactionCategory handles PUT /api/v1/transactions/{id}/category
+func (h *CategoryHandler) SetTransactionCategory(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	transactionID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.CategoryOverrideRequest
+	if !bindCategoryRequest(c, &req) {
+		return
+	}
+
+	transaction, err := h.categoryService.SetTransactionCategory(c.Request.Context(), userID, transactionID, req.Category)
+	if err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "İşlem kategorisi başarıyla güncellendi",
+		"data":    transaction.ToResponse(),
+	})
+}
+
+// respondError maps category service errors to HTTP responses
+func (h *CategoryHandler) respondError(c *gin.Context, userID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCategoryRule):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   err.Error(),
+			"message": "Geçersiz kategori kuralı",
+		})
+	case errors.Is(err, services.ErrCategoryRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Category rule not found",
+			"message": "Kategori kuralı bulunamadı",
+		})
+	case errors.Is(err, services.ErrTransactionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transaction not found",
+			"message": "İşlem bulunamadı",
+		})
+	case errors.Is(err, services.ErrNotTransactionOwner):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Access denied",
+			"message": "Bu işlemin kategorisini değiştirme yetkiniz yok",
+		})
+	default:
+		middleware.IncrementErrorCount(c)
+
+		logger.GetLogger().Error("Category operation failed",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+			zap.String("type", "category_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Category operation failed",
+			"message": "Kategori işlemi başarısız oldu",
+		})
+	}
+}
+
+// bindCategoryRequest binds a JSON body and reports validation errors
+func bindCategoryRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "Geçersiz istek formatı",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// currentUserID returns the authenticated user's ID or responds with 401
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authentication required",
+			"message": "Kimlik doğrulama gerekli",
+		})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// currentSubject returns the authenticated user as an authorization subject
+func currentSubject(c *gin.Context, userID uuid.UUID) authz.Subject {
+	return authz.Subject{UserID: userID, Role: models.UserRole(c.GetString("user_role"))}
+}
+
+// parseIDParam parses the :id path parameter or responds with 400
+func parseIDParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid ID",
+			"message": "Geçersiz ID",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
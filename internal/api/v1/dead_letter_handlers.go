@@ -0,0 +1,279 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/processing"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DeadLetterHandler handles administrator requests on dead-lettered jobs
+type DeadLetterHandler struct {
+	deadLetterService *services.DeadLetterService
+}
+
+// NewDeadLetterHandler creates a new DeadLetterHandler instance
+func NewDeadLetterHandler(deadLetterService *services.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		deadLetterService: deadLetterService,
+	}
+}
+
+// ListDeadLetters handles GET /api/v1/admin/jobs/dead-letter
+func (h *DeadLetterHandler) ListDeadLetters(c *gin.Context) {
+	// Parse query parameters
+	limitStr := c.DefaultQuery("limit", "50")
+	offsetStr := c.DefaultQuery("offset", "0")
+	status := models.DeadLetterStatus(c.DefaultQuery("status", string(models.DeadLetterStatusPending)))
+	if c.Query("status") == "all" {
+		status = ""
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	deadLetters, total, err := h.deadLetterService.ListDeadLetters(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		logger.GetLogger().Error("Failed to list dead-letter jobs",
+			zap.Error(err),
+			zap.String("ip", c.ClientIP()),
+			zap.String("type", "dead_letter_list_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve dead-letter jobs",
+			"message": "Dead-letter işleri alınamadı",
+		})
+		return
+	}
+
+	// Convert to response format
+	deadLetterResponses := make([]*models.DeadLetterJobResponse, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		deadLetterResponses = append(deadLetterResponses, deadLetter.ToResponse())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Dead-letter işleri başarıyla getirildi",
+		"data":    deadLetterResponses,
+		"pagination": gin.H{
+			"limit":  limit,
+			"offset": offset,
+			"count":  len(deadLetterResponses),
+			"total":  total,
+		},
+	})
+}
+
+// GetDeadLetter handles GET /api/v1/admin/jobs/dead-letter/{id}
+func (h *DeadLetterHandler) GetDeadLetter(c *gin.Context) {
+	id, adminID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	deadLetter, err := h.deadLetterService.GetDeadLetter(c.Request.Context(), id, adminID)
+	if err != nil {
+		h.respondError(c, id, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Dead-letter işi başarıyla getirildi",
+		"data":    deadLetter.ToResponse(),
+	})
+}
+
+// UpdateDeadLetter handles PUT /api/v1/admin/jobs/dead-letter/{id}
+func (h *DeadLetterHandler) UpdateDeadLetter(c *gin.Context) {
+	id, adminID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateDeadLetterJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.GetLogger().Warn("Invalid dead-letter update request",
+			zap.String("dead_letter_id", id.String()),
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "dead_letter_update_validation_error"),
+		)
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "Geçersiz istek formatı",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	deadLetter, approval, err := h.deadLetterService.UpdateDeadLetter(c.Request.Context(), id, adminID, &req)
+	if err != nil {
+		h.respondError(c, id, err)
+		return
+	}
+
+	if approval != nil {
+		logger.GetLogger().Info("Dead-letter job edit awaiting approval",
+			zap.String("dead_letter_id", id.String()),
+			zap.String("admin_id", adminID.String()),
+			zap.String("approval_id", approval.ID.String()),
+			zap.String("type", "dead_letter_edit_approval_requested"),
+		)
+
+		respondApprovalRequested(c, approval, "Hesap veya tutar değişikliği ikinci bir yetkilinin onayını bekliyor, onaylandığında iş yeniden oynatılır")
+		return
+	}
+
+	logger.GetLogger().Info("Dead-letter job edited",
+		zap.String("dead_letter_id", id.String()),
+		zap.String("admin_id", adminID.String()),
+		zap.String("type", "dead_letter_edited"),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Dead-letter işi başarıyla güncellendi",
+		"data":    deadLetter.ToResponse(),
+	})
+}
+
+// ReplayDeadLetter handles POST /api/v1/admin/jobs/dead-letter/{id}/replay
+func (h *DeadLetterHandler) ReplayDeadLetter(c *gin.Context) {
+	id, adminID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	deadLetter, err := h.deadLetterService.ReplayDeadLetter(c.Request.Context(), id, adminID)
+	if err != nil {
+		h.respondError(c, id, err)
+		return
+	}
+
+	logger.GetLogger().Info("Dead-letter job replayed",
+		zap.String("dead_letter_id", id.String()),
+		zap.String("job_id", deadLetter.JobID.String()),
+		zap.String("admin_id", adminID.String()),
+		zap.String("type", "dead_letter_replayed"),
+	)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":    "Dead-letter işi yeniden kuyruğa alındı",
+		"data":       deadLetter.ToResponse(),
+		"status_url": "/api/v1/jobs/" + deadLetter.JobID.String(),
+	})
+}
+
+// DiscardDeadLetter handles DELETE /api/v1/admin/jobs/dead-letter/{id}
+func (h *DeadLetterHandler) DiscardDeadLetter(c *gin.Context) {
+	id, adminID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	deadLetter, err := h.deadLetterService.DiscardDeadLetter(c.Request.Context(), id, adminID)
+	if err != nil {
+		h.respondError(c, id, err)
+		return
+	}
+
+	logger.GetLogger().Info("Dead-letter job discarded",
+		zap.String("dead_letter_id", id.String()),
+		zap.String("admin_id", adminID.String()),
+		zap.String("type", "dead_letter_discarded"),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Dead-letter işi iptal edildi",
+		"data":    deadLetter.ToResponse(),
+	})
+}
+
+// parseRequest extracts the dead-letter ID and the acting admin ID, writing an error response on failure
+func (h *DeadLetterHandler) parseRequest(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authentication required",
+			"message": "Kimlik doğrulama gerekli",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	adminID, err := uuid.Parse(currentUserID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid user ID",
+			"message": "Geçersiz kullanıcı ID'si",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.GetLogger().Warn("Invalid dead-letter ID format",
+			zap.String("dead_letter_id", idStr),
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "dead_letter_id_validation_error"),
+		)
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid dead-letter ID",
+			"message": "Geçersiz dead-letter ID'si",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, adminID, true
+}
+
+// respondError maps dead-letter service errors to HTTP responses
+func (h *DeadLetterHandler) respondError(c *gin.Context, id uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, services.ErrDeadLetterNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Dead-letter job not found",
+			"message": "Dead-letter işi bulunamadı",
+		})
+	case errors.Is(err, services.ErrDeadLetterResolved):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Dead-letter job already resolved",
+			"message": "Dead-letter işi zaten yeniden oynatılmış veya iptal edilmiş",
+		})
+	case errors.Is(err, processing.ErrQueueFull):
+		c.Header("Retry-After", "5")
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Job queue is full",
+			"message": "İş kuyruğu dolu, lütfen daha sonra tekrar deneyin",
+		})
+	default:
+		logger.GetLogger().Error("Dead-letter operation failed",
+			zap.String("dead_letter_id", id.String()),
+			zap.Error(err),
+			zap.String("ip", c.ClientIP()),
+			zap.String("type", "dead_letter_operation_error"),
+		)
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   err.Error(),
+			"message": "Dead-letter işlemi başarısız",
+		})
+	}
+}
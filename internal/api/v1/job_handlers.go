@@ -0,0 +1,100 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/barannkoca/banking-backend/internal/authz"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// JobHandler handles asynchronous job status requests
+type JobHandler struct {
+	jobService *services.JobService
+}
+
+// NewJobHandler creates a new JobHandler instance
+func NewJobHandler(jobService *services.JobService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// GetJob handles GET /api/v1/jobs/{id}
+func (h *JobHandler) GetJob(c *gin.Context) {
+	// Get current user from context
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authentication required",
+			"message": "Kimlik doğrulama gerekli",
+		})
+		return
+	}
+
+	// Get job ID from URL parameter
+	jobIDStr := c.Param("id")
+	jobID, err := uuid.Parse(jobIDStr)
+	if err != nil {
+		logger.GetLogger().Warn("Invalid job ID format",
+			zap.String("job_id", jobIDStr),
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "job_id_validation_error"),
+		)
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid job ID",
+			"message": "Geçersiz iş ID'si",
+		})
+		return
+	}
+
+	// Parse user ID
+	userID, err := uuid.Parse(currentUserID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid user ID",
+			"message": "Geçersiz kullanıcı ID'si",
+		})
+		return
+	}
+
+	job, err := h.jobService.GetJob(c.Request.Context(), jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Job not found",
+			"message": "İş bulunamadı",
+		})
+		return
+	}
+
+	// Users can only poll their own jobs unless they can read any account
+	if !authz.Allowed(currentSubject(c, userID), authz.AccountsRead, job.UserID) {
+		logger.GetLogger().Warn("Unauthorized job access attempt",
+			zap.String("user_id", userID.String()),
+			zap.String("job_id", jobID.String()),
+			zap.String("ip", c.ClientIP()),
+			zap.String("type", "job_access_unauthorized"),
+		)
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Access denied",
+			"message": "Bu işe erişim izniniz yok",
+		})
+		return
+	}
+
+	// Tell pollers when to come back while the job is still running
+	if !job.IsFinished() {
+		c.Header("Retry-After", "1")
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "İş durumu başarıyla getirildi",
+		"data":    job.ToResponse(),
+	})
+}
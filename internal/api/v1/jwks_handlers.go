@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// jwksMaxAge is how long verifiers may cache the JWKS; new keys are published well before
+// they sign, so a cached copy never misses a key in use
+const jwksMaxAge = 300
+
+// JWKSHandler publishes the public keys that verify issued tokens
+type JWKSHandler struct {
+	signingKeyService *services.SigningKeyService
+}
+
+// NewJWKSHandler creates a new JWKSHandler instance
+func NewJWKSHandler(signingKeyService *services.SigningKeyService) *JWKSHandler {
+	return &JWKSHandler{
+		signingKeyService: signingKeyService,
+	}
+}
+
+// GetJWKS handles GET /.well-known/jwks.json
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", jwksMaxAge))
+	c.JSON(http.StatusOK, h.signingKeyService.JWKS())
+}
@@ -0,0 +1,89 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LockoutHandler handles admin management of failed-login lockouts
+type LockoutHandler struct {
+	lockoutService *services.LockoutService
+}
+
+// NewLockoutHandler creates a new LockoutHandler instance
+func NewLockoutHandler(lockoutService *services.LockoutService) *LockoutHandler {
+	return &LockoutHandler{
+		lockoutService: lockoutService,
+	}
+}
+
+// AdminGetLockout handles GET /api/v1/admin/users/{id}/lockout
+func (h *LockoutHandler) AdminGetLockout(c *gin.Context) {
+	userID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.lockoutService.Status(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Hesap kilit durumu getirildi",
+		"data":    status,
+	})
+}
+
+// AdminUnlock handles DELETE /api/v1/admin/users/{id}/lockout
+func (h *LockoutHandler) AdminUnlock(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.lockoutService.Unlock(c.Request.Context(), adminID, userID); err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Hesap kilidi kaldırıldı",
+	})
+}
+
+// respondError maps lockout service errors to HTTP responses
+func (h *LockoutHandler) respondError(c *gin.Context, userID uuid.UUID, err error) {
+	if errors.Is(err, services.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "User not found",
+			"message": "Kullanıcı bulunamadı",
+		})
+		return
+	}
+
+	middleware.IncrementErrorCount(c)
+
+	logger.GetLogger().Error("Lockout operation failed",
+		zap.String("user_id", userID.String()),
+		zap.Error(err),
+		zap.String("type", "lockout_error"),
+	)
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Lockout operation failed",
+		"message": "Hesap kilidi işlemi başarısız oldu",
+	})
+}
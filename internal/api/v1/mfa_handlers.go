@@ -0,0 +1,222 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MFAHandler handles two-factor authentication management
+type MFAHandler struct {
+	mfaService     *services.MFAService
+	lockoutService *services.LockoutService
+}
+
+// NewMFAHandler creates a new MFAHandler instance
+func NewMFAHandler(mfaService *services.MFAService, lockoutService *services.LockoutService) *MFAHandler {
+	return &MFAHandler{
+		mfaService:     mfaService,
+		lockoutService: lockoutService,
+	}
+}
+
+// Enroll handles POST /api/v1/auth/mfa/enroll
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.mfaService.Enroll(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Doğrulama uygulamanıza anahtarı ekleyip bir kod ile onaylayın",
+		"data":    enrollment,
+	})
+}
+
+// Verify handles POST /api/v1/auth/mfa/verify, confirming a pending enrollment
+func (h *MFAHandler) Verify(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req models.MFACodeRequest
+	if !bindMFACode(c, &req) {
+		return
+	}
+	if !beginAttempt(c, h.lockoutService, &userID, "") {
+		return
+	}
+
+	codes, err := h.mfaService.ConfirmEnrollment(c.Request.Context(), userID, req.Code)
+	h.settleAttempt(c, userID, err)
+	if err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	logger.GetLogger().Info("MFA enabled",
+		zap.String("user_id", userID.String()),
+		zap.String("type", "mfa_enabled"),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "İki faktörlü doğrulama etkinleştirildi, kurtarma kodlarını güvenli bir yerde saklayın",
+		"recovery_codes": codes,
+	})
+}
+
+// Disable handles POST /api/v1/auth/mfa/disable
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req models.MFACodeRequest
+	if !bindMFACode(c, &req) {
+		return
+	}
+	if !beginAttempt(c, h.lockoutService, &userID, "") {
+		return
+	}
+
+	err := h.mfaService.Disable(c.Request.Context(), userID, req.Code)
+	h.settleAttempt(c, userID, err)
+	if err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	logger.GetLogger().Info("MFA disabled",
+		zap.String("user_id", userID.String()),
+		zap.String("type", "mfa_disabled"),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "İki faktörlü doğrulama devre dışı bırakıldı",
+	})
+}
+
+// RegenerateRecoveryCodes handles POST /api/v1/auth/mfa/recovery-codes
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req models.MFACodeRequest
+	if !bindMFACode(c, &req) {
+		return
+	}
+	if !beginAttempt(c, h.lockoutService, &userID, "") {
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(c.Request.Context(), userID, req.Code)
+	h.settleAttempt(c, userID, err)
+	if err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Yeni kurtarma kodları oluşturuldu, eski kodlar geçersiz",
+		"recovery_codes": codes,
+	})
+}
+
+// AdminReset handles DELETE /api/v1/admin/users/{id}/mfa
+func (h *MFAHandler) AdminReset(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.mfaService.AdminReset(c.Request.Context(), adminID, userID); err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Kullanıcının iki faktörlü doğrulaması sıfırlandı",
+	})
+}
+
+// settleAttempt keeps the attempt counted by beginAttempt only for a wrong code, so codes
+// cannot be guessed through an authenticated session
+func (h *MFAHandler) settleAttempt(c *gin.Context, userID uuid.UUID, err error) {
+	if !errors.Is(err, services.ErrInvalidMFACode) {
+		refundAttempt(c, h.lockoutService, &userID, "")
+	}
+}
+
+// respondError maps MFA service errors to HTTP responses
+func (h *MFAHandler) respondError(c *gin.Context, userID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidMFACode):
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Invalid MFA code",
+			"message": "Geçersiz doğrulama kodu",
+		})
+	case errors.Is(err, services.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "MFA already enabled",
+			"message": "İki faktörlü doğrulama zaten etkin",
+		})
+	case errors.Is(err, services.ErrMFANotEnrolled):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "MFA enrollment not started",
+			"message": "Önce iki faktörlü doğrulama kaydını başlatın",
+		})
+	case errors.Is(err, services.ErrMFANotEnabled):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "MFA not enabled",
+			"message": "İki faktörlü doğrulama etkin değil",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "User not found",
+			"message": "Kullanıcı bulunamadı",
+		})
+	default:
+		middleware.IncrementErrorCount(c)
+
+		logger.GetLogger().Error("MFA operation failed",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+			zap.String("type", "mfa_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "MFA operation failed",
+			"message": "İki faktörlü doğrulama işlemi başarısız oldu",
+		})
+	}
+}
+
+// bindMFACode binds a code request or responds with 400
+func bindMFACode(c *gin.Context, req *models.MFACodeRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"message": "Doğrulama kodu gerekli",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,391 @@
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/barannkoca/banking-backend/internal/authz"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/processing"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxPaymentFileSize bounds the size of uploaded payment files
+const maxPaymentFileSize = 5 << 20
+
+// PaymentImportHandler handles bulk payment file uploads
+type PaymentImportHandler struct {
+	importService *services.PaymentImportService
+	workerPool    *processing.WorkerPool
+}
+
+// NewPaymentImportHandler creates a new PaymentImportHandler instance
+func NewPaymentImportHandler(importService *services.PaymentImportService, workerPool *processing.WorkerPool) *PaymentImportHandler {
+	return &PaymentImportHandler{
+		importService: importService,
+		workerPool:    workerPool,
+	}
+}
+
+// UploadImport handles POST /api/v1/transactions/imports
+func (h *PaymentImportHandler) UploadImport(c *gin.Context) {
+	// Get current user from context
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authentication required",
+			"message": "Kimlik doğrulama gerekli",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(currentUserID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid user ID",
+			"message": "Geçersiz kullanıcı ID'si",
+		})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPaymentFileSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Payment file is required (max 5 MB)",
+			"message": "Ödeme dosyası gerekli (en fazla 5 MB)",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to read payment file",
+			"message": "Ödeme dosyası okunamadı",
+		})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to read payment file",
+			"message": "Ödeme dosyası okunamadı",
+		})
+		return
+	}
+
+	format := detectPaymentFileFormat(c.PostForm("format"), fileHeader.Filename, data)
+	if !models.IsValidPaymentFileFormat(string(format)) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Unsupported payment file format, use csv or pain.001",
+			"message": "Desteklenmeyen dosya formatı",
+		})
+		return
+	}
+
+	paymentImport, err := h.importService.Upload(c.Request.Context(), userID, format, filepath.Base(fileHeader.Filename), data)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidPaymentFile):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   err.Error(),
+				"message": "Ödeme dosyası işlenemedi",
+			})
+		case errors.Is(err, services.ErrDuplicateImport):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   err.Error(),
+				"message": "Bu dosya daha önce yüklendi",
+			})
+		case errors.Is(err, services.ErrBatchTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":   err.Error(),
+				"message": "Dosya kalem sınırını aşıyor",
+			})
+		default:
+			logger.GetLogger().Error("Failed to import payment file",
+				zap.String("user_id", userID.String()),
+				zap.Error(err),
+				zap.String("ip", c.ClientIP()),
+				zap.String("type", "payment_import_error"),
+			)
+
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to import payment file",
+				"message": "Ödeme dosyası içe aktarılamadı",
+			})
+		}
+		return
+	}
+
+	logger.GetLogger().Info("Payment file uploaded",
+		zap.String("user_id", userID.String()),
+		zap.String("import_id", paymentImport.ID.String()),
+		zap.String("format", string(format)),
+		zap.Int("row_count", paymentImport.RowCount),
+		zap.Int("valid_count", paymentImport.ValidCount),
+		zap.String("ip", c.ClientIP()),
+		zap.String("type", "payment_import_uploaded"),
+	)
+
+	c.Header("Location", "/api/v1/transactions/imports/"+paymentImport.ID.String())
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Ödeme dosyası önizlemesi hazır, onay bekleniyor",
+		"data":    h.toResponse(c, paymentImport, nil),
+	})
+}
+
+// GetImport handles GET /api/v1/transactions/imports/{id}
+func (h *PaymentImportHandler) GetImport(c *gin.Context) {
+	paymentImport, batch, _, ok := h.loadImport(c, false)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ödeme dosyası başarıyla getirildi",
+		"data":    h.toResponse(c, paymentImport, batch),
+	})
+}
+
+// ConfirmImport handles POST /api/v1/transactions/imports/{id}/confirm
+func (h *PaymentImportHandler) ConfirmImport(c *gin.Context) {
+	paymentImport, _, userID, ok := h.loadImport(c, true)
+	if !ok {
+		return
+	}
+
+	batch, err := h.importService.ConfirmImport(c.Request.Context(), paymentImport)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrImportExpired):
+			c.JSON(http.StatusGone, gin.H{
+				"error":   err.Error(),
+				"message": "Önizlemenin süresi doldu, dosyayı yeniden yükleyin",
+			})
+		case errors.Is(err, services.ErrImportNotPending):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   err.Error(),
+				"message": "Ödeme dosyası onay beklemiyor",
+			})
+		case errors.Is(err, services.ErrImportNoValidRows):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   err.Error(),
+				"message": "Dosyada geçerli ödeme yok",
+			})
+		case errors.Is(err, services.ErrInsufficientFunds):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   err.Error(),
+				"message": "Ödeme toplamı için yetersiz bakiye",
+			})
+		case errors.Is(err, processing.ErrQueueFull):
+			respondQueueSaturated(c, h.workerPool.SuggestedRetryAfter())
+		default:
+			logger.GetLogger().Error("Failed to confirm payment import",
+				zap.String("user_id", userID.String()),
+				zap.String("import_id", paymentImport.ID.String()),
+				zap.Error(err),
+				zap.String("ip", c.ClientIP()),
+				zap.String("type", "payment_import_confirm_error"),
+			)
+
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to confirm payment import",
+				"message": "Ödeme dosyası onaylanamadı",
+			})
+		}
+		return
+	}
+
+	logger.GetLogger().Info("Payment import confirmed",
+		zap.String("user_id", userID.String()),
+		zap.String("import_id", paymentImport.ID.String()),
+		zap.String("batch_id", batch.ID.String()),
+		zap.String("ip", c.ClientIP()),
+		zap.String("type", "payment_import_confirmed"),
+	)
+
+	statusURL := "/api/v1/transactions/imports/" + paymentImport.ID.String()
+	c.Header("Location", statusURL)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":    "Ödemeler kuyruğa alındı",
+		"status_url": statusURL,
+		"report_url": statusURL + "/report",
+		"data":       h.toResponse(c, paymentImport, batch),
+	})
+}
+
+// CancelImport handles DELETE /api/v1/transactions/imports/{id}
+func (h *PaymentImportHandler) CancelImport(c *gin.Context) {
+	paymentImport, _, _, ok := h.loadImport(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.importService.CancelImport(c.Request.Context(), paymentImport); err != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   err.Error(),
+			"message": "Ödeme dosyası onay beklemiyor",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ödeme dosyası iptal edildi",
+		"data":    h.toResponse(c, paymentImport, nil),
+	})
+}
+
+// GetImportReport handles GET /api/v1/transactions/imports/{id}/report
+func (h *PaymentImportHandler) GetImportReport(c *gin.Context) {
+	paymentImport, batch, _, ok := h.loadImport(c, false)
+	if !ok {
+		return
+	}
+
+	report, err := h.importService.StatusReport(paymentImport, batch)
+	if err != nil {
+		if errors.Is(err, services.ErrImportNotFinished) {
+			if batch != nil {
+				c.Header("Retry-After", "1")
+			}
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   err.Error(),
+				"message": "Ödemeler henüz tamamlanmadı",
+			})
+			return
+		}
+
+		logger.GetLogger().Error("Failed to render payment status report",
+			zap.String("import_id", paymentImport.ID.String()),
+			zap.Error(err),
+			zap.String("ip", c.ClientIP()),
+			zap.String("type", "payment_report_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to render status report",
+			"message": "Durum raporu oluşturulamadı",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="pain002-`+paymentImport.ID.String()+`.xml"`)
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", report)
+}
+
+// loadImport parses the import ID and loads the import, enforcing that users only see
+// their own imports. Admins may view any import but only the owner may act on it.
+func (h *PaymentImportHandler) loadImport(c *gin.Context, ownerOnly bool) (*models.PaymentImport, *models.Batch, uuid.UUID, bool) {
+	// Get current user from context
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authentication required",
+			"message": "Kimlik doğrulama gerekli",
+		})
+		return nil, nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(currentUserID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid user ID",
+			"message": "Geçersiz kullanıcı ID'si",
+		})
+		return nil, nil, uuid.Nil, false
+	}
+
+	importID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid import ID",
+			"message": "Geçersiz dosya ID'si",
+		})
+		return nil, nil, uuid.Nil, false
+	}
+
+	paymentImport, batch, err := h.importService.GetImport(c.Request.Context(), importID)
+	if err != nil {
+		if errors.Is(err, services.ErrImportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Payment import not found",
+				"message": "Ödeme dosyası bulunamadı",
+			})
+			return nil, nil, uuid.Nil, false
+		}
+
+		logger.GetLogger().Error("Failed to retrieve payment import",
+			zap.String("import_id", importID.String()),
+			zap.Error(err),
+			zap.String("ip", c.ClientIP()),
+			zap.String("type", "payment_import_retrieval_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve payment import",
+			"message": "Ödeme dosyası alınamadı",
+		})
+		return nil, nil, uuid.Nil, false
+	}
+
+	isOwner := paymentImport.UserID == userID
+	if !isOwner && (ownerOnly || !authz.Allowed(currentSubject(c, userID), authz.AccountsRead, paymentImport.UserID)) {
+		logger.GetLogger().Warn("Unauthorized payment import access attempt",
+			zap.String("user_id", userID.String()),
+			zap.String("import_id", importID.String()),
+			zap.String("ip", c.ClientIP()),
+			zap.String("type", "payment_import_access_unauthorized"),
+		)
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Access denied",
+			"message": "Bu ödeme dosyasına erişim izniniz yok",
+		})
+		return nil, nil, uuid.Nil, false
+	}
+
+	return paymentImport, batch, userID, true
+}
+
+// toResponse builds the import response, with a funds check while the import awaits confirmation
+func (h *PaymentImportHandler) toResponse(c *gin.Context, paymentImport *models.PaymentImport, batch *models.Batch) *models.PaymentImportResponse {
+	response := paymentImport.ToResponse()
+	if paymentImport.Status == models.PaymentImportStatusPreview {
+		if sufficient, err := h.importService.CheckFunds(c.Request.Context(), paymentImport); err == nil {
+			response.FundsSufficient = &sufficient
+		}
+	}
+	if batch != nil {
+		response.Batch = batch.ToResponse()
+	}
+	return response
+}
+
+// detectPaymentFileFormat picks the file format from the explicit form field, the file
+// extension or, as a last resort, the content
+func detectPaymentFileFormat(explicit, fileName string, data []byte) models.PaymentFileFormat {
+	if explicit != "" {
+		return models.PaymentFileFormat(strings.ToLower(explicit))
+	}
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return models.PaymentFileFormatCSV
+	case ".xml":
+		return models.PaymentFileFormatPain001
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		return models.PaymentFileFormatPain001
+	}
+	return models.PaymentFileFormatCSV
+}
@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/authz"
+	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SearchHandler handles full-text search requests
+type SearchHandler struct {
+	searchService *services.SearchService
+}
+
+// NewSearchHandler creates a new SearchHandler instance
+func NewSearchHandler(searchService *services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search handles GET /api/v1/search
+func (h *SearchHandler) Search(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authentication required",
+			"message": "Kimlik doğrulama gerekli",
+		})
+		return
+	}
+
+	query := &models.SearchQuery{Text: c.Query("q")}
+	for _, value := range splitList(c.Query("type")) {
+		query.Types = append(query.Types, models.SearchResultType(value))
+	}
+	if value := c.Query("from"); value != "" {
+		from, ok := parseDateOrTime(value, time.Time{}, false)
+		if !ok {
+			respondInvalidSearch(c, "from must be YYYY-MM-DD or RFC3339")
+			return
+		}
+		query.From = &from
+	}
+	if value := c.Query("to"); value != "" {
+		to, ok := parseDateOrTime(value, time.Time{}, true)
+		if !ok {
+			respondInvalidSearch(c, "to must be YYYY-MM-DD or RFC3339")
+			return
+		}
+		query.To = &to
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			respondInvalidSearch(c, "limit must be a positive integer")
+			return
+		}
+		query.Limit = limit
+	}
+
+	// Staff who can read any account search everything; customers only see their own records
+	role := c.GetString("user_role")
+	if !authz.HasPermission(models.UserRole(role), authz.AccountsRead) {
+		query.OwnerID = &userID
+	}
+
+	results, err := h.searchService.Search(c.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSearchQuery) {
+			respondInvalidSearch(c, err.Error())
+			return
+		}
+
+		middleware.IncrementErrorCount(c)
+
+		logger.GetLogger().Error("Search failed",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+			zap.String("type", "search_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Search failed",
+			"message": "Arama yapılamadı",
+		})
+		return
+	}
+
+	logger.GetLogger().Info("Search completed",
+		zap.String("user_id", userID.String()),
+		zap.String("user_role", role),
+		zap.Int("count", len(results)),
+		zap.String("ip", c.ClientIP()),
+		zap.String("type", "search_success"),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Arama sonuçları başarıyla getirildi",
+		"data":    results,
+		"count":   len(results),
+	})
+}
+
+// respondInvalidSearch reports a malformed search parameter
+func respondInvalidSearch(c *gin.Context, reason string) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":   reason,
+		"message": "Geçersiz arama parametresi",
+	})
+}
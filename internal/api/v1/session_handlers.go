@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SessionHandler handles login session (device) management for users and admins
+type SessionHandler struct {
+	sessionService *services.SessionService
+}
+
+// NewSessionHandler creates a new SessionHandler instance
+func NewSessionHandler(sessionService *services.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// ListSessions handles GET /api/v1/auth/sessions
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	token, ok := currentToken(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.sessionService.ListSessions(c.Request.Context(), token.userID)
+	if err != nil {
+		h.respondError(c, token.userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Oturumlar başarıyla getirildi",
+		"data":    sessionResponses(sessions, token.sessionID),
+	})
+}
+
+// RevokeSession handles DELETE /api/v1/auth/sessions/{id}
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	token, ok := currentToken(c)
+	if !ok {
+		return
+	}
+	sessionID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.sessionService.RevokeSession(c.Request.Context(), token.userID, sessionID); err != nil {
+		h.respondError(c, token.userID, err)
+		return
+	}
+
+	logger.GetLogger().Info("Session revoked",
+		zap.String("user_id", token.userID.String()),
+		zap.String("session_id", sessionID.String()),
+		zap.Bool("current", sessionID == token.sessionID),
+		zap.String("type", "session_revoked"),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Oturum sonlandırıldı",
+	})
+}
+
+// AdminListSessions handles GET /api/v1/admin/users/{id}/sessions
+func (h *SessionHandler) AdminListSessions(c *gin.Context) {
+	userID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.sessionService.ListUserSessions(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Oturumlar başarıyla getirildi",
+		"data":    sessionResponses(sessions, uuid.Nil),
+	})
+}
+
+// AdminRevokeSession handles DELETE /api/v1/admin/users/{id}/sessions/{session_id}
+func (h *SessionHandler) AdminRevokeSession(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid session ID",
+			"message": "Geçersiz oturum ID",
+		})
+		return
+	}
+
+	if err := h.sessionService.AdminRevokeSession(c.Request.Context(), adminID, userID, sessionID); err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Oturum sonlandırıldı",
+	})
+}
+
+// AdminRevokeAllSessions handles DELETE /api/v1/admin/users/{id}/sessions
+func (h *SessionHandler) AdminRevokeAllSessions(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.sessionService.AdminRevokeAllSessions(c.Request.Context(), adminID, userID)
+	if err != nil {
+		h.respondError(c, userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Kullanıcının tüm oturumları sonlandırıldı",
+		"sessions_revoked": revoked,
+	})
+}
+
+// respondError maps session service errors to HTTP responses
+func (h *SessionHandler) respondError(c *gin.Context, userID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, services.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Session not found",
+			"message": "Oturum bulunamadı",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "User not found",
+			"message": "Kullanıcı bulunamadı",
+		})
+	default:
+		middleware.IncrementErrorCount(c)
+
+		logger.GetLogger().Error("Session operation failed",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+			zap.String("type", "session_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Session operation failed",
+			"message": "Oturum işlemi başarısız oldu",
+		})
+	}
+}
+
+// sessionResponses converts sessions to responses, marking the caller's current session
+func sessionResponses(sessions []*models.TokenFamily, currentSessionID uuid.UUID) []*models.SessionResponse {
+	responses := make([]*models.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = session.ToSessionResponse(session.ID == currentSessionID)
+	}
+	return responses
+}
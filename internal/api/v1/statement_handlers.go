@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MIMECamt053 is the media type clients send in Accept to receive a camt.053 statement
+const MIMECamt053 = "application/vnd.iso20022.camt.053+xml"
+
+// statementFormats are the Accept values that select a camt.053 statement
+var statementFormats = []string{MIMECamt053, gin.MIMEXML, gin.MIMEXML2}
+
+// wantsStatement reports whether the client prefers a camt.053 statement over JSON
+func wantsStatement(c *gin.Context) bool {
+	format := c.NegotiateFormat(append([]string{gin.MIMEJSON}, statementFormats...)...)
+	for _, candidate := range statementFormats {
+		if format == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// respondStatement renders the camt.053 statement of an account for the period given by
+// the from and to query parameters, falling back to the given defaults
+func respondStatement(c *gin.Context, statementService *services.StatementService, userID uuid.UUID, defaultFrom, defaultTo time.Time) {
+	from, ok := parseDateOrTime(c.Query("from"), defaultFrom, false)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid from parameter, use YYYY-MM-DD or RFC3339",
+			"message": "Geçersiz başlangıç tarihi",
+		})
+		return
+	}
+	to, ok := parseDateOrTime(c.Query("to"), defaultTo, true)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid to parameter, use YYYY-MM-DD or RFC3339",
+			"message": "Geçersiz bitiş tarihi",
+		})
+		return
+	}
+
+	body, err := statementService.GenerateCamt053(c.Request.Context(), userID, from, to)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidStatementPeriod) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   err.Error(),
+				"message": "Geçersiz ekstre dönemi",
+			})
+			return
+		}
+
+		middleware.IncrementErrorCount(c)
+
+		logger.GetLogger().Error("Failed to generate statement",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+			zap.String("type", "statement_export_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to generate statement",
+			"message": "Hesap ekstresi oluşturulamadı",
+		})
+		return
+	}
+
+	logger.GetLogger().Info("Statement exported",
+		zap.String("user_id", userID.String()),
+		zap.String("ip", c.ClientIP()),
+		zap.String("type", "statement_export_success"),
+	)
+
+	c.Header("Vary", "Accept")
+	c.Data(http.StatusOK, MIMECamt053+"; charset=utf-8", body)
+}
+
+// parseDateOrTime parses a date or RFC3339 timestamp; a bare date used as the end of
+// a period covers the whole day
+func parseDateOrTime(value string, fallback time.Time, endOfDay bool) (time.Time, bool) {
+	if value == "" {
+		return fallback, true
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, true
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+	return parsed, true
+}
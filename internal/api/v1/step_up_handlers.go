@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/barannkoca/banking-backend/internal/middleware"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/services"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// StepUpHandler handles step-up (re-authentication) requests for sensitive operations
+type StepUpHandler struct {
+	stepUpService  *services.StepUpService
+	lockoutService *services.LockoutService
+}
+
+// NewStepUpHandler creates a new StepUpHandler instance
+func NewStepUpHandler(stepUpService *services.StepUpService, lockoutService *services.LockoutService) *StepUpHandler {
+	return &StepUpHandler{
+		stepUpService:  stepUpService,
+		lockoutService: lockoutService,
+	}
+}
+
+// CreateChallenge handles POST /api/v1/auth/step-up/challenge, for clients that step up
+// before calling a sensitive endpoint instead of waiting for its 401
+func (h *StepUpHandler) CreateChallenge(c *gin.Context) {
+	token, ok := currentToken(c)
+	if !ok {
+		return
+	}
+
+	challenge, err := h.stepUpService.CreateStepUpChallenge(c.Request.Context(), token.userID, token.sessionID)
+	if err != nil {
+		h.respondError(c, token.userID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Yeniden kimlik doğrulama için challenge oluşturuldu",
+		"data":    challenge,
+	})
+}
+
+// CompleteStepUp handles POST /api/v1/auth/step-up
+func (h *StepUpHandler) CompleteStepUp(c *gin.Context) {
+	token, ok := currentToken(c)
+	if !ok {
+		return
+	}
+
+	var req models.StepUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"message": "Geçersiz doğrulama verisi",
+		})
+		return
+	}
+
+	// Wrong credentials count against the account like failed logins
+	if !beginAttempt(c, h.lockoutService, &token.userID, "") {
+		return
+	}
+
+	response, err := h.stepUpService.CompleteStepUp(c.Request.Context(), token.userID, token.sessionID, &req)
+	if !errors.Is(err, services.ErrInvalidStepUpCredentials) {
+		refundAttempt(c, h.lockoutService, &token.userID, "")
+	}
+	if err != nil {
+		h.respondError(c, token.userID, err)
+		return
+	}
+
+	logger.GetLogger().Info("Step-up authentication completed",
+		zap.String("user_id", token.userID.String()),
+		zap.String("session_id", token.sessionID.String()),
+		zap.String("acr", response.ACR),
+		zap.String("type", "step_up_success"),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Yeniden kimlik doğrulama başarılı",
+		"data":    response,
+	})
+}
+
+// respondError maps step-up service errors to HTTP responses
+func (h *StepUpHandler) respondError(c *gin.Context, userID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidStepUpCredentials):
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Invalid credentials",
+			"message": "Şifre veya doğrulama kodu hatalı",
+		})
+	case errors.Is(err, services.ErrInvalidStepUpChallenge):
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Invalid step-up challenge",
+			"message": "Doğrulama isteği geçersiz veya süresi dolmuş",
+		})
+	case errors.Is(err, services.ErrStepUpMethodNotAllowed):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Step-up method not allowed",
+			"message": "İki faktörlü doğrulama açık hesaplarda doğrulama kodu, diğerlerinde şifre gerekli",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "User not found",
+			"message": "Kullanıcı bulunamadı",
+		})
+	default:
+		middleware.IncrementErrorCount(c)
+
+		logger.GetLogger().Error("Step-up operation failed",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+			zap.String("type", "step_up_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Step-up failed",
+			"message": "Yeniden kimlik doğrulama başarısız oldu",
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/events"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// streamRetryMillis is the reconnection delay suggested to EventSource clients
+	streamRetryMillis = 3000
+	// streamWriteTimeout bounds a single WebSocket write to a stalled client
+	streamWriteTimeout = 10 * time.Second
+	// streamLimitRetryAfter is the Retry-After value sent when the connection limit is hit
+	streamLimitRetryAfter = "30"
+)
+
+// StreamHandler pushes the current user's balance and transaction events over
+// Server-Sent Events or WebSocket
+type StreamHandler struct {
+	broker            *events.Broker
+	heartbeatInterval time.Duration
+	maxDuration       time.Duration
+}
+
+// NewStreamHandler creates a new StreamHandler instance
+func NewStreamHandler(broker *events.Broker, heartbeatInterval, maxDuration time.Duration) *StreamHandler {
+	return &StreamHandler{
+		broker:            broker,
+		heartbeatInterval: heartbeatInterval,
+		maxDuration:       maxDuration,
+	}
+}
+
+// streamMessage is a WebSocket frame without event payload: the connection greeting and heartbeats
+type streamMessage struct {
+	Type        string    `json:"type"`
+	LastEventID uint64    `json:"last_event_id,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// Stream handles GET /api/v1/stream (Server-Sent Events)
+func (h *StreamHandler) Stream(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	subscription, replay, ok := h.subscribe(c, userID, c.GetHeader("Last-Event-ID"))
+	if !ok {
+		return
+	}
+	defer subscription.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // disable proxy buffering (nginx)
+	c.Status(http.StatusOK)
+
+	// An id-only block sets the client's resume point without dispatching an event
+	fmt.Fprintf(c.Writer, "retry: %d\nid: %d\n\n", streamRetryMillis, subscription.LastEventID())
+	for _, event := range replay {
+		if err := writeSSEEvent(c, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(h.maxDuration)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case event := <-subscription.Events():
+			if err := writeSSEEvent(c, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprintf(c.Writer, ": heartbeat %d\n\n", time.Now().Unix()); err != nil {
+				return
+			}
+		case <-deadline.C:
+			// Clients reconnect with Last-Event-ID, which rebalances long-lived connections
+			return
+		case <-subscription.Done():
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// StreamWebSocket handles GET /api/v1/stream/ws (WebSocket)
+func (h *StreamHandler) StreamWebSocket(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	// Subscribe before the upgrade so limit errors are still plain HTTP responses
+	subscription, replay, ok := h.subscribe(c, userID, c.GetHeader("Last-Event-ID"))
+	if !ok {
+		return
+	}
+	defer subscription.Close()
+
+	server := websocket.Server{
+		Handler: func(ws *websocket.Conn) {
+			h.serveWebSocket(ws, subscription, replay)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// serveWebSocket writes events to an upgraded connection until either side closes it
+func (h *StreamHandler) serveWebSocket(ws *websocket.Conn, subscription *events.Subscription, replay []events.Event) {
+	defer ws.Close()
+
+	// Client messages are ignored; the read loop only detects the connection closing
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var message string
+		for {
+			if err := websocket.Message.Receive(ws, &message); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(frame interface{}) error {
+		ws.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		return websocket.JSON.Send(ws, frame)
+	}
+
+	if err := send(streamMessage{Type: "Connected", LastEventID: subscription.LastEventID(), Time: time.Now().UTC()}); err != nil {
+		return
+	}
+	for _, event := range replay {
+		if err := send(event); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(h.maxDuration)
+	defer deadline.Stop()
+
+	for {
+		var err error
+		select {
+		case event := <-subscription.Events():
+			err = send(event)
+		case <-heartbeat.C:
+			err = send(streamMessage{Type: "Heartbeat", Time: time.Now().UTC()})
+		case <-deadline.C:
+			return
+		case <-subscription.Done():
+			return
+		case <-closed:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// subscribe opens a broker subscription, resuming after lastEventID when it is set,
+// or responds with an error
+func (h *StreamHandler) subscribe(c *gin.Context, userID uuid.UUID, lastEventID string) (*events.Subscription, []events.Event, bool) {
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	var resumeFrom uint64
+	resume := lastEventID != ""
+	if resume {
+		parsed, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid Last-Event-ID",
+				"message": "Geçersiz olay ID",
+			})
+			return nil, nil, false
+		}
+		resumeFrom = parsed
+	}
+
+	subscription, replay, err := h.broker.Subscribe(userID, resumeFrom, resume)
+	if err != nil {
+		switch {
+		case errors.Is(err, events.ErrTooManyConnections):
+			logger.GetLogger().Warn("Stream connection limit reached",
+				zap.String("user_id", userID.String()),
+				zap.String("ip", c.ClientIP()),
+				zap.String("type", "stream_limit_exceeded"),
+			)
+			c.Header("Retry-After", streamLimitRetryAfter)
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Too many stream connections",
+				"message": "Çok fazla açık bağlantı",
+			})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Event stream unavailable",
+				"message": "Olay akışı kullanılamıyor",
+			})
+		}
+		return nil, nil, false
+	}
+
+	logger.GetLogger().Info("Stream connection opened",
+		zap.String("user_id", userID.String()),
+		zap.Bool("resume", resume),
+		zap.Int("replayed", len(replay)),
+		zap.String("type", "stream_connected"),
+	)
+	return subscription, replay, true
+}
+
+// writeSSEEvent writes one event in text/event-stream format
+func writeSSEEvent(c *gin.Context, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
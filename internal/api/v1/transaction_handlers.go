@@ -1,10 +1,14 @@
 package v1
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/barannkoca/banking-backend/internal/authz"
 	"github.com/barannkoca/banking-backend/internal/interfaces"
 	"github.com/barannkoca/banking-backend/internal/middleware"
 	"github.com/barannkoca/banking-backend/internal/models"
@@ -22,6 +26,9 @@ type TransactionHandler struct {
 	balanceService     *services.BalanceService
 	auditService       interfaces.AuditService
 	workerPool         *processing.WorkerPool
+	jobService         *services.JobService
+	statementService   *services.StatementService
+	approvalService    *services.ApprovalService
 }
 
 // NewTransactionHandler creates a new TransactionHandler instance
@@ -30,12 +37,18 @@ func NewTransactionHandler(
 	balanceService *services.BalanceService,
 	auditService interfaces.AuditService,
 	workerPool *processing.WorkerPool,
+	jobService *services.JobService,
+	statementService *services.StatementService,
+	approvalService *services.ApprovalService,
 ) *TransactionHandler {
 	return &TransactionHandler{
 		transactionService: transactionService,
 		balanceService:     balanceService,
 		auditService:       auditService,
 		workerPool:         workerPool,
+		jobService:         jobService,
+		statementService:   statementService,
+		approvalService:    approvalService,
 	}
 }
 
@@ -87,11 +100,30 @@ func (h *TransactionHandler) CreditTransaction(c *gin.Context) {
 		return
 	}
 
+	// Staff may credit other accounts within their role's limit
+	accountID := userID
+	if req.AccountID != nil {
+		accountID = *req.AccountID
+	}
+	err = h.transactionService.AuthorizeCredit(c.Request.Context(), currentSubject(c, userID), accountID, req.Amount)
+	var limitErr *authz.LimitError
+	if errors.As(err, &limitErr) {
+		// Credits above the role's limit run once a second user approves them
+		h.requestCreditApproval(c, userID, accountID, &req, limitErr.Limit)
+		return
+	}
+	if err != nil {
+		respondCreditDenied(c, userID, accountID, err)
+		return
+	}
+
 	// Create transaction job
 	job := &processing.TransactionJob{
 		ID:                 uuid.New(),
 		TransactionType:    "credit",
-		ToAccountID:        userID,
+		UserID:             userID,
+		Priority:           models.PriorityForRole(models.UserRole(c.GetString("user_role"))),
+		ToAccountID:        accountID,
 		Amount:             req.Amount,
 		TransactionService: h.transactionService,
 		BalanceService:     h.balanceService,
@@ -103,6 +135,11 @@ func (h *TransactionHandler) CreditTransaction(c *gin.Context) {
 
 	// Submit job to worker pool
 	if err := h.workerPool.SubmitJob(job); err != nil {
+		if errors.Is(err, processing.ErrQueueFull) {
+			respondQueueSaturated(c, h.workerPool.SuggestedRetryAfter())
+			return
+		}
+
 		logger.GetLogger().Error("Failed to submit credit job",
 			zap.String("user_id", userID.String()),
 			zap.Float64("amount", req.Amount),
@@ -120,6 +157,7 @@ func (h *TransactionHandler) CreditTransaction(c *gin.Context) {
 	logger.GetLogger().Info("Credit transaction submitted",
 		zap.String("job_id", job.ID.String()),
 		zap.String("user_id", userID.String()),
+		zap.String("account_id", accountID.String()),
 		zap.Float64("amount", req.Amount),
 		zap.String("ip", c.ClientIP()),
 		zap.String("type", "credit_submitted"),
@@ -128,12 +166,79 @@ func (h *TransactionHandler) CreditTransaction(c *gin.Context) {
 	c.JSON(http.StatusAccepted, gin.H{
 		"message":    "Para yatırma işlemi başlatıldı",
 		"job_id":     job.ID.String(),
+		"account_id": accountID.String(),
 		"amount":     req.Amount,
 		"status":     "processing",
 		"created_at": job.CreatedAt,
 	})
 }
 
+// requestCreditApproval submits a credit above the caller's limit for approval
+func (h *TransactionHandler) requestCreditApproval(c *gin.Context, userID, accountID uuid.UUID, req *models.DepositRequest, limit float64) {
+	reason := req.Reference
+	if reason == "" {
+		reason = fmt.Sprintf("Limit üstü para yatırma (limit %.2f)", limit)
+	}
+
+	approval, err := h.approvalService.Submit(c.Request.Context(), userID, models.ApprovalTypeCredit, accountID,
+		models.CreditPayload{AccountID: accountID, Amount: req.Amount}, reason)
+	if err != nil {
+		respondCreditDenied(c, userID, accountID, err)
+		return
+	}
+
+	logger.GetLogger().Info("Credit above role limit awaiting approval",
+		zap.String("user_id", userID.String()),
+		zap.String("account_id", accountID.String()),
+		zap.Float64("amount", req.Amount),
+		zap.Float64("limit", limit),
+		zap.String("approval_id", approval.ID.String()),
+		zap.String("type", "credit_approval_requested"),
+	)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": fmt.Sprintf("%.2f üzerindeki para yatırma işlemi ikinci bir yetkilinin onayını bekliyor", limit),
+		"limit":   limit,
+		"data":    approval.ToResponse(),
+	})
+}
+
+// respondCreditDenied maps AuthorizeCredit errors to HTTP responses
+func respondCreditDenied(c *gin.Context, userID, accountID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, authz.ErrPermissionDenied):
+		logger.GetLogger().Warn("Unauthorized credit attempt",
+			zap.String("user_id", userID.String()),
+			zap.String("account_id", accountID.String()),
+			zap.String("ip", c.ClientIP()),
+			zap.String("type", "credit_unauthorized"),
+		)
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Access denied",
+			"message": "Başka bir hesaba para yatırma izniniz yok",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Account not found",
+			"message": "Hesap bulunamadı",
+		})
+	default:
+		middleware.IncrementErrorCount(c)
+
+		logger.GetLogger().Error("Credit authorization failed",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+			zap.String("type", "credit_authorization_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to process credit transaction",
+			"message": "Para yatırma işlemi başlatılamadı",
+		})
+	}
+}
+
 // DebitTransaction handles POST /api/v1/transactions/debit
 func (h *TransactionHandler) DebitTransaction(c *gin.Context) {
 	// Get current user from context
@@ -210,6 +315,8 @@ func (h *TransactionHandler) DebitTransaction(c *gin.Context) {
 	job := &processing.TransactionJob{
 		ID:                 uuid.New(),
 		TransactionType:    "debit",
+		UserID:             userID,
+		Priority:           models.PriorityForRole(models.UserRole(c.GetString("user_role"))),
 		FromAccountID:      userID,
 		Amount:             req.Amount,
 		TransactionService: h.transactionService,
@@ -222,6 +329,11 @@ func (h *TransactionHandler) DebitTransaction(c *gin.Context) {
 
 	// Submit job to worker pool
 	if err := h.workerPool.SubmitJob(job); err != nil {
+		if errors.Is(err, processing.ErrQueueFull) {
+			respondQueueSaturated(c, h.workerPool.SuggestedRetryAfter())
+			return
+		}
+
 		logger.GetLogger().Error("Failed to submit debit job",
 			zap.String("user_id", userID.String()),
 			zap.Float64("amount", req.Amount),
@@ -338,6 +450,8 @@ func (h *TransactionHandler) TransferTransaction(c *gin.Context) {
 	job := &processing.TransactionJob{
 		ID:                 uuid.New(),
 		TransactionType:    "transfer",
+		UserID:             fromUserID,
+		Priority:           models.PriorityForRole(models.UserRole(c.GetString("user_role"))),
 		FromAccountID:      fromUserID,
 		ToAccountID:        req.ToUserID,
 		Amount:             req.Amount,
@@ -351,6 +465,11 @@ func (h *TransactionHandler) TransferTransaction(c *gin.Context) {
 
 	// Submit job to worker pool
 	if err := h.workerPool.SubmitJob(job); err != nil {
+		if errors.Is(err, processing.ErrQueueFull) {
+			respondQueueSaturated(c, h.workerPool.SuggestedRetryAfter())
+			return
+		}
+
 		logger.GetLogger().Error("Failed to submit transfer job",
 			zap.String("from_user_id", fromUserID.String()),
 			zap.String("to_user_id", req.ToUserID.String()),
@@ -386,8 +505,8 @@ func (h *TransactionHandler) TransferTransaction(c *gin.Context) {
 	})
 }
 
-// GetTransactionHistory handles GET /api/v1/transactions/history
-func (h *TransactionHandler) GetTransactionHistory(c *gin.Context) {
+// SubmitAsyncTransaction handles POST /api/v1/transactions/async/{credit|debit|transfer}
+func (h *TransactionHandler) SubmitAsyncTransaction(c *gin.Context) {
 	// Get current user from context
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
@@ -398,20 +517,155 @@ func (h *TransactionHandler) GetTransactionHistory(c *gin.Context) {
 		return
 	}
 
-	// Parse query parameters
-	limitStr := c.DefaultQuery("limit", "50")
-	offsetStr := c.DefaultQuery("offset", "0")
-	transactionType := c.Query("type")
-	status := c.Query("status")
+	// Validate job type from URL parameter
+	jobType := c.Param("type")
+	if !models.IsValidJobType(jobType) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Unsupported transaction type",
+			"message": "Desteklenmeyen işlem türü",
+		})
+		return
+	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 || limit > 100 {
-		limit = 50
+	// Parse request body
+	var req models.AsyncTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.GetLogger().Warn("Invalid async transaction request",
+			zap.String("user_id", currentUserID.(string)),
+			zap.String("transaction_type", jobType),
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "async_transaction_validation_error"),
+		)
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Geçersiz işlem verisi",
+		})
+		return
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	// Parse user ID
+	userID, err := uuid.Parse(currentUserID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid user ID",
+			"message": "Geçersiz kullanıcı ID'si",
+		})
+		return
+	}
+
+	// Teller and admin operations are scheduled ahead of customer traffic
+	job := &models.Job{
+		UserID:    userID,
+		Type:      models.JobType(jobType),
+		Amount:    req.Amount,
+		Reference: req.Reference,
+		Priority:  models.PriorityForRole(models.UserRole(c.GetString("user_role"))),
+	}
+
+	switch job.Type {
+	case models.JobTypeCredit:
+		job.ToAccountID = &userID
+	case models.JobTypeDebit:
+		job.FromAccountID = &userID
+	case models.JobTypeTransfer:
+		if req.ToUserID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Recipient required",
+				"message": "Transfer için alıcı kullanıcı gereklidir",
+			})
+			return
+		}
+		if *req.ToUserID == userID {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Cannot transfer to same account",
+				"message": "Kendinize transfer yapamazsınız",
+			})
+			return
+		}
+		job.FromAccountID = &userID
+		job.ToAccountID = req.ToUserID
+	}
+
+	// Debits and transfers require sufficient balance upfront
+	if job.FromAccountID != nil {
+		canPerform, err := h.transactionService.CanPerformTransaction(c.Request.Context(), userID, req.Amount)
+		if err != nil {
+			logger.GetLogger().Error("Failed to check balance",
+				zap.String("user_id", userID.String()),
+				zap.Error(err),
+				zap.String("type", "balance_check_error"),
+			)
+
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to check balance",
+				"message": "Bakiye kontrol edilemedi",
+			})
+			return
+		}
+
+		if !canPerform {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Insufficient balance",
+				"message": "Yetersiz bakiye",
+			})
+			return
+		}
+	}
+
+	// Persist and submit the job
+	if err := h.jobService.SubmitJob(c.Request.Context(), job); err != nil {
+		if errors.Is(err, processing.ErrQueueFull) {
+			respondQueueSaturated(c, h.workerPool.SuggestedRetryAfter())
+			return
+		}
+
+		logger.GetLogger().Error("Failed to submit async transaction job",
+			zap.String("user_id", userID.String()),
+			zap.String("transaction_type", jobType),
+			zap.Float64("amount", req.Amount),
+			zap.Error(err),
+			zap.String("type", "async_job_submit_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to submit transaction",
+			"message": "İşlem başlatılamadı",
+		})
+		return
+	}
+
+	logger.GetLogger().Info("Async transaction submitted",
+		zap.String("job_id", job.ID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("transaction_type", jobType),
+		zap.Float64("amount", req.Amount),
+		zap.String("ip", c.ClientIP()),
+		zap.String("type", "async_transaction_submitted"),
+	)
+
+	statusURL := "/api/v1/jobs/" + job.ID.String()
+	c.Header("Location", statusURL)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":    "İşlem kuyruğa alındı",
+		"job_id":     job.ID.String(),
+		"status":     job.Status,
+		"status_url": statusURL,
+		"created_at": job.CreatedAt,
+	})
+}
+
+// GetTransactionHistory handles GET /api/v1/transactions/history
+func (h *TransactionHandler) GetTransactionHistory(c *gin.Context) {
+	// Get current user from context
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authentication required",
+			"message": "Kimlik doğrulama gerekli",
+		})
+		return
 	}
 
 	// Parse user ID
@@ -424,9 +678,28 @@ func (h *TransactionHandler) GetTransactionHistory(c *gin.Context) {
 		return
 	}
 
+	// Serve a camt.053 statement of the last 30 days when the client asks for one
+	if wantsStatement(c) {
+		now := time.Now()
+		respondStatement(c, h.statementService, userID, now.AddDate(0, 0, -30), now)
+		return
+	}
+
+	// Parse filters, sorting and cursor
+	query, err := parseTransactionQuery(c)
+	if err != nil {
+		respondInvalidTransactionQuery(c, err)
+		return
+	}
+
 	// Get transaction history from service
-	transactions, err := h.transactionService.GetTransactionHistory(c.Request.Context(), userID, limit, offset, transactionType, status)
+	page, err := h.transactionService.GetTransactionHistory(c.Request.Context(), userID, query)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidTransactionQuery) || errors.Is(err, models.ErrInvalidCursor) {
+			respondInvalidTransactionQuery(c, err)
+			return
+		}
+
 		// Increment error count for performance monitoring
 		middleware.IncrementErrorCount(c)
 
@@ -444,14 +717,15 @@ func (h *TransactionHandler) GetTransactionHistory(c *gin.Context) {
 	}
 
 	// Convert to response format
-	var transactionResponses []*models.TransactionResponse
-	for _, transaction := range transactions {
+	transactionResponses := make([]*models.TransactionResponse, 0, len(page.Transactions))
+	for _, transaction := range page.Transactions {
 		transactionResponses = append(transactionResponses, transaction.ToResponse())
 	}
 
 	logger.GetLogger().Info("Transaction history retrieved",
 		zap.String("user_id", userID.String()),
 		zap.Int("count", len(transactionResponses)),
+		zap.Bool("has_more", page.HasMore),
 		zap.String("ip", c.ClientIP()),
 		zap.String("type", "transaction_history_success"),
 	)
@@ -460,13 +734,28 @@ func (h *TransactionHandler) GetTransactionHistory(c *gin.Context) {
 		"message": "İşlem geçmişi başarıyla getirildi",
 		"data":    transactionResponses,
 		"pagination": gin.H{
-			"limit":  limit,
-			"offset": offset,
-			"count":  len(transactionResponses),
+			"limit":       query.Limit,
+			"count":       len(transactionResponses),
+			"has_more":    page.HasMore,
+			"next_cursor": page.NextCursor,
+			"sort":        query.SortBy,
+			"order":       query.SortDir,
 		},
 	})
 }
 
+// respondInvalidTransactionQuery reports a malformed filter, sort or cursor parameter
+func respondInvalidTransactionQuery(c *gin.Context, err error) {
+	message := "Geçersiz sorgu parametresi"
+	if errors.Is(err, models.ErrInvalidCursor) {
+		message = "Geçersiz sayfalama imleci"
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":   err.Error(),
+		"message": message,
+	})
+}
+
 // GetTransaction handles GET /api/v1/transactions/{id}
 func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 	// Get current user from context
@@ -523,9 +812,8 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 		return
 	}
 
-	// Check if user has access to this transaction
-	if (transaction.FromUserID != nil && *transaction.FromUserID != userID) &&
-		(transaction.ToUserID != nil && *transaction.ToUserID != userID) {
+	// Parties to the transaction and staff who can read any account have access
+	if !transaction.IsParty(userID) && !authz.HasPermission(models.UserRole(c.GetString("user_role")), authz.AccountsRead) {
 		logger.GetLogger().Warn("Unauthorized transaction access attempt",
 			zap.String("user_id", userID.String()),
 			zap.String("transaction_id", transactionID.String()),
@@ -552,3 +840,18 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 		"data":    transaction.ToResponse(),
 	})
 }
+
+// respondQueueSaturated tells the client to back off while the worker pool drains its backlog
+func respondQueueSaturated(c *gin.Context, retryAfter time.Duration) {
+	logger.GetLogger().Warn("Job queue saturated, rejecting request",
+		zap.Duration("retry_after", retryAfter),
+		zap.String("ip", c.ClientIP()),
+		zap.String("type", "job_queue_saturated"),
+	)
+
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error":   "Job queue is full",
+		"message": "İş kuyruğu dolu, lütfen daha sonra tekrar deneyin",
+	})
+}
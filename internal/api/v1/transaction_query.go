@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// parseTransactionQuery builds a transaction query from the request's query parameters:
+// limit, cursor, type, status, from, to, min_amount, max_amount, counterparty, reference,
+// sort and order. type and status accept comma-separated lists.
+func parseTransactionQuery(c *gin.Context) (*models.TransactionQuery, error) {
+	query := models.NewTransactionQuery()
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("%w: limit must be a positive integer", models.ErrInvalidTransactionQuery)
+		}
+		query.Limit = limit
+	}
+
+	if value := c.Query("cursor"); value != "" {
+		cursor, err := models.DecodeTransactionCursor(value)
+		if err != nil {
+			return nil, err
+		}
+		query.Cursor = cursor
+	}
+
+	for _, value := range splitList(c.Query("type")) {
+		query.Filter.Types = append(query.Filter.Types, models.TransactionType(value))
+	}
+	for _, value := range splitList(c.Query("status")) {
+		query.Filter.Statuses = append(query.Filter.Statuses, models.TransactionStatus(value))
+	}
+
+	if value := c.Query("from"); value != "" {
+		from, ok := parseDateOrTime(value, time.Time{}, false)
+		if !ok {
+			return nil, fmt.Errorf("%w: from must be YYYY-MM-DD or RFC3339", models.ErrInvalidTransactionQuery)
+		}
+		query.Filter.From = &from
+	}
+	if value := c.Query("to"); value != "" {
+		to, ok := parseDateOrTime(value, time.Time{}, true)
+		if !ok {
+			return nil, fmt.Errorf("%w: to must be YYYY-MM-DD or RFC3339", models.ErrInvalidTransactionQuery)
+		}
+		query.Filter.To = &to
+	}
+
+	var err error
+	if query.Filter.MinAmount, err = parseAmountParam(c, "min_amount"); err != nil {
+		return nil, err
+	}
+	if query.Filter.MaxAmount, err = parseAmountParam(c, "max_amount"); err != nil {
+		return nil, err
+	}
+
+	if value := c.Query("counterparty"); value != "" {
+		counterpartyID, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: counterparty must be a user ID", models.ErrInvalidTransactionQuery)
+		}
+		query.Filter.CounterpartyID = &counterpartyID
+	}
+
+	query.Filter.Reference = strings.TrimSpace(c.Query("reference"))
+
+	if value := c.Query("sort"); value != "" {
+		query.SortBy = models.TransactionSortField(value)
+	}
+	if value := c.Query("order"); value != "" {
+		query.SortDir = models.SortDirection(strings.ToLower(value))
+	}
+
+	return query, nil
+}
+
+// parseAmountParam parses an optional non-negative amount query parameter
+func parseAmountParam(c *gin.Context, name string) (*float64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return nil, fmt.Errorf("%w: %s must be a non-negative number", models.ErrInvalidTransactionQuery, name)
+	}
+	return &amount, nil
+}
+
+// splitList splits a comma-separated query parameter, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
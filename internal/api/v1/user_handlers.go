@@ -1,8 +1,10 @@
 package v1
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/barannkoca/banking-backend/internal/authz"
 	"github.com/barannkoca/banking-backend/internal/models"
 	"github.com/barannkoca/banking-backend/internal/services"
 	"github.com/barannkoca/banking-backend/pkg/logger"
@@ -13,13 +15,15 @@ import (
 
 // UserHandler handles user management requests
 type UserHandler struct {
-	userService *services.UserService
+	userService     *services.UserService
+	approvalService *services.ApprovalService
 }
 
 // NewUserHandler creates a new UserHandler instance
-func NewUserHandler(userService *services.UserService) *UserHandler {
+func NewUserHandler(userService *services.UserService, approvalService *services.ApprovalService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:     userService,
+		approvalService: approvalService,
 	}
 }
 
@@ -103,8 +107,9 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	// Authorization check: user can only access their own data unless they're admin
-	if currentUserUUID != userID && currentUser.Role != models.RoleAdmin {
+	// Authorization check: user can only access their own data unless permitted
+	subject := authz.Subject{UserID: currentUserUUID, Role: currentUser.Role}
+	if !authz.Allowed(subject, authz.UsersRead, userID) {
 		logger.GetLogger().Warn("Unauthorized user access attempt",
 			zap.String("current_user_id", currentUserIDStr),
 			zap.String("requested_user_id", userID.String()),
@@ -191,8 +196,9 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	// Authorization check: user can only update their own data unless they're admin
-	if currentUserUUID != userID && currentUser.Role != models.RoleAdmin {
+	// Authorization check: user can only update their own data unless permitted
+	subject := authz.Subject{UserID: currentUserUUID, Role: currentUser.Role}
+	if !authz.Allowed(subject, authz.UsersUpdate, userID) {
 		logger.GetLogger().Warn("Unauthorized user update attempt",
 			zap.String("current_user_id", currentUserIDStr),
 			zap.String("requested_user_id", userID.String()),
@@ -242,16 +248,24 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		existingUser.Email = req.Email
 	}
 	if req.Role != "" {
-		// Only admin can change roles
-		if currentUser.Role != models.RoleAdmin {
+		// An unknown role is rejected before it reaches an approver
+		if !models.IsValidRole(req.Role) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid role",
+				"message": "Geçersiz kullanıcı rolü",
+			})
+			return
+		}
+		// Role changes need their own permission, even on one's own account
+		if !authz.HasPermission(currentUser.Role, authz.UsersManageRoles) {
 			c.JSON(http.StatusForbidden, gin.H{
 				"error":   "Role change not allowed",
 				"message": "Rol değişikliği izni yok",
 			})
 			return
 		}
-		existingUser.Role = models.UserRole(req.Role)
 	}
+	oldRole := existingUser.Role
 
 	// Update user
 	err = h.userService.UpdateUser(c.Request.Context(), existingUser)
@@ -276,9 +290,40 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		zap.String("type", "user_update_success"),
 	)
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Kullanıcı başarıyla güncellendi",
-		"data":    existingUser.ToResponse(),
+	// The role changes only once a second role manager approves
+	if req.Role == "" || models.UserRole(req.Role) == oldRole {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Kullanıcı başarıyla güncellendi",
+			"data":    existingUser.ToResponse(),
+		})
+		return
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("Rol değişikliği: %s -> %s", oldRole, req.Role)
+	}
+	approval, err := h.approvalService.Submit(c.Request.Context(), currentUserUUID, models.ApprovalTypeRoleChange, userID,
+		models.RoleChangePayload{UserID: userID, Role: models.UserRole(req.Role)}, reason)
+	if err != nil {
+		logger.GetLogger().Error("Failed to request role change",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+			zap.String("ip", c.ClientIP()),
+			zap.String("type", "role_change_request_error"),
+		)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to request role change",
+			"message": "Rol değişikliği talebi oluşturulamadı",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":  "Kullanıcı güncellendi, rol değişikliği ikinci bir yetkilinin onayını bekliyor",
+		"data":     existingUser.ToResponse(),
+		"approval": approval.ToResponse(),
 	})
 }
 
@@ -325,8 +370,9 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	// Authorization check: user can only delete their own account unless they're admin
-	if currentUserUUID != userID && currentUser.Role != models.RoleAdmin {
+	// Authorization check: user can only delete their own account unless permitted
+	subject := authz.Subject{UserID: currentUserUUID, Role: currentUser.Role}
+	if !authz.Allowed(subject, authz.UsersDelete, userID) {
 		logger.GetLogger().Warn("Unauthorized user deletion attempt",
 			zap.String("current_user_id", currentUserIDStr),
 			zap.String("requested_user_id", userID.String()),
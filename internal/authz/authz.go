@@ -0,0 +1,140 @@
+// Package authz maps roles to permissions and evaluates access to resources. Users may
+// always act on resources they own; permissions grant the same operation on other users'
+// resources, optionally capped per role by an amount limit.
+package authz
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// Permission is an operation a role may perform on resources owned by other users
+type Permission string
+
+const (
+	UsersList        Permission = "users.list"
+	UsersRead        Permission = "users.read"
+	UsersUpdate      Permission = "users.update"
+	UsersDelete      Permission = "users.delete"
+	UsersManageRoles Permission = "users.manage_roles"
+	// AccountsRead covers balances, transactions, jobs, batches, imports and search results
+	AccountsRead Permission = "accounts.read"
+	// AccountsCredit allows crediting another user's account, subject to the role's limit
+	AccountsCredit   Permission = "accounts.credit"
+	AccountsRefund   Permission = "accounts.refund"
+	AccountsFreeze   Permission = "accounts.freeze"
+	SessionsManage   Permission = "sessions.manage"
+	SecurityManage   Permission = "security.manage" // MFA resets and login lockouts
+	JobsManage       Permission = "jobs.manage"     // dead-letter jobs
+	APIClientsManage Permission = "api_clients.manage"
+	AuditRead        Permission = "audit.read"
+	SystemMaintain   Permission = "system.maintain"
+	// ApprovalsDecide allows approving or rejecting other users' requests; the checker also
+	// needs the permission of the operation itself
+	ApprovalsDecide Permission = "approvals.decide"
+)
+
+// rolePermissions lists what each role may do beyond its own resources. Customers only
+// act on their own resources.
+var rolePermissions = map[models.UserRole][]Permission{
+	models.RoleCustomer: {},
+	models.RoleTeller: {
+		UsersList, UsersRead,
+		AccountsRead, AccountsCredit, AccountsRefund, AccountsFreeze,
+		ApprovalsDecide,
+	},
+	models.RoleAdmin: {
+		UsersList, UsersRead, UsersUpdate, UsersDelete, UsersManageRoles,
+		AccountsRead, AccountsCredit, AccountsRefund, AccountsFreeze,
+		SessionsManage, SecurityManage, JobsManage, APIClientsManage, AuditRead, SystemMaintain,
+		ApprovalsDecide,
+	},
+}
+
+// ErrPermissionDenied is returned when the subject lacks the permission for a resource
+var ErrPermissionDenied = errors.New("permission denied")
+
+// LimitError is returned when an amount exceeds the subject's role limit for the
+// permission. It matches ErrPermissionDenied with errors.Is.
+type LimitError struct {
+	Permission Permission
+	Limit      float64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s limited to %.2f", e.Permission, e.Limit)
+}
+
+// Unwrap makes a LimitError a permission denial
+func (e *LimitError) Unwrap() error {
+	return ErrPermissionDenied
+}
+
+// Subject is the user an access decision is made for
+type Subject struct {
+	UserID uuid.UUID
+	Role   models.UserRole
+}
+
+// Resource is what the subject acts on. OwnerID is uuid.Nil for resources without an
+// owner; Amount is only compared against limits.
+type Resource struct {
+	OwnerID uuid.UUID
+	Amount  float64
+}
+
+// HasPermission reports whether the role holds the permission
+func HasPermission(role models.UserRole, permission Permission) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RolesWith returns the roles holding the permission
+func RolesWith(permission Permission) []models.UserRole {
+	var roles []models.UserRole
+	for role := range rolePermissions {
+		if HasPermission(role, permission) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// Allowed reports whether the subject owns the resource or holds the permission.
+// Amount limits are checked by Policy.Evaluate.
+func Allowed(subject Subject, permission Permission, ownerID uuid.UUID) bool {
+	return (ownerID != uuid.Nil && ownerID == subject.UserID) || HasPermission(subject.Role, permission)
+}
+
+// Policy evaluates permissions with per-role amount limits
+type Policy struct {
+	limits map[Permission]map[models.UserRole]float64
+}
+
+// NewPolicy creates a Policy. Roles without a limit for a permission are not capped.
+func NewPolicy(limits map[Permission]map[models.UserRole]float64) *Policy {
+	return &Policy{limits: limits}
+}
+
+// Evaluate returns nil if the subject may act on the resource, ErrPermissionDenied if not,
+// or a *LimitError if the amount exceeds the role's limit. Limits do not apply to the
+// subject's own resources.
+func (p *Policy) Evaluate(subject Subject, permission Permission, resource Resource) error {
+	if resource.OwnerID != uuid.Nil && resource.OwnerID == subject.UserID {
+		return nil
+	}
+	if !HasPermission(subject.Role, permission) {
+		return ErrPermissionDenied
+	}
+	if limit, ok := p.limits[permission][subject.Role]; ok && resource.Amount > limit {
+		return &LimitError{Permission: permission, Limit: limit}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package authz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestAllowedOwnsOrHoldsPermission(t *testing.T) {
+	customer := Subject{UserID: uuid.New(), Role: models.RoleCustomer}
+	teller := Subject{UserID: uuid.New(), Role: models.RoleTeller}
+	other := uuid.New()
+
+	if !Allowed(customer, UsersRead, customer.UserID) {
+		t.Error("customer denied own profile")
+	}
+	if Allowed(customer, UsersRead, other) {
+		t.Error("customer allowed another user's profile")
+	}
+	if Allowed(customer, UsersList, uuid.Nil) {
+		t.Error("customer allowed an unowned resource")
+	}
+	if !Allowed(teller, AccountsRead, other) {
+		t.Error("teller denied another user's account")
+	}
+	if Allowed(teller, UsersUpdate, other) {
+		t.Error("teller allowed to update another user")
+	}
+}
+
+func TestPolicyEvaluateAppliesRoleLimits(t *testing.T) {
+	policy := NewPolicy(map[Permission]map[models.UserRole]float64{
+		AccountsCredit: {models.RoleTeller: 1000},
+	})
+	teller := Subject{UserID: uuid.New(), Role: models.RoleTeller}
+	admin := Subject{UserID: uuid.New(), Role: models.RoleAdmin}
+	customer := Subject{UserID: uuid.New(), Role: models.RoleCustomer}
+	account := uuid.New()
+
+	if err := policy.Evaluate(teller, AccountsCredit, Resource{OwnerID: account, Amount: 1000}); err != nil {
+		t.Errorf("teller credit at limit: %v", err)
+	}
+
+	err := policy.Evaluate(teller, AccountsCredit, Resource{OwnerID: account, Amount: 1000.01})
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != 1000 {
+		t.Fatalf("teller credit above limit: got %v, want LimitError", err)
+	}
+	if !errors.Is(err, ErrPermissionDenied) {
+		t.Error("LimitError does not match ErrPermissionDenied")
+	}
+
+	if err := policy.Evaluate(admin, AccountsCredit, Resource{OwnerID: account, Amount: 1e9}); err != nil {
+		t.Errorf("admin credit without limit: %v", err)
+	}
+	if err := policy.Evaluate(customer, AccountsCredit, Resource{OwnerID: account, Amount: 1}); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("customer credit to another account: got %v, want ErrPermissionDenied", err)
+	}
+	if err := policy.Evaluate(customer, AccountsCredit, Resource{OwnerID: customer.UserID, Amount: 1e9}); err != nil {
+		t.Errorf("customer credit to own account: %v", err)
+	}
+}
+
+func TestRolesWithApprovalsDecide(t *testing.T) {
+	roles := RolesWith(ApprovalsDecide)
+	granted := map[models.UserRole]bool{}
+	for _, role := range roles {
+		granted[role] = true
+	}
+	if len(roles) != 2 || !granted[models.RoleAdmin] || !granted[models.RoleTeller] {
+		t.Errorf("RolesWith(ApprovalsDecide) = %v, want admin and teller", roles)
+	}
+}
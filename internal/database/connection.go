@@ -75,11 +75,36 @@ func AutoMigrate() error {
 		&models.Transaction{},
 		&models.Balance{},
 		&models.AuditLog{},
+		&models.Job{},
+		&models.QueuedJob{},
+		&models.DeadLetterJob{},
+		&models.Batch{},
+		&models.BatchItem{},
+		&models.PaymentImport{},
+		&models.PaymentImportRow{},
+		&models.CategoryRule{},
+		&models.TokenFamily{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+		&models.UserMFA{},
+		&models.MFARecoveryCode{},
+		&models.LoginThrottle{},
+		&models.StepUpChallengeFailure{},
+		&models.AccountToken{},
+		&models.PasswordHistory{},
+		&models.JWTSigningKey{},
+		&models.APIClient{},
+		&models.APIKey{},
+		&models.ApprovalRequest{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to auto migrate: %w", err)
 	}
 
+	if err := migrateSearch(); err != nil {
+		return err
+	}
+
 	log.Println("✅ Database migration completed successfully")
 	return nil
 }
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"fmt"
+	"log"
+)
+
+// SearchConfiguration is the text search configuration used for search vectors and queries.
+// The language-neutral "simple" configuration matches references such as invoice numbers
+// verbatim and works for both Turkish and English text. It must match the configuration
+// used in searchMigrations.
+const SearchConfiguration = "simple"
+
+// searchMigrations add tsvector columns with GIN indexes to transactions and audit logs.
+// Transaction vectors include the counterparty usernames, so they are kept up to date by
+// triggers instead of a generated column. Every statement is idempotent.
+var searchMigrations = []string{
+	`ALTER TABLE transactions ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+	`CREATE OR REPLACE FUNCTION transactions_search_vector_update() RETURNS trigger AS $$
+	BEGIN
+		NEW.search_vector :=
+			setweight(to_tsvector('simple', coalesce(NEW.reference, '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce((SELECT username FROM users WHERE id = NEW.from_user_id), '')), 'B') ||
+			setweight(to_tsvector('simple', coalesce((SELECT username FROM users WHERE id = NEW.to_user_id), '')), 'B');
+		RETURN NEW;
+	END
+	$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS transactions_search_vector_trigger ON transactions`,
+	`CREATE TRIGGER transactions_search_vector_trigger
+		BEFORE INSERT OR UPDATE OF reference, from_user_id, to_user_id ON transactions
+		FOR EACH ROW EXECUTE FUNCTION transactions_search_vector_update()`,
+	`CREATE OR REPLACE FUNCTION users_username_search_update() RETURNS trigger AS $$
+	BEGIN
+		UPDATE transactions SET reference = reference WHERE from_user_id = NEW.id OR to_user_id = NEW.id;
+		RETURN NEW;
+	END
+	$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS users_username_search_trigger ON users`,
+	`CREATE TRIGGER users_username_search_trigger
+		AFTER UPDATE OF username ON users
+		FOR EACH ROW WHEN (OLD.username IS DISTINCT FROM NEW.username)
+		EXECUTE FUNCTION users_username_search_update()`,
+	`UPDATE transactions SET reference = reference WHERE search_vector IS NULL`,
+	`CREATE INDEX IF NOT EXISTS idx_transactions_search_vector ON transactions USING GIN (search_vector)`,
+
+	`ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('simple', coalesce(details, '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce(action, '')), 'B')
+		) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_audit_logs_search_vector ON audit_logs USING GIN (search_vector)`,
+}
+
+// migrateSearch creates the full-text search columns, triggers and indexes
+func migrateSearch() error {
+	for _, statement := range searchMigrations {
+		if err := DB.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to migrate search indexes: %w", err)
+		}
+	}
+
+	log.Println("✅ Full-text search indexes are ready")
+	return nil
+}
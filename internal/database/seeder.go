@@ -3,6 +3,7 @@ package database
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/barannkoca/banking-backend/internal/models"
 	"github.com/google/uuid"
@@ -25,14 +26,19 @@ func SeedData() error {
 
 	log.Println("🌱 Seeding database...")
 
+	// Seeded accounts start with verified email addresses
+	now := time.Now()
+
 	// Create admin user
 	adminPassword, _ := bcrypt.GenerateFromPassword([]byte("admin123"), bcrypt.DefaultCost)
 	adminUser := &models.User{
-		ID:           uuid.New(),
-		Username:     "admin",
-		Email:        "admin@banking.com",
-		PasswordHash: string(adminPassword),
-		Role:         models.RoleAdmin,
+		ID:                uuid.New(),
+		Username:          "admin",
+		Email:             "admin@banking.com",
+		EmailVerifiedAt:   &now,
+		PasswordChangedAt: &now,
+		PasswordHash:      string(adminPassword),
+		Role:              models.RoleAdmin,
 	}
 
 	if err := DB.Create(adminUser).Error; err != nil {
@@ -51,11 +57,13 @@ func SeedData() error {
 	// Create test customer
 	customerPassword, _ := bcrypt.GenerateFromPassword([]byte("customer123"), bcrypt.DefaultCost)
 	customerUser := &models.User{
-		ID:           uuid.New(),
-		Username:     "johndoe",
-		Email:        "john@example.com",
-		PasswordHash: string(customerPassword),
-		Role:         models.RoleCustomer,
+		ID:                uuid.New(),
+		Username:          "johndoe",
+		Email:             "john@example.com",
+		EmailVerifiedAt:   &now,
+		PasswordChangedAt: &now,
+		PasswordHash:      string(customerPassword),
+		Role:              models.RoleCustomer,
 	}
 
 	if err := DB.Create(customerUser).Error; err != nil {
@@ -74,11 +82,13 @@ func SeedData() error {
 	// Create another test customer
 	customer2Password, _ := bcrypt.GenerateFromPassword([]byte("customer456"), bcrypt.DefaultCost)
 	customer2User := &models.User{
-		ID:           uuid.New(),
-		Username:     "janedoe",
-		Email:        "jane@example.com",
-		PasswordHash: string(customer2Password),
-		Role:         models.RoleCustomer,
+		ID:                uuid.New(),
+		Username:          "janedoe",
+		Email:             "jane@example.com",
+		EmailVerifiedAt:   &now,
+		PasswordChangedAt: &now,
+		PasswordHash:      string(customer2Password),
+		Role:              models.RoleCustomer,
 	}
 
 	if err := DB.Create(customer2User).Error; err != nil {
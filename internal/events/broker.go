@@ -0,0 +1,249 @@
+// Package events provides the in-process publish/subscribe broker behind the real-time
+// balance and transaction stream.
+package events
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// EventType names a stream event
+type EventType string
+
+const (
+	EventBalanceChanged           EventType = "BalanceChanged"
+	EventTransactionStatusChanged EventType = "TransactionStatusChanged"
+	// EventStreamReset tells a resuming client that events were missed and it should reload its state
+	EventStreamReset EventType = "StreamReset"
+)
+
+var (
+	// ErrTooManyConnections is returned when a user or the server is at its connection limit
+	ErrTooManyConnections = errors.New("too many stream connections")
+	// ErrBrokerClosed is returned when subscribing after the broker shut down
+	ErrBrokerClosed = errors.New("event broker closed")
+)
+
+// Event is a single message delivered to one user
+type Event struct {
+	ID        uint64      `json:"id"`
+	Type      EventType   `json:"type"`
+	Data      interface{} `json:"data,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// BalanceChanged is the payload of EventBalanceChanged
+type BalanceChanged struct {
+	UserID        uuid.UUID  `json:"user_id"`
+	Balance       float64    `json:"balance"`
+	TransactionID *uuid.UUID `json:"transaction_id,omitempty"`
+}
+
+// TransactionStatusChanged is the payload of EventTransactionStatusChanged
+type TransactionStatusChanged struct {
+	TransactionID uuid.UUID  `json:"transaction_id"`
+	Type          string     `json:"type"`
+	Status        string     `json:"status"`
+	Amount        float64    `json:"amount"`
+	FromUserID    *uuid.UUID `json:"from_user_id,omitempty"`
+	ToUserID      *uuid.UUID `json:"to_user_id,omitempty"`
+	Category      string     `json:"category,omitempty"`
+}
+
+// Config holds broker limits
+type Config struct {
+	MaxConnectionsPerUser int
+	MaxConnections        int
+	SendBufferSize        int
+	ReplayBufferSize      int
+}
+
+// Broker fans events out to the subscriptions of their user and keeps a short per-user
+// history so reconnecting clients can resume from their last event ID
+type Broker struct {
+	config Config
+	logger *zap.Logger
+
+	mu          sync.Mutex
+	closed      bool
+	startID     uint64
+	lastID      uint64
+	history     map[uuid.UUID][]Event
+	evictedUpTo map[uuid.UUID]uint64
+	subscribers map[uuid.UUID]map[*Subscription]struct{}
+	connections int
+}
+
+// NewBroker creates a new Broker. Event IDs start from the current time in microseconds so
+// IDs from before a restart are never mistaken for IDs of this run.
+func NewBroker(config Config, logger *zap.Logger) *Broker {
+	startID := uint64(time.Now().UnixMicro())
+	return &Broker{
+		config:      config,
+		logger:      logger,
+		startID:     startID,
+		lastID:      startID,
+		history:     make(map[uuid.UUID][]Event),
+		evictedUpTo: make(map[uuid.UUID]uint64),
+		subscribers: make(map[uuid.UUID]map[*Subscription]struct{}),
+	}
+}
+
+// Publish records an event for a user and delivers it to the user's subscriptions.
+// Subscriptions that cannot keep up are dropped instead of blocking the publisher.
+func (b *Broker) Publish(userID uuid.UUID, eventType EventType, data interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	b.lastID++
+	event := Event{ID: b.lastID, Type: eventType, Data: data, CreatedAt: time.Now().UTC()}
+
+	if b.config.ReplayBufferSize > 0 {
+		history := append(b.history[userID], event)
+		if overflow := len(history) - b.config.ReplayBufferSize; overflow > 0 {
+			b.evictedUpTo[userID] = history[overflow-1].ID
+			history = append([]Event(nil), history[overflow:]...)
+		}
+		b.history[userID] = history
+	} else {
+		b.evictedUpTo[userID] = event.ID
+	}
+
+	for subscription := range b.subscribers[userID] {
+		select {
+		case subscription.events <- event:
+		default:
+			b.logger.Warn("Dropping slow stream subscriber",
+				zap.String("user_id", userID.String()),
+				zap.Uint64("event_id", event.ID))
+			b.removeLocked(subscription)
+		}
+	}
+}
+
+// Subscribe opens a subscription for a user. When resume is set, the user's events after
+// lastEventID are returned for replay; if some of them are no longer available the replay
+// is a single StreamReset event instead, telling the client to reload its state.
+func (b *Broker) Subscribe(userID uuid.UUID, lastEventID uint64, resume bool) (*Subscription, []Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, nil, ErrBrokerClosed
+	}
+	if b.connections >= b.config.MaxConnections || len(b.subscribers[userID]) >= b.config.MaxConnectionsPerUser {
+		return nil, nil, ErrTooManyConnections
+	}
+
+	var replay []Event
+	if resume {
+		oldest := b.startID
+		if evicted := b.evictedUpTo[userID]; evicted > oldest {
+			oldest = evicted
+		}
+		if lastEventID < oldest || lastEventID > b.lastID {
+			replay = []Event{{ID: b.lastID, Type: EventStreamReset, CreatedAt: time.Now().UTC()}}
+		} else {
+			for _, event := range b.history[userID] {
+				if event.ID > lastEventID {
+					replay = append(replay, event)
+				}
+			}
+		}
+	}
+
+	subscription := &Subscription{
+		broker:      b,
+		userID:      userID,
+		lastEventID: b.lastID,
+		events:      make(chan Event, b.config.SendBufferSize),
+		done:        make(chan struct{}),
+	}
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[userID][subscription] = struct{}{}
+	b.connections++
+
+	return subscription, replay, nil
+}
+
+// Connections returns the number of open subscriptions
+func (b *Broker) Connections() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.connections
+}
+
+// Close ends every subscription and rejects new ones, letting streaming requests finish
+// during a graceful shutdown
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for _, subscriptions := range b.subscribers {
+		for subscription := range subscriptions {
+			b.removeLocked(subscription)
+		}
+	}
+}
+
+// removeLocked detaches a subscription and signals its owner; b.mu must be held
+func (b *Broker) removeLocked(subscription *Subscription) {
+	subscriptions, ok := b.subscribers[subscription.userID]
+	if !ok {
+		return
+	}
+	if _, ok := subscriptions[subscription]; !ok {
+		return
+	}
+	delete(subscriptions, subscription)
+	if len(subscriptions) == 0 {
+		delete(b.subscribers, subscription.userID)
+	}
+	b.connections--
+	close(subscription.done)
+}
+
+// Subscription receives the events of one user for one connection
+type Subscription struct {
+	broker      *Broker
+	userID      uuid.UUID
+	lastEventID uint64
+	events      chan Event
+	done        chan struct{}
+}
+
+// LastEventID is the newest event ID at the time of subscribing. A client that reconnects
+// with it misses nothing, even if it received no events on this connection.
+func (s *Subscription) LastEventID() uint64 {
+	return s.lastEventID
+}
+
+// Events delivers published events in order
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Done is closed when the broker drops the subscription, because the client was too slow
+// or the broker shut down
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close releases the subscription; it is safe to call more than once
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.removeLocked(s)
+}
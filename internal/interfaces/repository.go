@@ -38,7 +38,7 @@ type UserRepository interface {
 type TransactionRepository interface {
 	Save(ctx context.Context, tx *models.Transaction) error
 	FindByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error)
-	FindByAccount(ctx context.Context, accountID uuid.UUID) ([]*models.Transaction, error)
+	FindByAccount(ctx context.Context, accountID uuid.UUID, query *models.TransactionQuery) ([]*models.Transaction, error)
 	UpdateStatus(ctx context.Context, id uuid.UUID, status models.TransactionStatus) error
 }
 
@@ -49,6 +49,45 @@ type BalanceRepository interface {
 	SaveBalanceHistory(ctx context.Context, accountID uuid.UUID, amount float64, timestamp time.Time) error
 }
 
+// JobRepository defines the interface for asynchronous job data operations
+type JobRepository interface {
+	Create(ctx context.Context, job *models.Job) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Job, error)
+	FindByStatus(ctx context.Context, status models.JobStatus, limit int) ([]*models.Job, error)
+	MarkProcessing(ctx context.Context, id uuid.UUID, retryCount int) error
+	MarkCompleted(ctx context.Context, id uuid.UUID, transactionID *uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, status models.JobStatus, errMsg string) error
+	Requeue(ctx context.Context, id uuid.UUID, fromAccountID, toAccountID *uuid.UUID, amount float64) error
+}
+
+// BatchRepository defines the interface for batch transfer data operations
+type BatchRepository interface {
+	Create(ctx context.Context, batch *models.Batch) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Batch, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status models.BatchStatus, completedAt *time.Time) error
+	UpdateItems(ctx context.Context, items []models.BatchItem) error
+}
+
+// PaymentImportRepository defines the interface for bulk payment file import data operations
+type PaymentImportRepository interface {
+	Create(ctx context.Context, paymentImport *models.PaymentImport) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.PaymentImport, error)
+	ExistsByHash(ctx context.Context, userID uuid.UUID, fileHash string) (bool, error)
+	TransitionStatus(ctx context.Context, id uuid.UUID, from, to models.PaymentImportStatus) error
+	SetBatch(ctx context.Context, id, batchID uuid.UUID, confirmedAt time.Time) error
+}
+
+// DeadLetterRepository defines the interface for dead-lettered job data operations
+type DeadLetterRepository interface {
+	Create(ctx context.Context, deadLetter *models.DeadLetterJob) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.DeadLetterJob, error)
+	List(ctx context.Context, status models.DeadLetterStatus, limit, offset int) ([]*models.DeadLetterJob, error)
+	Count(ctx context.Context, status models.DeadLetterStatus) (int64, error)
+	Update(ctx context.Context, deadLetter *models.DeadLetterJob) error
+	MarkResolved(ctx context.Context, id uuid.UUID, status models.DeadLetterStatus, resolvedBy uuid.UUID) error
+	Reopen(ctx context.Context, id uuid.UUID) error
+}
+
 // AuditLogRepository defines the interface for audit log data operations
 type AuditLogRepository interface {
 	// Create operations
@@ -70,3 +109,12 @@ type AuditLogRepository interface {
 	Count(ctx context.Context) (int64, error)
 	CountByAction(ctx context.Context, action string) (int64, error)
 }
+
+// CategoryRuleRepository defines the interface for categorization rule data operations
+type CategoryRuleRepository interface {
+	Create(ctx context.Context, rule *models.CategoryRule) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.CategoryRule, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.CategoryRule, error)
+	Update(ctx context.Context, rule *models.CategoryRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
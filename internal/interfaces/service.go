@@ -51,10 +51,11 @@ type UserService interface {
 
 // TransactionService defines the interface for transaction operations
 type TransactionService interface {
-	// Core transaction operations
-	Credit(ctx context.Context, accountID uuid.UUID, amount float64) error
-	Debit(ctx context.Context, accountID uuid.UUID, amount float64) error
-	Transfer(ctx context.Context, fromAccountID, toAccountID uuid.UUID, amount float64) error
+	// Core transaction operations (the completed transaction record is returned on success)
+	Credit(ctx context.Context, accountID uuid.UUID, amount float64, reference string) (*models.Transaction, error)
+	Debit(ctx context.Context, accountID uuid.UUID, amount float64, reference string) (*models.Transaction, error)
+	Transfer(ctx context.Context, fromAccountID, toAccountID uuid.UUID, amount float64, reference string) (*models.Transaction, error)
+	Refund(ctx context.Context, transactionID uuid.UUID) (*models.Transaction, error)
 }
 
 // BalanceService defines the interface for balance management operations
@@ -96,6 +97,7 @@ type NotificationService interface {
 	SendWelcomeEmail(ctx context.Context, user *models.User) error
 	SendTransactionNotification(ctx context.Context, user *models.User, transaction *models.Transaction) error
 	SendPasswordResetEmail(ctx context.Context, user *models.User, resetToken string) error
+	SendEmailVerification(ctx context.Context, user *models.User, verificationToken string) error
 	SendBalanceAlert(ctx context.Context, user *models.User, balance *models.Balance) error
 
 	// SMS notifications (if implemented)
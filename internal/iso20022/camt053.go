@@ -0,0 +1,297 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// Camt053Namespace is the namespace of the rendered bank-to-customer statements
+const Camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// Balance type and indicator codes used in statements
+const (
+	BalanceOpeningBooked = "OPBD"
+	BalanceClosingBooked = "CLBD"
+	Credit               = "CRDT"
+	Debit                = "DBIT"
+	EntryBooked          = "BOOK"
+)
+
+// Camt053Document is a bank-to-customer statement (camt.053)
+type Camt053Document struct {
+	XMLName   xml.Name      `xml:"Document"`
+	Xmlns     string        `xml:"xmlns,attr"`
+	Statement BkToCstmrStmt `xml:"BkToCstmrStmt"`
+}
+
+// BkToCstmrStmt is the camt.053 message body
+type BkToCstmrStmt struct {
+	GroupHeader StatementGroupHeader `xml:"GrpHdr"`
+	Statements  []AccountStatement   `xml:"Stmt"`
+}
+
+// StatementGroupHeader identifies the statement message
+type StatementGroupHeader struct {
+	MessageID        string `xml:"MsgId"`
+	CreationDateTime string `xml:"CreDtTm"`
+}
+
+// AccountStatement is the statement of a single account for a period
+type AccountStatement struct {
+	ID               string            `xml:"Id"`
+	CreationDateTime string            `xml:"CreDtTm"`
+	FromToDate       DateTimePeriod    `xml:"FrToDt"`
+	Account          StatementAccount  `xml:"Acct"`
+	Balances         []CashBalance     `xml:"Bal"`
+	Summary          TotalTransactions `xml:"TxsSummry"`
+	Entries          []ReportEntry     `xml:"Ntry"`
+}
+
+// DateTimePeriod is the period covered by a statement
+type DateTimePeriod struct {
+	From string `xml:"FrDtTm"`
+	To   string `xml:"ToDtTm"`
+}
+
+// StatementAccount identifies the reported account
+type StatementAccount struct {
+	ID       string `xml:"Id>Othr>Id"`
+	Currency string `xml:"Ccy"`
+	Name     string `xml:"Nm,omitempty"`
+}
+
+// CashBalance is a booked balance at a point in time
+type CashBalance struct {
+	Type                 string         `xml:"Tp>CdOrPrtry>Cd"`
+	Amount               CurrencyAmount `xml:"Amt"`
+	CreditDebitIndicator string         `xml:"CdtDbtInd"`
+	DateTime             string         `xml:"Dt>DtTm"`
+}
+
+// CurrencyAmount is an amount with its currency
+type CurrencyAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// TotalTransactions summarizes the entries of a statement
+type TotalTransactions struct {
+	Total   TotalEntries `xml:"TtlNtries"`
+	Credits NumberAndSum `xml:"TtlCdtNtries"`
+	Debits  NumberAndSum `xml:"TtlDbtNtries"`
+}
+
+// TotalEntries is the number, sum and net amount of all entries
+type TotalEntries struct {
+	NumberOfEntries      string `xml:"NbOfNtries"`
+	Sum                  string `xml:"Sum"`
+	NetAmount            string `xml:"TtlNetNtryAmt"`
+	CreditDebitIndicator string `xml:"CdtDbtInd"`
+}
+
+// NumberAndSum is the number and sum of credit or debit entries
+type NumberAndSum struct {
+	NumberOfEntries string `xml:"NbOfNtries"`
+	Sum             string `xml:"Sum"`
+}
+
+// ReportEntry is a single booked entry
+type ReportEntry struct {
+	Reference            string              `xml:"NtryRef"`
+	Amount               CurrencyAmount      `xml:"Amt"`
+	CreditDebitIndicator string              `xml:"CdtDbtInd"`
+	Status               string              `xml:"Sts"`
+	BookingDateTime      string              `xml:"BookgDt>DtTm"`
+	ValueDateTime        string              `xml:"ValDt>DtTm"`
+	ServicerReference    string              `xml:"AcctSvcrRef"`
+	BankTransactionCode  BankTransactionCode `xml:"BkTxCd"`
+	Details              *EntryDetails       `xml:"NtryDtls,omitempty"`
+}
+
+// BankTransactionCode classifies an entry by domain, family and sub-family
+type BankTransactionCode struct {
+	Domain    string `xml:"Domn>Cd"`
+	Family    string `xml:"Domn>Fmly>Cd"`
+	SubFamily string `xml:"Domn>Fmly>SubFmlyCd"`
+}
+
+// EntryDetails carries the underlying transaction of an entry
+type EntryDetails struct {
+	Transaction EntryTransaction `xml:"TxDtls"`
+}
+
+// EntryTransaction describes the parties and remittance of an entry
+type EntryTransaction struct {
+	References     TransactionReferences `xml:"Refs"`
+	RelatedParties *RelatedParties       `xml:"RltdPties,omitempty"`
+	Remittance     *Remittance           `xml:"RmtInf,omitempty"`
+}
+
+// TransactionReferences identifies the underlying transaction
+type TransactionReferences struct {
+	ServicerReference string `xml:"AcctSvcrRef"`
+}
+
+// RelatedParties names the debtor and creditor of an entry
+type RelatedParties struct {
+	DebtorName   string `xml:"Dbtr>Nm,omitempty"`
+	CreditorName string `xml:"Cdtr>Nm,omitempty"`
+}
+
+// Remittance is the unstructured remittance information of an entry
+type Remittance struct {
+	Unstructured string `xml:"Ustrd"`
+}
+
+// Camt053Entry is the input for a single statement entry
+type Camt053Entry struct {
+	Reference    string
+	Amount       float64
+	Credit       bool
+	BookedAt     time.Time
+	Family       string
+	SubFamily    string
+	DebtorName   string
+	CreditorName string
+	Remittance   string
+}
+
+// Camt053Statement holds everything needed to render a statement
+type Camt053Statement struct {
+	MessageID      string
+	StatementID    string
+	CreatedAt      time.Time
+	From           time.Time
+	To             time.Time
+	AccountID      string
+	AccountName    string
+	Currency       string
+	OpeningBalance float64
+	ClosingBalance float64
+	Entries        []Camt053Entry
+}
+
+// NewCamt053Document builds a camt.053 document with opening and closing booked balances
+// and a transaction summary computed from the entries
+func NewCamt053Document(statement *Camt053Statement) *Camt053Document {
+	stmt := AccountStatement{
+		ID:               truncate(statement.StatementID, 35),
+		CreationDateTime: formatDateTime(statement.CreatedAt),
+		FromToDate: DateTimePeriod{
+			From: formatDateTime(statement.From),
+			To:   formatDateTime(statement.To),
+		},
+		Account: StatementAccount{
+			ID:       truncate(statement.AccountID, 34),
+			Currency: statement.Currency,
+			Name:     truncate(statement.AccountName, 70),
+		},
+		Balances: []CashBalance{
+			newCashBalance(BalanceOpeningBooked, statement.OpeningBalance, statement.Currency, statement.From),
+			newCashBalance(BalanceClosingBooked, statement.ClosingBalance, statement.Currency, statement.To),
+		},
+		Entries: make([]ReportEntry, 0, len(statement.Entries)),
+	}
+
+	var creditCount, debitCount int
+	var creditSum, debitSum float64
+	for _, entry := range statement.Entries {
+		indicator := Debit
+		if entry.Credit {
+			indicator = Credit
+			creditCount++
+			creditSum += entry.Amount
+		} else {
+			debitCount++
+			debitSum += entry.Amount
+		}
+
+		reportEntry := ReportEntry{
+			Reference:            truncate(entry.Reference, 35),
+			Amount:               CurrencyAmount{Currency: statement.Currency, Value: FormatAmount(entry.Amount)},
+			CreditDebitIndicator: indicator,
+			Status:               EntryBooked,
+			BookingDateTime:      formatDateTime(entry.BookedAt),
+			ValueDateTime:        formatDateTime(entry.BookedAt),
+			ServicerReference:    truncate(entry.Reference, 35),
+			BankTransactionCode: BankTransactionCode{
+				Domain:    "PMNT",
+				Family:    entry.Family,
+				SubFamily: entry.SubFamily,
+			},
+			Details: &EntryDetails{
+				Transaction: EntryTransaction{
+					References: TransactionReferences{ServicerReference: truncate(entry.Reference, 35)},
+				},
+			},
+		}
+		if entry.DebtorName != "" || entry.CreditorName != "" {
+			reportEntry.Details.Transaction.RelatedParties = &RelatedParties{
+				DebtorName:   truncate(entry.DebtorName, 140),
+				CreditorName: truncate(entry.CreditorName, 140),
+			}
+		}
+		if entry.Remittance != "" {
+			reportEntry.Details.Transaction.Remittance = &Remittance{Unstructured: truncate(entry.Remittance, 140)}
+		}
+		stmt.Entries = append(stmt.Entries, reportEntry)
+	}
+
+	net := creditSum - debitSum
+	netIndicator := Credit
+	if net < 0 {
+		netIndicator = Debit
+		net = -net
+	}
+	stmt.Summary = TotalTransactions{
+		Total: TotalEntries{
+			NumberOfEntries:      fmt.Sprintf("%d", creditCount+debitCount),
+			Sum:                  FormatAmount(creditSum + debitSum),
+			NetAmount:            FormatAmount(net),
+			CreditDebitIndicator: netIndicator,
+		},
+		Credits: NumberAndSum{NumberOfEntries: fmt.Sprintf("%d", creditCount), Sum: FormatAmount(creditSum)},
+		Debits:  NumberAndSum{NumberOfEntries: fmt.Sprintf("%d", debitCount), Sum: FormatAmount(debitSum)},
+	}
+
+	return &Camt053Document{
+		Xmlns: Camt053Namespace,
+		Statement: BkToCstmrStmt{
+			GroupHeader: StatementGroupHeader{
+				MessageID:        truncate(statement.MessageID, 35),
+				CreationDateTime: formatDateTime(statement.CreatedAt),
+			},
+			Statements: []AccountStatement{stmt},
+		},
+	}
+}
+
+// Marshal renders the document with an XML declaration
+func (d *Camt053Document) Marshal() ([]byte, error) {
+	body, err := xml.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// newCashBalance builds a balance; negative amounts are reported as debit balances
+func newCashBalance(balanceType string, amount float64, currency string, at time.Time) CashBalance {
+	indicator := Credit
+	if amount < 0 {
+		indicator = Debit
+		amount = -amount
+	}
+	return CashBalance{
+		Type:                 balanceType,
+		Amount:               CurrencyAmount{Currency: currency, Value: FormatAmount(amount)},
+		CreditDebitIndicator: indicator,
+		DateTime:             formatDateTime(at),
+	}
+}
+
+// formatDateTime formats a time as an ISO 20022 ISODateTime in UTC
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
@@ -0,0 +1,144 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// camt053Schema is the unmodified camt.053.001.02 schema published by ISO 20022, see testdata/README.md
+const camt053Schema = "testdata/camt.053.001.02.xsd"
+
+func sampleStatement() *Camt053Statement {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &Camt053Statement{
+		MessageID:      "STMT20240131",
+		StatementID:    "550e8400e29b41d4a716446655440000",
+		CreatedAt:      time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC),
+		From:           from,
+		To:             time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
+		AccountID:      "550e8400e29b41d4a716446655440000",
+		AccountName:    "alice",
+		Currency:       "TRY",
+		OpeningBalance: 1000,
+		ClosingBalance: 1349.5,
+		Entries: []Camt053Entry{
+			{Reference: "7c9e6679742540de944be07fc1f90ae7", Amount: 500, Credit: true, BookedAt: from.Add(24 * time.Hour), Family: "CNTR", SubFamily: "CDPT", CreditorName: "alice", Remittance: "Deposit"},
+			{Reference: "8d1f6a2c3b4e4f5a9c8d7e6f5a4b3c2d", Amount: 150.5, BookedAt: from.Add(48 * time.Hour), Family: "ICDT", SubFamily: "DMCT", DebtorName: "alice", CreditorName: "bob", Remittance: strings.Repeat("Invoice 4471 ", 20)},
+		},
+	}
+}
+
+func TestCamt053ValidatesAgainstSchema(t *testing.T) {
+	xmllint := schemaValidator(t)
+
+	cases := map[string]*Camt053Statement{
+		"with entries": sampleStatement(),
+		"empty period": {
+			MessageID: "EMPTY", StatementID: "EMPTY", CreatedAt: time.Now(),
+			From: time.Now().Add(-time.Hour), To: time.Now(),
+			AccountID: "550e8400e29b41d4a716446655440000", Currency: "TRY",
+		},
+		"overdrawn": func() *Camt053Statement {
+			statement := sampleStatement()
+			statement.OpeningBalance = -25
+			return statement
+		}(),
+	}
+
+	for name, statement := range cases {
+		t.Run(name, func(t *testing.T) {
+			body, err := NewCamt053Document(statement).Marshal()
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			validateAgainstSchema(t, xmllint, body, true)
+		})
+	}
+}
+
+func TestCamt053SchemaRejectsInvalidDocument(t *testing.T) {
+	xmllint := schemaValidator(t)
+
+	// Guards against a schema check that accepts anything
+	doc := NewCamt053Document(sampleStatement())
+	doc.Statement.Statements[0].Entries[0].CreditDebitIndicator = "CREDIT"
+	body, err := doc.Marshal()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	validateAgainstSchema(t, xmllint, body, false)
+}
+
+func TestCamt053Summary(t *testing.T) {
+	doc := NewCamt053Document(sampleStatement())
+	stmt := doc.Statement.Statements[0]
+
+	if got := stmt.Balances[0]; got.Type != BalanceOpeningBooked || got.Amount.Value != "1000.00" || got.CreditDebitIndicator != Credit {
+		t.Errorf("unexpected opening balance %+v", got)
+	}
+	if got := stmt.Balances[1]; got.Type != BalanceClosingBooked || got.Amount.Value != "1349.50" {
+		t.Errorf("unexpected closing balance %+v", got)
+	}
+
+	summary := stmt.Summary
+	if summary.Total.NumberOfEntries != "2" || summary.Total.Sum != "650.50" {
+		t.Errorf("unexpected totals %+v", summary.Total)
+	}
+	if summary.Total.NetAmount != "349.50" || summary.Total.CreditDebitIndicator != Credit {
+		t.Errorf("unexpected net amount %+v", summary.Total)
+	}
+	if summary.Credits.Sum != "500.00" || summary.Debits.Sum != "150.50" {
+		t.Errorf("unexpected credit/debit sums %+v / %+v", summary.Credits, summary.Debits)
+	}
+
+	// The document must round-trip through encoding/xml
+	body, err := doc.Marshal()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded Camt053Document
+	if err := xml.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(decoded.Statement.Statements[0].Entries) != 2 {
+		t.Errorf("expected 2 entries after round-trip, got %d", len(decoded.Statement.Statements[0].Entries))
+	}
+}
+
+// schemaValidator returns the xmllint binary, skipping the test if it is not installed.
+// A missing schema fails the test; it must be vendored in testdata.
+func schemaValidator(t *testing.T) string {
+	t.Helper()
+
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint not installed, skipping schema validation")
+	}
+	if _, err := os.Stat(camt053Schema); err != nil {
+		t.Fatalf("official schema %s is missing (see testdata/README.md): %v", camt053Schema, err)
+	}
+	return xmllint
+}
+
+// validateAgainstSchema runs xmllint against the camt.053 schema and checks the outcome
+func validateAgainstSchema(t *testing.T, xmllint string, body []byte, wantValid bool) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "statement.xml")
+	if err := os.WriteFile(path, body, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	output, err := exec.Command(xmllint, "--noout", "--schema", camt053Schema, path).CombinedOutput()
+	if wantValid && err != nil {
+		t.Fatalf("document does not validate against %s: %v\n%s\n%s", camt053Schema, err, output, body)
+	}
+	if !wantValid && err == nil {
+		t.Fatalf("expected schema validation to fail:\n%s", body)
+	}
+}
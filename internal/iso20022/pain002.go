@@ -0,0 +1,182 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// Pain002Namespace is the namespace of the rendered payment status reports
+const Pain002Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.002.001.03"
+
+// Payment status codes (ExternalPaymentTransactionStatus1Code / ExternalPaymentGroupStatus1Code)
+const (
+	StatusAccepted          = "ACCP" // accepted, not yet settled
+	StatusSettled           = "ACSC" // accepted and settled on the creditor account
+	StatusPending           = "PDNG" // still being processed
+	StatusPartiallyAccepted = "PART" // some transactions settled, others rejected
+	StatusRejected          = "RJCT" // rejected
+)
+
+// Pain002Document is a customer payment status report (pain.002)
+type Pain002Document struct {
+	XMLName xml.Name       `xml:"Document"`
+	Xmlns   string         `xml:"xmlns,attr"`
+	Report  CstmrPmtStsRpt `xml:"CstmrPmtStsRpt"`
+}
+
+// CstmrPmtStsRpt is the pain.002 message body
+type CstmrPmtStsRpt struct {
+	GroupHeader      StatusGroupHeader            `xml:"GrpHdr"`
+	OriginalGroup    OriginalGroupInformation     `xml:"OrgnlGrpInfAndSts"`
+	OriginalPayments []OriginalPaymentInformation `xml:"OrgnlPmtInfAndSts,omitempty"`
+}
+
+// StatusGroupHeader identifies the status report itself
+type StatusGroupHeader struct {
+	MessageID        string `xml:"MsgId"`
+	CreationDateTime string `xml:"CreDtTm"`
+}
+
+// OriginalGroupInformation refers to the initiation message and carries the overall status
+type OriginalGroupInformation struct {
+	OriginalMessageID     string `xml:"OrgnlMsgId"`
+	OriginalMessageNameID string `xml:"OrgnlMsgNmId"`
+	OriginalNbOfTxs       string `xml:"OrgnlNbOfTxs,omitempty"`
+	OriginalCtrlSum       string `xml:"OrgnlCtrlSum,omitempty"`
+	GroupStatus           string `xml:"GrpSts"`
+}
+
+// OriginalPaymentInformation groups transaction statuses of one payment information block
+type OriginalPaymentInformation struct {
+	OriginalPaymentInformationID string              `xml:"OrgnlPmtInfId"`
+	Transactions                 []TransactionStatus `xml:"TxInfAndSts"`
+}
+
+// TransactionStatus is the status of a single original transaction
+type TransactionStatus struct {
+	OriginalEndToEndID string          `xml:"OrgnlEndToEndId"`
+	Status             string          `xml:"TxSts"`
+	Reason             *StatusReason   `xml:"StsRsnInf,omitempty"`
+	OriginalAmount     *OriginalAmount `xml:"OrgnlTxRef>Amt>InstdAmt,omitempty"`
+}
+
+// StatusReason explains a rejection in free text
+type StatusReason struct {
+	AdditionalInformation string `xml:"AddtlInf"`
+}
+
+// OriginalAmount echoes the instructed amount of the original transaction
+type OriginalAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// Pain002Transaction is the input for a single transaction line of a status report
+type Pain002Transaction struct {
+	PaymentInformationID string
+	EndToEndID           string
+	Status               string
+	Reason               string
+	Amount               float64
+	Currency             string
+}
+
+// Pain002Report holds everything needed to render a status report
+type Pain002Report struct {
+	MessageID             string
+	CreatedAt             time.Time
+	OriginalMessageID     string
+	OriginalMessageNameID string
+	Transactions          []Pain002Transaction
+}
+
+// NewPain002Document builds a pain.002 document. The group status is derived from the
+// transaction statuses and the original control totals are recomputed from the lines.
+func NewPain002Document(report *Pain002Report) *Pain002Document {
+	doc := &Pain002Document{
+		Xmlns: Pain002Namespace,
+		Report: CstmrPmtStsRpt{
+			GroupHeader: StatusGroupHeader{
+				MessageID:        truncate(report.MessageID, 35),
+				CreationDateTime: report.CreatedAt.UTC().Format("2006-01-02T15:04:05"),
+			},
+			OriginalGroup: OriginalGroupInformation{
+				OriginalMessageID:     truncate(report.OriginalMessageID, 35),
+				OriginalMessageNameID: report.OriginalMessageNameID,
+				OriginalNbOfTxs:       fmt.Sprintf("%d", len(report.Transactions)),
+			},
+		},
+	}
+
+	var controlSum float64
+	counts := make(map[string]int)
+	blocks := make(map[string]int)
+	for _, tx := range report.Transactions {
+		controlSum += tx.Amount
+		counts[tx.Status]++
+
+		index, ok := blocks[tx.PaymentInformationID]
+		if !ok {
+			index = len(doc.Report.OriginalPayments)
+			blocks[tx.PaymentInformationID] = index
+			doc.Report.OriginalPayments = append(doc.Report.OriginalPayments, OriginalPaymentInformation{
+				OriginalPaymentInformationID: truncate(tx.PaymentInformationID, 35),
+			})
+		}
+
+		status := TransactionStatus{
+			OriginalEndToEndID: truncate(tx.EndToEndID, 35),
+			Status:             tx.Status,
+			OriginalAmount: &OriginalAmount{
+				Currency: tx.Currency,
+				Value:    FormatAmount(tx.Amount),
+			},
+		}
+		if tx.Reason != "" {
+			status.Reason = &StatusReason{AdditionalInformation: truncate(tx.Reason, 105)}
+		}
+		doc.Report.OriginalPayments[index].Transactions = append(doc.Report.OriginalPayments[index].Transactions, status)
+	}
+	doc.Report.OriginalGroup.OriginalCtrlSum = FormatAmount(controlSum)
+	doc.Report.OriginalGroup.GroupStatus = groupStatus(counts, len(report.Transactions))
+
+	return doc
+}
+
+// Marshal renders the document with an XML declaration
+func (d *Pain002Document) Marshal() ([]byte, error) {
+	body, err := xml.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// FormatAmount formats an amount with the two decimals ISO 20022 expects
+func FormatAmount(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
+
+// groupStatus derives the overall status from the transaction status counts
+func groupStatus(counts map[string]int, total int) string {
+	switch {
+	case counts[StatusPending] > 0 || counts[StatusAccepted] > 0:
+		return StatusPending
+	case counts[StatusSettled] == total:
+		return StatusSettled
+	case counts[StatusRejected] == total:
+		return StatusRejected
+	default:
+		return StatusPartiallyAccepted
+	}
+}
+
+// truncate cuts s to the maximum length of an ISO 20022 text field
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
@@ -1,115 +1,250 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/barannkoca/banking-backend/internal/authz"
+	"github.com/barannkoca/banking-backend/internal/models"
 	"github.com/barannkoca/banking-backend/pkg/logger"
 	"github.com/barannkoca/banking-backend/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
-// AuthenticationMiddleware validates JWT tokens and sets user context
-func AuthenticationMiddleware() gin.HandlerFunc {
+// SessionChecker validates access tokens, reports whether a token (by jti) or its session
+// was revoked and records session activity
+type SessionChecker interface {
+	ValidateAccessToken(token string) (*utils.JWTClaims, error)
+	IsRevoked(ctx context.Context, tokenID, sessionID uuid.UUID) (bool, error)
+	TouchSession(ctx context.Context, sessionID uuid.UUID, ipAddress string)
+}
+
+// ClientTokenVerifier verifies access tokens issued to API clients
+type ClientTokenVerifier interface {
+	// VerifyClientToken returns nil claims and no error for tokens that are not client tokens
+	VerifyClientToken(ctx context.Context, token string) (*utils.JWTClaims, error)
+}
+
+// APIKeyHeader carries a personal API key as an alternative to a Bearer JWT
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyVerifier authenticates personal API keys
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key, ipAddress string) (*models.APIKey, error)
+}
+
+// AuthenticationMiddleware validates JWT access tokens, rejects revoked ones and sets user
+// context. When apiKeys is not nil, a personal API key in the X-API-Key header is accepted
+// instead; routes accepting keys must also be guarded by APIKeyScopeMiddleware.
+func AuthenticationMiddleware(sessions SessionChecker, apiKeys APIKeyVerifier) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
-		// Get Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Authorization header required",
-				"message": "Please provide a valid JWT token",
-			})
-			c.Abort()
+		if key := c.GetHeader(APIKeyHeader); key != "" && apiKeys != nil {
+			if !authenticateAPIKey(c, apiKeys, key) {
+				return
+			}
+			c.Next()
 			return
 		}
 
-		// Check if it's a Bearer token
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Invalid authorization format",
-				"message": "Authorization header must be in format: Bearer <token>",
-			})
-			c.Abort()
+		token, ok := bearerToken(c)
+		if !ok {
 			return
 		}
-
-		// Extract token
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Empty token",
-				"message": "JWT token cannot be empty",
-			})
-			c.Abort()
+		if !authenticateUser(c, sessions, token) {
 			return
 		}
 
-		// Validate JWT token (placeholder - implement actual JWT validation)
-		userID, userRole, err := validateJWTToken(token)
-		if err != nil {
-			logger.GetLogger().Warn("Invalid JWT token",
-				zap.String("token", token[:10]+"..."), // Log only first 10 chars for security
-				zap.String("ip", c.ClientIP()),
-				zap.Error(err),
-				zap.String("type", "auth_error"),
-			)
+		c.Next()
+	})
+}
 
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Invalid token",
-				"message": "JWT token is invalid or expired",
-			})
-			c.Abort()
-			return
-		}
+// bearerToken extracts the token of a Bearer Authorization header, or responds 401
+func bearerToken(c *gin.Context) (string, bool) {
+	// Get Authorization header
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authorization header required",
+			"message": "Please provide a valid JWT token",
+		})
+		c.Abort()
+		return "", false
+	}
 
-		// Set user context
-		c.Set("user_id", userID)
-		c.Set("user_role", userRole)
-		c.Set("authenticated", true)
+	// Check if it's a Bearer token
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Invalid authorization format",
+			"message": "Authorization header must be in format: Bearer <token>",
+		})
+		c.Abort()
+		return "", false
+	}
 
-		// Log successful authentication
-		logger.GetLogger().Info("User authenticated",
-			zap.String("user_id", userID),
-			zap.String("user_role", userRole),
+	// Extract token
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Empty token",
+			"message": "JWT token cannot be empty",
+		})
+		c.Abort()
+		return "", false
+	}
+
+	return token, true
+}
+
+// authenticateUser validates a user access token and sets the user context, or responds 401
+func authenticateUser(c *gin.Context, sessions SessionChecker, token string) bool {
+	// Validate JWT token
+	claims, err := validateJWTToken(sessions, token)
+	if err != nil {
+		logger.GetLogger().Warn("Invalid JWT token",
+			zap.String("token", token[:10]+"..."), // Log only first 10 chars for security
 			zap.String("ip", c.ClientIP()),
-			zap.String("type", "auth_success"),
+			zap.Error(err),
+			zap.String("type", "auth_error"),
 		)
 
-		c.Next()
-	})
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Invalid token",
+			"message": "JWT token is invalid or expired",
+		})
+		c.Abort()
+		return false
+	}
+
+	// Reject tokens on the revocation list or of a logged out session
+	tokenID, _ := uuid.Parse(claims.ID)
+	sessionID, _ := uuid.Parse(claims.SessionID)
+	revoked, err := sessions.IsRevoked(c.Request.Context(), tokenID, sessionID)
+	if err != nil {
+		logger.GetLogger().Error("Token revocation check failed",
+			zap.String("user_id", claims.UserID),
+			zap.Error(err),
+			zap.String("type", "auth_error"),
+		)
+
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Authentication unavailable",
+			"message": "Kimlik doğrulama şu anda yapılamıyor",
+		})
+		c.Abort()
+		return false
+	}
+	if revoked {
+		logger.GetLogger().Warn("Revoked JWT token used",
+			zap.String("user_id", claims.UserID),
+			zap.String("jti", claims.ID),
+			zap.String("session_id", claims.SessionID),
+			zap.String("ip", c.ClientIP()),
+			zap.String("type", "auth_revoked_token"),
+		)
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Token revoked",
+			"message": "JWT token has been revoked",
+		})
+		c.Abort()
+		return false
+	}
+
+	sessions.TouchSession(c.Request.Context(), sessionID, c.ClientIP())
+
+	// Set user context
+	c.Set("user_id", claims.UserID)
+	c.Set("user_role", claims.Role)
+	c.Set("session_id", claims.SessionID)
+	c.Set("token_id", claims.ID)
+	c.Set("token_expires_at", claims.ExpiresAt.Time)
+	c.Set("authenticated", true)
+
+	// Log successful authentication
+	logger.GetLogger().Info("User authenticated",
+		zap.String("user_id", claims.UserID),
+		zap.String("user_role", claims.Role),
+		zap.String("ip", c.ClientIP()),
+		zap.String("type", "auth_success"),
+	)
+	return true
 }
 
-// AdminAuthorizationMiddleware checks if user has admin role
-func AdminAuthorizationMiddleware() gin.HandlerFunc {
+// authenticateAPIKey validates a personal API key and sets the user context of its owner,
+// or responds 401. API key requests have no session. ClientIP honours X-Forwarded-For only
+// from the configured trusted proxies, so the allow-list cannot be bypassed with the header.
+func authenticateAPIKey(c *gin.Context, apiKeys APIKeyVerifier, key string) bool {
+	apiKey, err := apiKeys.VerifyAPIKey(c.Request.Context(), key, c.ClientIP())
+	if err != nil {
+		logger.GetLogger().Warn("Invalid API key",
+			zap.String("ip", c.ClientIP()),
+			zap.Error(err),
+			zap.String("type", "auth_error"),
+		)
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Invalid API key",
+			"message": "API anahtarı geçersiz, süresi dolmuş veya bu IP adresinden kullanılamaz",
+		})
+		c.Abort()
+		return false
+	}
+
+	c.Set("user_id", apiKey.UserID.String())
+	c.Set("user_role", string(apiKey.User.Role))
+	c.Set("api_key_id", apiKey.ID.String())
+	c.Set("scopes", apiKey.ScopeList())
+	c.Set("authenticated", true)
+
+	logger.GetLogger().Info("User authenticated with API key",
+		zap.String("user_id", apiKey.UserID.String()),
+		zap.String("api_key", apiKey.Prefix),
+		zap.String("ip", c.ClientIP()),
+		zap.String("type", "auth_success"),
+	)
+	return true
+}
+
+// APIKeyScopeMiddleware limits API key requests to the key's scopes: GET and HEAD need
+// readScope and other methods writeScope, an empty scope denying them. JWT-authenticated
+// users are not limited. Must run after AuthenticationMiddleware.
+func APIKeyScopeMiddleware(readScope, writeScope string) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
-		// Check if user is authenticated
-		if !isAuthenticated(c) {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Authentication required",
-				"message": "Please authenticate first",
-			})
-			c.Abort()
+		if c.GetString("api_key_id") == "" {
+			c.Next()
 			return
 		}
 
-		// Check if user has admin role
-		userRole := getUserRoleFromContext(c)
-		if userRole != "admin" {
-			logger.GetLogger().Warn("Unauthorized admin access attempt",
+		scope := writeScope
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = readScope
+		}
+		if scope == "" || !hasScope(c.GetStringSlice("scopes"), scope) {
+			logger.GetLogger().Warn("API key scope denied",
 				zap.String("user_id", getUserIDFromContext(c)),
-				zap.String("user_role", userRole),
+				zap.String("required_scope", scope),
 				zap.String("ip", c.ClientIP()),
 				zap.String("path", c.Request.URL.Path),
 				zap.String("type", "auth_unauthorized"),
 			)
+			respondInsufficientScope(c, scope)
+			return
+		}
 
-			c.JSON(http.StatusForbidden, gin.H{
-				"error":   "Insufficient permissions",
-				"message": "Admin role required for this operation",
-			})
-			c.Abort()
+		c.Next()
+	})
+}
+
+// RequirePermission requires the authenticated user's role to hold every permission. Checks
+// on resources the user may own are made with authz.Allowed or a Policy in the handler or
+// service. Must run after AuthenticationMiddleware.
+func RequirePermission(permissions ...authz.Permission) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		if !authorizePermissions(c, permissions...) {
 			return
 		}
 
@@ -117,51 +252,157 @@ func AdminAuthorizationMiddleware() gin.HandlerFunc {
 	})
 }
 
-// ManagerAuthorizationMiddleware checks if user has manager or admin role
-func ManagerAuthorizationMiddleware() gin.HandlerFunc {
+// authorizePermissions checks that the authenticated user's role holds the permissions, or
+// responds 401/403
+func authorizePermissions(c *gin.Context, permissions ...authz.Permission) bool {
+	// Check if user is authenticated
+	if !isAuthenticated(c) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authentication required",
+			"message": "Please authenticate first",
+		})
+		c.Abort()
+		return false
+	}
+
+	userRole := getUserRoleFromContext(c)
+	for _, permission := range permissions {
+		if authz.HasPermission(models.UserRole(userRole), permission) {
+			continue
+		}
+
+		logger.GetLogger().Warn("Permission denied",
+			zap.String("user_id", getUserIDFromContext(c)),
+			zap.String("user_role", userRole),
+			zap.String("permission", string(permission)),
+			zap.String("ip", c.ClientIP()),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("type", "auth_unauthorized"),
+		)
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Insufficient permissions",
+			"message": "Bu işlem için yetkiniz yok",
+		})
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+// PermissionOrClientScopeMiddleware admits users holding permission and API clients whose
+// token was granted scope. It authenticates the request itself, so routes using it need no
+// other auth middleware.
+func PermissionOrClientScopeMiddleware(sessions SessionChecker, clients ClientTokenVerifier, permission authz.Permission, scope string) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
-		// Check if user is authenticated
-		if !isAuthenticated(c) {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Authentication required",
-				"message": "Please authenticate first",
-			})
-			c.Abort()
+		token, ok := bearerToken(c)
+		if !ok {
 			return
 		}
 
-		// Check if user has manager or admin role
-		userRole := getUserRoleFromContext(c)
-		if userRole != "manager" && userRole != "admin" {
-			logger.GetLogger().Warn("Unauthorized manager access attempt",
-				zap.String("user_id", getUserIDFromContext(c)),
-				zap.String("user_role", userRole),
+		claims, err := clients.VerifyClientToken(c.Request.Context(), token)
+		if err != nil {
+			logger.GetLogger().Warn("Invalid client token",
 				zap.String("ip", c.ClientIP()),
-				zap.String("path", c.Request.URL.Path),
-				zap.String("type", "auth_unauthorized"),
+				zap.Error(err),
+				zap.String("type", "auth_error"),
 			)
 
-			c.JSON(http.StatusForbidden, gin.H{
-				"error":   "Insufficient permissions",
-				"message": "Manager or admin role required for this operation",
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "Invalid token",
+				"message": "JWT token is invalid or expired",
 			})
 			c.Abort()
 			return
 		}
 
+		if claims != nil {
+			if !authorizeClientScope(c, claims, scope) {
+				return
+			}
+		} else if !authenticateUser(c, sessions, token) || !authorizePermissions(c, permission) {
+			return
+		}
+
 		c.Next()
 	})
 }
 
-// validateJWTToken validates JWT token and returns user info
-func validateJWTToken(token string) (userID string, userRole string, err error) {
-	// Use JWT utility to validate token
-	claims, err := utils.ValidateToken(token, utils.DefaultJWTConfig())
+// authorizeClientScope checks that a client token carries scope and sets the client
+// context, or responds 403 with an RFC 6750 insufficient_scope challenge
+func authorizeClientScope(c *gin.Context, claims *utils.JWTClaims, scope string) bool {
+	scopes := strings.Fields(claims.Scope)
+	if hasScope(scopes, scope) {
+		c.Set("client_id", claims.ClientID)
+		c.Set("scopes", scopes)
+		c.Set("authenticated", true)
+
+		logger.GetLogger().Info("API client authenticated",
+			zap.String("client_id", claims.ClientID),
+			zap.String("scope", scope),
+			zap.String("ip", c.ClientIP()),
+			zap.String("type", "auth_success"),
+		)
+		return true
+	}
+
+	logger.GetLogger().Warn("API client scope denied",
+		zap.String("client_id", claims.ClientID),
+		zap.String("required_scope", scope),
+		zap.String("ip", c.ClientIP()),
+		zap.String("path", c.Request.URL.Path),
+		zap.String("type", "auth_unauthorized"),
+	)
+
+	respondInsufficientScope(c, scope)
+	return false
+}
+
+// hasScope reports whether scopes includes scope
+func hasScope(scopes []string, scope string) bool {
+	for _, granted := range scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// respondInsufficientScope responds 403 with an RFC 6750 insufficient_scope challenge
+func respondInsufficientScope(c *gin.Context, scope string) {
+	message := "Bu işlem API anahtarı ile yapılamaz"
+	challenge := `Bearer error="insufficient_scope"`
+	if scope != "" {
+		message = fmt.Sprintf("Bu işlem için %s yetkisi gerekli", scope)
+		challenge += fmt.Sprintf(", scope=%q", scope)
+	}
+
+	c.Header("WWW-Authenticate", challenge)
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":   "Insufficient scope",
+		"message": message,
+	})
+	c.Abort()
+}
+
+// validateJWTToken validates an access token and returns its claims
+func validateJWTToken(sessions SessionChecker, token string) (*utils.JWTClaims, error) {
+	// Refresh and other token types are not accepted here
+	claims, err := sessions.ValidateAccessToken(token)
 	if err != nil {
-		return "", "", fmt.Errorf("invalid token: %w", err)
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("invalid token: missing expiry")
+	}
+	if _, err := uuid.Parse(claims.ID); err != nil {
+		return nil, fmt.Errorf("invalid token: missing jti")
+	}
+	if _, err := uuid.Parse(claims.SessionID); err != nil {
+		return nil, fmt.Errorf("invalid token: missing session")
 	}
 
-	return claims.UserID, claims.Role, nil
+	return claims, nil
 }
 
 // isAuthenticated checks if user is authenticated
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// EmailVerificationChecker reports whether a user verified their email address
+type EmailVerificationChecker interface {
+	IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// RequireVerifiedEmail rejects requests from users whose current email address is not
+// verified. Must run after AuthenticationMiddleware.
+func RequireVerifiedEmail(checker EmailVerificationChecker) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		userID, err := uuid.Parse(getUserIDFromContext(c))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "Authentication required",
+				"message": "Please authenticate first",
+			})
+			c.Abort()
+			return
+		}
+
+		verified, err := checker.IsEmailVerified(c.Request.Context(), userID)
+		if err != nil {
+			logger.GetLogger().Error("Email verification check failed",
+				zap.String("user_id", userID.String()),
+				zap.Error(err),
+				zap.String("type", "email_verification_error"),
+			)
+
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Verification check unavailable",
+				"message": "E-posta doğrulama durumu şu anda kontrol edilemiyor",
+			})
+			c.Abort()
+			return
+		}
+
+		if !verified {
+			logger.GetLogger().Warn("Unverified email blocked",
+				zap.String("user_id", userID.String()),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("type", "email_not_verified"),
+			)
+
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Email not verified",
+				"message": "Bu işlem için e-posta adresinizi doğrulamanız gerekli",
+				"code":    "EMAIL_NOT_VERIFIED",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	})
+}
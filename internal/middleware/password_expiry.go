@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PasswordExpiryChecker reports whether a user's password passed its maximum age
+type PasswordExpiryChecker interface {
+	IsPasswordExpired(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// PasswordExpiryMiddleware rejects requests from users who must change their password.
+// The /auth session routes stay available so the password can be changed at
+// POST /api/v1/auth/password/change. Must run after AuthenticationMiddleware.
+func PasswordExpiryMiddleware(checker PasswordExpiryChecker) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		userID, err := uuid.Parse(getUserIDFromContext(c))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "Authentication required",
+				"message": "Please authenticate first",
+			})
+			c.Abort()
+			return
+		}
+
+		expired, err := checker.IsPasswordExpired(c.Request.Context(), userID)
+		if err != nil {
+			logger.GetLogger().Error("Password expiry check failed",
+				zap.String("user_id", userID.String()),
+				zap.Error(err),
+				zap.String("type", "password_expiry_error"),
+			)
+
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Password check unavailable",
+				"message": "Şifre durumu şu anda kontrol edilemiyor",
+			})
+			c.Abort()
+			return
+		}
+
+		if expired {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Password expired",
+				"message": "Şifrenizin süresi doldu, devam etmek için şifrenizi değiştirin",
+				"code":    "PASSWORD_EXPIRED",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	})
+}
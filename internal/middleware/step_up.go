@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/pkg/logger"
+	"github.com/barannkoca/banking-backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// StepUpTokenHeader carries the elevated token obtained from POST /api/v1/auth/step-up
+const StepUpTokenHeader = "X-Step-Up-Token"
+
+// maxStepUpBodySize bounds the body of requests with amount conditions; larger bodies are
+// rejected so the handler never binds bytes the condition did not evaluate
+const maxStepUpBodySize = 1 << 20
+
+// StepUpVerifier validates elevated tokens and issues step-up challenges
+type StepUpVerifier interface {
+	VerifyStepUpToken(ctx context.Context, token string, userID, sessionID uuid.UUID) (*utils.JWTClaims, error)
+	CreateStepUpChallenge(ctx context.Context, userID, sessionID uuid.UUID) (*models.StepUpChallenge, error)
+}
+
+// StepUpCondition reports whether a request needs step-up authentication
+type StepUpCondition func(c *gin.Context) bool
+
+// StepUpMiddleware requires a valid elevated token for requests matching the condition (all
+// requests if nil). Without one it responds 401 with a challenge the client completes at
+// POST /api/v1/auth/step-up. Must run after AuthenticationMiddleware.
+func StepUpMiddleware(verifier StepUpVerifier, condition StepUpCondition) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		if condition != nil && !condition(c) {
+			c.Next()
+			return
+		}
+
+		// API keys have no session to re-authenticate; these operations need an interactive login
+		if c.GetString("api_key_id") != "" {
+			respondInsufficientScope(c, "")
+			return
+		}
+
+		userID, userErr := uuid.Parse(getUserIDFromContext(c))
+		sessionID, sessionErr := uuid.Parse(c.GetString("session_id"))
+		if userErr != nil || sessionErr != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "Authentication required",
+				"message": "Please authenticate first",
+			})
+			c.Abort()
+			return
+		}
+
+		if token := c.GetHeader(StepUpTokenHeader); token != "" {
+			claims, err := verifier.VerifyStepUpToken(c.Request.Context(), token, userID, sessionID)
+			if err == nil {
+				c.Set("step_up_acr", claims.ACR)
+				c.Set("step_up_amr", claims.AMR)
+				c.Next()
+				return
+			}
+
+			logger.GetLogger().Warn("Invalid step-up token",
+				zap.String("user_id", userID.String()),
+				zap.String("ip", c.ClientIP()),
+				zap.String("path", c.Request.URL.Path),
+				zap.Error(err),
+				zap.String("type", "step_up_invalid"),
+			)
+		}
+
+		challenge, err := verifier.CreateStepUpChallenge(c.Request.Context(), userID, sessionID)
+		if err != nil {
+			logger.GetLogger().Error("Step-up challenge creation failed",
+				zap.String("user_id", userID.String()),
+				zap.Error(err),
+				zap.String("type", "step_up_error"),
+			)
+
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Authentication unavailable",
+				"message": "Kimlik doğrulama şu anda yapılamıyor",
+			})
+			c.Abort()
+			return
+		}
+
+		logger.GetLogger().Info("Step-up authentication required",
+			zap.String("user_id", userID.String()),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("acr", challenge.ACR),
+			zap.String("type", "step_up_required"),
+		)
+
+		// RFC 9470 step-up challenge
+		c.Header("WWW-Authenticate", `Bearer error="insufficient_user_authentication", acr_values="`+challenge.ACR+`"`)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Step-up authentication required",
+			"message": "Bu işlem için yeniden kimlik doğrulama gerekli",
+			"code":    "STEP_UP_REQUIRED",
+			"data":    challenge,
+		})
+		c.Abort()
+	})
+}
+
+// AmountAboveThreshold matches requests whose JSON body moves more than threshold: the
+// amount field, or the sum of items[].amount for batches. Deposits (the async credit type)
+// never match. Bodies that are too large or cannot be parsed match, so step-up fails
+// closed. The body is restored for the handler.
+func AmountAboveThreshold(threshold float64) StepUpCondition {
+	return func(c *gin.Context) bool {
+		if c.Param("type") == string(models.JobTypeCredit) {
+			return false
+		}
+
+		// The handler reads the same capped body, so it cannot bind more than was evaluated
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxStepUpBodySize))
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return true // body too large or unreadable, fail closed
+		}
+
+		var payload struct {
+			Amount float64 `json:"amount"`
+			Items  []struct {
+				Amount float64 `json:"amount"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return true // cannot evaluate the amount, fail closed
+		}
+
+		total := payload.Amount
+		for _, item := range payload.Items {
+			total += item.Amount
+		}
+		return total > threshold
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountTokenPurpose is what a single-use account token may be used for
+type AccountTokenPurpose string
+
+const (
+	AccountTokenPasswordReset     AccountTokenPurpose = "password_reset"
+	AccountTokenEmailVerification AccountTokenPurpose = "email_verification"
+)
+
+// AccountToken is a single-use, expiring token sent by email. Only its SHA-256 hash is stored.
+type AccountToken struct {
+	ID        uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID           `json:"user_id" gorm:"type:uuid;not null;index"`
+	Purpose   AccountTokenPurpose `json:"purpose" gorm:"not null;size:20"`
+	TokenHash string              `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	Email     string              `json:"email" gorm:"not null;size:100"` // address the token was sent to
+	ExpiresAt time.Time           `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time          `json:"used_at,omitempty"`
+	CreatedAt time.Time           `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for AccountToken model
+func (AccountToken) TableName() string {
+	return "account_tokens"
+}
+
+// PasswordForgotRequest starts a password reset
+type PasswordForgotRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// PasswordResetRequest sets a new password with a reset token
+type PasswordResetRequest struct {
+	Token       string `json:"token" binding:"required,max=100"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// EmailVerifyRequest confirms an email address with a verification token
+type EmailVerifyRequest struct {
+	Token string `json:"token" binding:"required,max=100"`
+}
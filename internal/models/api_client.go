@@ -0,0 +1,97 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// API client scopes. Each one grants read access to an admin endpoint that service
+// clients may call instead of using an admin's token.
+const (
+	ScopeUsersRead        = "users:read"
+	ScopeTransactionsRead = "transactions:read"
+	ScopeSessionsRead     = "sessions:read"
+	ScopeAuditRead        = "audit:read"
+)
+
+// APIClientScopes lists the scopes an API client can be granted
+var APIClientScopes = []string{ScopeUsersRead, ScopeTransactionsRead, ScopeSessionsRead, ScopeAuditRead}
+
+// IsValidScope reports whether scope can be granted to an API client
+func IsValidScope(scope string) bool {
+	for _, known := range APIClientScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+// APIClient is a service registered by an admin to call the API with the OAuth2 client
+// credentials grant. Only the SHA-256 hash of its secret is stored.
+type APIClient struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ClientID   string     `json:"client_id" gorm:"not null;size:64;uniqueIndex"`
+	SecretHash string     `json:"-" gorm:"not null;size:64"`
+	Name       string     `json:"name" gorm:"not null;size:100"`
+	Scopes     string     `json:"-" gorm:"not null;size:255"` // space-separated, as in the OAuth2 scope parameter
+	CreatedBy  uuid.UUID  `json:"created_by" gorm:"type:uuid;not null"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"` // last token issued
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for APIClient model
+func (APIClient) TableName() string {
+	return "api_clients"
+}
+
+// ScopeList returns the granted scopes
+func (c *APIClient) ScopeList() []string {
+	return strings.Fields(c.Scopes)
+}
+
+// IsRevoked reports whether the client was revoked
+func (c *APIClient) IsRevoked() bool {
+	return c.RevokedAt != nil
+}
+
+// ToResponse converts APIClient to APIClientResponse
+func (c *APIClient) ToResponse() APIClientResponse {
+	return APIClientResponse{
+		ID:         c.ID,
+		ClientID:   c.ClientID,
+		Name:       c.Name,
+		Scopes:     c.ScopeList(),
+		CreatedBy:  c.CreatedBy,
+		RevokedAt:  c.RevokedAt,
+		LastUsedAt: c.LastUsedAt,
+		CreatedAt:  c.CreatedAt,
+	}
+}
+
+// APIClientResponse represents an API client in API responses
+type APIClientResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	ClientID   string     `json:"client_id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APIClientCreateRequest registers an API client
+type APIClientCreateRequest struct {
+	Name   string   `json:"name" binding:"required,min=3,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+}
+
+// APIClientCreateResponse is returned once on registration; the secret is not retrievable later
+type APIClientCreateResponse struct {
+	APIClientResponse
+	ClientSecret string `json:"client_secret"`
+}
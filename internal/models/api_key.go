@@ -0,0 +1,123 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Personal API key scopes, in addition to ScopeUsersRead and ScopeTransactionsRead. Read
+// scopes cover GET requests of a route group and write scopes every other method.
+const (
+	ScopeUsersWrite        = "users:write"
+	ScopeTransactionsWrite = "transactions:write"
+	ScopeBalancesRead      = "balances:read"
+	ScopeCategoriesRead    = "categories:read"
+	ScopeCategoriesWrite   = "categories:write"
+	ScopeAnalyticsRead     = "analytics:read"
+)
+
+// APIKeyScopes lists the scopes a personal API key can be granted
+var APIKeyScopes = []string{
+	ScopeUsersRead, ScopeUsersWrite,
+	ScopeTransactionsRead, ScopeTransactionsWrite,
+	ScopeBalancesRead,
+	ScopeCategoriesRead, ScopeCategoriesWrite,
+	ScopeAnalyticsRead,
+}
+
+// IsValidAPIKeyScope reports whether scope can be granted to a personal API key
+func IsValidAPIKeyScope(scope string) bool {
+	for _, known := range APIKeyScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey is a long-lived personal credential acting as its user within its scopes. The key
+// is shown once on creation; only its SHA-256 hash and its public prefix are stored.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name       string     `json:"name" gorm:"not null;size:100"`
+	Prefix     string     `json:"prefix" gorm:"not null;size:20;uniqueIndex"` // identifies the key in lists and logs
+	KeyHash    string     `json:"-" gorm:"not null;size:64"`
+	Scopes     string     `json:"-" gorm:"not null;size:255"`  // space-separated
+	AllowedIPs string     `json:"-" gorm:"not null;size:1000"` // space-separated IPs and CIDRs; empty allows any
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `json:"last_used_ip,omitempty" gorm:"size:45"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relationships
+	User *User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// TableName returns the table name for APIKey model
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList returns the granted scopes
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+// AllowedIPList returns the IPs and CIDRs the key may be used from
+func (k *APIKey) AllowedIPList() []string {
+	return strings.Fields(k.AllowedIPs)
+}
+
+// IsActive reports whether the key is neither revoked nor expired at now
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && now.Before(k.ExpiresAt)
+}
+
+// ToResponse converts APIKey to APIKeyResponse
+func (k *APIKey) ToResponse() APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		AllowedIPs: k.AllowedIPList(),
+		ExpiresAt:  k.ExpiresAt,
+		RevokedAt:  k.RevokedAt,
+		LastUsedAt: k.LastUsedAt,
+		LastUsedIP: k.LastUsedIP,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// APIKeyResponse represents a personal API key in API responses
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	AllowedIPs []string   `json:"allowed_ips"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APIKeyCreateRequest creates a personal API key. Without ExpiresAt the key lives for the
+// configured maximum lifetime.
+type APIKeyCreateRequest struct {
+	Name       string     `json:"name" binding:"required,min=3,max=100"`
+	Scopes     []string   `json:"scopes" binding:"required,min=1"`
+	AllowedIPs []string   `json:"allowed_ips" binding:"max=20"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+// APIKeyCreateResponse is returned once on creation; the key is not retrievable later
+type APIKeyCreateResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalType is an operation that needs a second user's approval before it runs
+type ApprovalType string
+
+const (
+	ApprovalTypeRoleChange     ApprovalType = "role_change"
+	ApprovalTypeCredit         ApprovalType = "credit" // teller credits above the role limit
+	ApprovalTypeRefund         ApprovalType = "refund"
+	ApprovalTypeAccountFreeze  ApprovalType = "account_freeze"   // freezing and unfreezing
+	ApprovalTypeDeadLetterEdit ApprovalType = "dead_letter_edit" // dead-letter edits of accounts or amount
+)
+
+// ApprovalStatus defines the state of an approval request
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending  ApprovalStatus = "pending"
+	ApprovalStatusApproved ApprovalStatus = "approved" // approved, operation running
+	ApprovalStatusExecuted ApprovalStatus = "executed" // operation ran, or was queued as JobID
+	ApprovalStatusFailed   ApprovalStatus = "failed"   // approved, but the operation failed
+	ApprovalStatusRejected ApprovalStatus = "rejected"
+	ApprovalStatusExpired  ApprovalStatus = "expired"
+)
+
+// IsValidApprovalStatus checks if the given string is an approval status
+func IsValidApprovalStatus(status string) bool {
+	switch ApprovalStatus(status) {
+	case ApprovalStatusPending, ApprovalStatusApproved, ApprovalStatusExecuted,
+		ApprovalStatusFailed, ApprovalStatusRejected, ApprovalStatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// ApprovalRequest is an operation requested by a maker that runs only after a different
+// user (the checker) approves it. SubjectID is the user the operation affects; neither the
+// maker nor the subject may approve.
+type ApprovalRequest struct {
+	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Type         ApprovalType   `json:"type" gorm:"not null;size:30;index"`
+	Payload      string         `json:"-" gorm:"type:jsonb;not null"`
+	SubjectID    uuid.UUID      `json:"subject_id" gorm:"type:uuid;not null;index"`
+	Reason       string         `json:"reason" gorm:"not null;size:500"`
+	Status       ApprovalStatus `json:"status" gorm:"not null;size:20;default:'pending';index"`
+	MakerID      uuid.UUID      `json:"maker_id" gorm:"type:uuid;not null;index"`
+	CheckerID    *uuid.UUID     `json:"checker_id,omitempty" gorm:"type:uuid"`
+	DecisionNote string         `json:"decision_note,omitempty" gorm:"size:500"`
+	Error        string         `json:"error,omitempty" gorm:"type:text"`  // why an approved operation failed
+	JobID        *uuid.UUID     `json:"job_id,omitempty" gorm:"type:uuid"` // job moving the money of credits and refunds
+	ExpiresAt    time.Time      `json:"expires_at" gorm:"not null;index"`
+	DecidedAt    *time.Time     `json:"decided_at,omitempty"`
+	ExecutedAt   *time.Time     `json:"executed_at,omitempty"`
+	CreatedAt    time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for ApprovalRequest model
+func (ApprovalRequest) TableName() string {
+	return "approval_requests"
+}
+
+// IsPending reports whether the request still awaits a decision at now
+func (r *ApprovalRequest) IsPending(now time.Time) bool {
+	return r.Status == ApprovalStatusPending && now.Before(r.ExpiresAt)
+}
+
+// ToResponse converts ApprovalRequest to ApprovalRequestResponse
+func (r *ApprovalRequest) ToResponse() ApprovalRequestResponse {
+	return ApprovalRequestResponse{
+		ID:           r.ID,
+		Type:         r.Type,
+		Payload:      json.RawMessage(r.Payload),
+		SubjectID:    r.SubjectID,
+		Reason:       r.Reason,
+		Status:       r.Status,
+		MakerID:      r.MakerID,
+		CheckerID:    r.CheckerID,
+		DecisionNote: r.DecisionNote,
+		Error:        r.Error,
+		JobID:        r.JobID,
+		ExpiresAt:    r.ExpiresAt,
+		DecidedAt:    r.DecidedAt,
+		ExecutedAt:   r.ExecutedAt,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+// ApprovalRequestResponse represents an approval request in API responses
+type ApprovalRequestResponse struct {
+	ID           uuid.UUID       `json:"id"`
+	Type         ApprovalType    `json:"type"`
+	Payload      json.RawMessage `json:"payload"`
+	SubjectID    uuid.UUID       `json:"subject_id"`
+	Reason       string          `json:"reason"`
+	Status       ApprovalStatus  `json:"status"`
+	MakerID      uuid.UUID       `json:"maker_id"`
+	CheckerID    *uuid.UUID      `json:"checker_id,omitempty"`
+	DecisionNote string          `json:"decision_note,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	JobID        *uuid.UUID      `json:"job_id,omitempty"`
+	ExpiresAt    time.Time       `json:"expires_at"`
+	DecidedAt    *time.Time      `json:"decided_at,omitempty"`
+	ExecutedAt   *time.Time      `json:"executed_at,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// RoleChangePayload is the operation of an ApprovalTypeRoleChange request
+type RoleChangePayload struct {
+	UserID uuid.UUID `json:"user_id"`
+	Role   UserRole  `json:"role"`
+}
+
+// CreditPayload is the operation of an ApprovalTypeCredit request
+type CreditPayload struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Amount    float64   `json:"amount"`
+}
+
+// RefundPayload is the operation of an ApprovalTypeRefund request
+type RefundPayload struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+}
+
+// AccountFreezePayload is the operation of an ApprovalTypeAccountFreeze request
+type AccountFreezePayload struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Frozen    bool      `json:"frozen"`
+}
+
+// DeadLetterEditPayload is the operation of an ApprovalTypeDeadLetterEdit request: the
+// dead-lettered job as it is replayed once approved
+type DeadLetterEditPayload struct {
+	DeadLetterID    uuid.UUID  `json:"dead_letter_id"`
+	TransactionType string     `json:"transaction_type"`
+	FromAccountID   *uuid.UUID `json:"from_account_id,omitempty"`
+	ToAccountID     *uuid.UUID `json:"to_account_id,omitempty"`
+	Amount          float64    `json:"amount"`
+	MaxRetries      int        `json:"max_retries"`
+}
+
+// ApprovalReasonRequest carries the maker's justification for an operation needing approval
+type ApprovalReasonRequest struct {
+	Reason string `json:"reason" binding:"required,min=5,max=500"`
+}
+
+// ApprovalDecisionRequest is the checker's optional note on approving or rejecting
+type ApprovalDecisionRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
@@ -9,13 +9,13 @@ type AuthRegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role,omitempty"`
 }
 
 // AuthLoginRequest represents the request for user login
 type AuthLoginRequest struct {
 	UsernameOrEmail string `json:"username_or_email" binding:"required"`
 	Password        string `json:"password" binding:"required"`
+	DeviceName      string `json:"device_name,omitempty" binding:"omitempty,max=100"`
 }
 
 // AuthRefreshRequest represents the request for token refresh
@@ -67,6 +67,7 @@ type UserUpdateRequest struct {
 	Username string `json:"username,omitempty" binding:"omitempty,min=3,max=50"`
 	Email    string `json:"email,omitempty" binding:"omitempty,email"`
 	Role     string `json:"role,omitempty" binding:"omitempty,oneof=admin teller customer"`
+	Reason   string `json:"reason,omitempty" binding:"max=500"` // justification for a role change, shown to approvers
 }
 
 // NewAuthResponse creates a new AuthResponse
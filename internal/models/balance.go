@@ -13,6 +13,7 @@ import (
 type Balance struct {
 	UserID        uuid.UUID `json:"user_id" gorm:"type:uuid;primary_key"`
 	Amount        float64   `json:"amount" gorm:"not null;type:decimal(15,2);default:0"`
+	Frozen        bool      `json:"frozen" gorm:"not null;default:false"` // frozen accounts cannot send money
 	LastUpdatedAt time.Time `json:"last_updated_at" gorm:"autoUpdateTime"`
 
 	// Thread-safety
@@ -31,6 +32,7 @@ func (*Balance) TableName() string {
 type BalanceResponse struct {
 	UserID        uuid.UUID `json:"user_id"`
 	Amount        float64   `json:"amount"`
+	Frozen        bool      `json:"frozen"`
 	LastUpdatedAt time.Time `json:"last_updated_at"`
 }
 
@@ -39,6 +41,7 @@ func (b *Balance) ToResponse() *BalanceResponse {
 	return &BalanceResponse{
 		UserID:        b.UserID,
 		Amount:        b.Amount,
+		Frozen:        b.Frozen,
 		LastUpdatedAt: b.LastUpdatedAt,
 	}
 }
@@ -225,10 +228,12 @@ func (b *Balance) MarshalJSON() ([]byte, error) {
 	aux := struct {
 		UserID        uuid.UUID `json:"user_id"`
 		Amount        float64   `json:"amount"`
+		Frozen        bool      `json:"frozen"`
 		LastUpdatedAt time.Time `json:"last_updated_at"`
 	}{
 		UserID:        b.UserID,
 		Amount:        b.Amount,
+		Frozen:        b.Frozen,
 		LastUpdatedAt: b.LastUpdatedAt,
 	}
 
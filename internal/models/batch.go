@@ -0,0 +1,172 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Batch represents a group of transfers submitted together, e.g. a payroll run
+type Batch struct {
+	ID          uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID      uuid.UUID   `json:"user_id" gorm:"type:uuid;not null;index"`
+	Mode        BatchMode   `json:"mode" gorm:"not null;size:20"`
+	Status      BatchStatus `json:"status" gorm:"not null;size:30;index"`
+	Reference   string      `json:"reference,omitempty" gorm:"size:100"`
+	ItemCount   int         `json:"item_count" gorm:"not null"`
+	TotalAmount float64     `json:"total_amount" gorm:"not null;type:decimal(15,2)"`
+	CreatedAt   time.Time   `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time   `json:"updated_at" gorm:"autoUpdateTime"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+
+	// Relationships
+	Items []BatchItem `json:"items,omitempty" gorm:"foreignKey:BatchID"`
+}
+
+// BatchItem represents a single transfer within a batch
+type BatchItem struct {
+	ID            uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	BatchID       uuid.UUID       `json:"batch_id" gorm:"type:uuid;not null;index"`
+	Position      int             `json:"position" gorm:"not null"`
+	ToUserID      uuid.UUID       `json:"to_user_id" gorm:"type:uuid;not null"`
+	Amount        float64         `json:"amount" gorm:"not null;type:decimal(15,2)"`
+	Reference     string          `json:"reference,omitempty" gorm:"size:100"`
+	Status        BatchItemStatus `json:"status" gorm:"not null;size:20"`
+	Error         string          `json:"error,omitempty" gorm:"type:text"`
+	JobID         *uuid.UUID      `json:"job_id,omitempty" gorm:"type:uuid"`
+	TransactionID *uuid.UUID      `json:"transaction_id,omitempty" gorm:"type:uuid"`
+	UpdatedAt     time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relationships
+	Job *Job `json:"-" gorm:"foreignKey:JobID"`
+}
+
+// BatchMode defines how failures of individual items affect the batch
+type BatchMode string
+
+const (
+	BatchModeAllOrNothing BatchMode = "all_or_nothing" // every item succeeds or none is applied
+	BatchModeBestEffort   BatchMode = "best_effort"    // items are processed independently
+)
+
+// BatchStatus defines the processing status of a batch
+type BatchStatus string
+
+const (
+	BatchStatusProcessing         BatchStatus = "processing"
+	BatchStatusCompleted          BatchStatus = "completed"
+	BatchStatusPartiallyCompleted BatchStatus = "partially_completed"
+	BatchStatusFailed             BatchStatus = "failed"
+)
+
+// BatchItemStatus defines the processing status of a batch item
+type BatchItemStatus string
+
+const (
+	BatchItemStatusPending    BatchItemStatus = "pending"
+	BatchItemStatusRejected   BatchItemStatus = "rejected"
+	BatchItemStatusQueued     BatchItemStatus = "queued"
+	BatchItemStatusProcessing BatchItemStatus = "processing"
+	BatchItemStatusCompleted  BatchItemStatus = "completed"
+	BatchItemStatusFailed     BatchItemStatus = "failed"
+)
+
+// TableName returns the table name for Batch model
+func (Batch) TableName() string {
+	return "batches"
+}
+
+// TableName returns the table name for BatchItem model
+func (BatchItem) TableName() string {
+	return "batch_items"
+}
+
+// IsFinished checks if the batch reached a final state
+func (b *Batch) IsFinished() bool {
+	return b.Status != BatchStatusProcessing
+}
+
+// IsFinished checks if the item reached a final state
+func (i *BatchItem) IsFinished() bool {
+	switch i.Status {
+	case BatchItemStatusRejected, BatchItemStatusCompleted, BatchItemStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// BatchTransferRequest represents a batch transfer submission
+type BatchTransferRequest struct {
+	Mode      BatchMode           `json:"mode" binding:"required,oneof=all_or_nothing best_effort"`
+	Reference string              `json:"reference,omitempty" binding:"max=100"`
+	Items     []BatchTransferItem `json:"items" binding:"required,min=1,dive"`
+}
+
+// BatchTransferItem represents a single transfer in a batch request
+type BatchTransferItem struct {
+	ToUserID  uuid.UUID `json:"to_user_id" binding:"required"`
+	Amount    float64   `json:"amount" binding:"required,gt=0"`
+	Reference string    `json:"reference,omitempty" binding:"max=100"`
+}
+
+// BatchResponse represents the response for batch data
+type BatchResponse struct {
+	ID          uuid.UUID            `json:"id"`
+	Mode        BatchMode            `json:"mode"`
+	Status      BatchStatus          `json:"status"`
+	Reference   string               `json:"reference,omitempty"`
+	ItemCount   int                  `json:"item_count"`
+	TotalAmount float64              `json:"total_amount"`
+	Summary     map[string]int       `json:"summary"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+	CompletedAt *time.Time           `json:"completed_at,omitempty"`
+	Items       []*BatchItemResponse `json:"items"`
+}
+
+// BatchItemResponse represents the response for batch item data
+type BatchItemResponse struct {
+	Position      int             `json:"position"`
+	ToUserID      uuid.UUID       `json:"to_user_id"`
+	Amount        float64         `json:"amount"`
+	Reference     string          `json:"reference,omitempty"`
+	Status        BatchItemStatus `json:"status"`
+	Error         string          `json:"error,omitempty"`
+	JobID         *uuid.UUID      `json:"job_id,omitempty"`
+	TransactionID *uuid.UUID      `json:"transaction_id,omitempty"`
+}
+
+// ToResponse converts Batch to BatchResponse
+func (b *Batch) ToResponse() *BatchResponse {
+	response := &BatchResponse{
+		ID:          b.ID,
+		Mode:        b.Mode,
+		Status:      b.Status,
+		Reference:   b.Reference,
+		ItemCount:   b.ItemCount,
+		TotalAmount: b.TotalAmount,
+		Summary:     make(map[string]int),
+		CreatedAt:   b.CreatedAt,
+		UpdatedAt:   b.UpdatedAt,
+		CompletedAt: b.CompletedAt,
+		Items:       make([]*BatchItemResponse, 0, len(b.Items)),
+	}
+
+	for i := range b.Items {
+		item := &b.Items[i]
+		response.Summary[string(item.Status)]++
+		response.Items = append(response.Items, &BatchItemResponse{
+			Position:      item.Position,
+			ToUserID:      item.ToUserID,
+			Amount:        item.Amount,
+			Reference:     item.Reference,
+			Status:        item.Status,
+			Error:         item.Error,
+			JobID:         item.JobID,
+			TransactionID: item.TransactionID,
+		})
+	}
+
+	return response
+}
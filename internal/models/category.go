@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransactionCategory classifies where money went from the paying account's point of view
+type TransactionCategory string
+
+const (
+	CategoryIncome        TransactionCategory = "income"
+	CategoryTransfer      TransactionCategory = "transfer"
+	CategoryCash          TransactionCategory = "cash"
+	CategoryGroceries     TransactionCategory = "groceries"
+	CategoryDining        TransactionCategory = "dining"
+	CategoryTransport     TransactionCategory = "transport"
+	CategoryUtilities     TransactionCategory = "utilities"
+	CategoryHousing       TransactionCategory = "housing"
+	CategoryShopping      TransactionCategory = "shopping"
+	CategoryEntertainment TransactionCategory = "entertainment"
+	CategoryHealth        TransactionCategory = "health"
+	CategoryEducation     TransactionCategory = "education"
+	CategorySalary        TransactionCategory = "salary"
+	CategoryOther         TransactionCategory = "other"
+)
+
+// TransactionCategories lists every category in display order
+var TransactionCategories = []TransactionCategory{
+	CategoryIncome, CategorySalary, CategoryTransfer, CategoryCash, CategoryGroceries,
+	CategoryDining, CategoryTransport, CategoryUtilities, CategoryHousing, CategoryShopping,
+	CategoryEntertainment, CategoryHealth, CategoryEducation, CategoryOther,
+}
+
+// IsValidTransactionCategory checks if the category is known
+func IsValidTransactionCategory(category TransactionCategory) bool {
+	for _, known := range TransactionCategories {
+		if category == known {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryRule is a user-defined categorization rule. A rule matches either a counterparty
+// or a keyword in the transaction reference; counterparty rules win over keyword rules.
+type CategoryRule struct {
+	ID             uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID         uuid.UUID           `json:"user_id" gorm:"type:uuid;not null;index"`
+	CounterpartyID *uuid.UUID          `json:"counterparty_id,omitempty" gorm:"type:uuid"`
+	Keyword        string              `json:"keyword,omitempty" gorm:"size:100"`
+	Category       TransactionCategory `json:"category" gorm:"not null;size:30"`
+	CreatedAt      time.Time           `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time           `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for CategoryRule model
+func (CategoryRule) TableName() string {
+	return "category_rules"
+}
+
+// CategoryRuleRequest represents a request to create or update a categorization rule
+type CategoryRuleRequest struct {
+	CounterpartyID *uuid.UUID          `json:"counterparty_id,omitempty"`
+	Keyword        string              `json:"keyword,omitempty" binding:"max=100"`
+	Category       TransactionCategory `json:"category" binding:"required"`
+}
+
+// CategoryOverrideRequest represents a request to set the category of a single transaction
+type CategoryOverrideRequest struct {
+	Category TransactionCategory `json:"category" binding:"required"`
+}
+
+// SpendingPeriod defines the length of a spending analytics period
+type SpendingPeriod string
+
+const (
+	SpendingPeriodWeek  SpendingPeriod = "week"
+	SpendingPeriodMonth SpendingPeriod = "month"
+	SpendingPeriodYear  SpendingPeriod = "year"
+)
+
+// CategorySpending is the spending of one category in a period
+type CategorySpending struct {
+	Category      TransactionCategory `json:"category"`
+	Total         float64             `json:"total"`
+	Count         int64               `json:"count"`
+	PreviousTotal float64             `json:"previous_total"`
+	Delta         float64             `json:"delta"`
+	DeltaPercent  *float64            `json:"delta_percent"`
+}
+
+// CounterpartySpending is the spending towards one counterparty in a period
+type CounterpartySpending struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Total    float64   `json:"total"`
+	Count    int64     `json:"count"`
+}
+
+// SpendingReport summarizes outgoing money for a period compared with the previous one
+type SpendingReport struct {
+	Period            SpendingPeriod          `json:"period"`
+	From              time.Time               `json:"from"`
+	To                time.Time               `json:"to"`
+	PreviousFrom      time.Time               `json:"previous_from"`
+	PreviousTo        time.Time               `json:"previous_to"`
+	Total             float64                 `json:"total"`
+	PreviousTotal     float64                 `json:"previous_total"`
+	Categories        []*CategorySpending     `json:"categories"`
+	TopCounterparties []*CounterpartySpending `json:"top_counterparties"`
+}
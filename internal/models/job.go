@@ -0,0 +1,160 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job represents an asynchronously processed transaction request
+type Job struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID        uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Type          JobType    `json:"type" gorm:"not null;size:20"`
+	FromAccountID *uuid.UUID `json:"from_account_id,omitempty" gorm:"type:uuid"`
+	ToAccountID   *uuid.UUID `json:"to_account_id,omitempty" gorm:"type:uuid"`
+	Amount        float64    `json:"amount" gorm:"not null;type:decimal(15,2)"`
+	Reference     string     `json:"reference,omitempty" gorm:"size:100"`
+	// SourceTransactionID is the transaction a refund job reverses
+	SourceTransactionID *uuid.UUID  `json:"source_transaction_id,omitempty" gorm:"type:uuid"`
+	Priority            JobPriority `json:"priority" gorm:"not null;size:20;default:'normal'"`
+	Status              JobStatus   `json:"status" gorm:"not null;default:'queued';index"`
+	Error               string      `json:"error,omitempty" gorm:"type:text"`
+	TransactionID       *uuid.UUID  `json:"transaction_id,omitempty" gorm:"type:uuid"`
+	RetryCount          int         `json:"retry_count" gorm:"not null;default:0"`
+	MaxRetries          int         `json:"max_retries" gorm:"not null;default:3"`
+	CreatedAt           time.Time   `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time   `json:"updated_at" gorm:"autoUpdateTime"`
+	StartedAt           *time.Time  `json:"started_at,omitempty"`
+	CompletedAt         *time.Time  `json:"completed_at,omitempty"`
+
+	// Relationships
+	Transaction *Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID"`
+}
+
+// JobType defines the kind of transaction a job executes
+type JobType string
+
+const (
+	JobTypeCredit   JobType = "credit"
+	JobTypeDebit    JobType = "debit"
+	JobTypeTransfer JobType = "transfer"
+	JobTypeRefund   JobType = "refund" // only submitted for approved refunds
+)
+
+// JobPriority defines the scheduling class of a job
+type JobPriority string
+
+const (
+	JobPriorityHigh   JobPriority = "high"   // teller and admin operations
+	JobPriorityBatch  JobPriority = "batch"  // salary and other bulk payment batches
+	JobPriorityNormal JobPriority = "normal" // regular customer traffic
+)
+
+// JobPriorities lists the priority classes from highest to lowest
+var JobPriorities = []JobPriority{JobPriorityHigh, JobPriorityBatch, JobPriorityNormal}
+
+// IsValidJobPriority checks if the given string is a supported priority class
+func IsValidJobPriority(priority string) bool {
+	switch JobPriority(priority) {
+	case JobPriorityHigh, JobPriorityBatch, JobPriorityNormal:
+		return true
+	default:
+		return false
+	}
+}
+
+// PriorityForRole returns the default priority class of jobs submitted by the given role
+func PriorityForRole(role UserRole) JobPriority {
+	switch role {
+	case RoleAdmin, RoleTeller:
+		return JobPriorityHigh
+	default:
+		return JobPriorityNormal
+	}
+}
+
+// JobStatus defines the processing status of a job
+type JobStatus string
+
+const (
+	JobStatusQueued     JobStatus = "queued"
+	JobStatusProcessing JobStatus = "processing"
+	JobStatusRetrying   JobStatus = "retrying"
+	JobStatusCompleted  JobStatus = "completed"
+	JobStatusFailed     JobStatus = "failed"
+)
+
+// TableName returns the table name for Job model
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// IsValidJobType checks if the given string is a supported job type
+func IsValidJobType(jobType string) bool {
+	switch JobType(jobType) {
+	case JobTypeCredit, JobTypeDebit, JobTypeTransfer:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinished checks if the job reached a final state
+func (j *Job) IsFinished() bool {
+	return j.Status == JobStatusCompleted || j.Status == JobStatusFailed
+}
+
+// AsyncTransactionRequest represents a request submitted to the async transaction API
+type AsyncTransactionRequest struct {
+	ToUserID  *uuid.UUID `json:"to_user_id,omitempty"`
+	Amount    float64    `json:"amount" binding:"required,gt=0"`
+	Reference string     `json:"reference,omitempty" binding:"max=100"`
+}
+
+// JobResponse represents the response for job data
+type JobResponse struct {
+	ID            uuid.UUID            `json:"id"`
+	Type          JobType              `json:"type"`
+	FromAccountID *uuid.UUID           `json:"from_account_id,omitempty"`
+	ToAccountID   *uuid.UUID           `json:"to_account_id,omitempty"`
+	Amount        float64              `json:"amount"`
+	Reference     string               `json:"reference,omitempty"`
+	Priority      JobPriority          `json:"priority"`
+	Status        JobStatus            `json:"status"`
+	Error         string               `json:"error,omitempty"`
+	RetryCount    int                  `json:"retry_count"`
+	MaxRetries    int                  `json:"max_retries"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+	StartedAt     *time.Time           `json:"started_at,omitempty"`
+	CompletedAt   *time.Time           `json:"completed_at,omitempty"`
+	Transaction   *TransactionResponse `json:"transaction,omitempty"`
+}
+
+// ToResponse converts Job to JobResponse
+func (j *Job) ToResponse() *JobResponse {
+	response := &JobResponse{
+		ID:            j.ID,
+		Type:          j.Type,
+		FromAccountID: j.FromAccountID,
+		ToAccountID:   j.ToAccountID,
+		Amount:        j.Amount,
+		Reference:     j.Reference,
+		Priority:      j.Priority,
+		Status:        j.Status,
+		Error:         j.Error,
+		RetryCount:    j.RetryCount,
+		MaxRetries:    j.MaxRetries,
+		CreatedAt:     j.CreatedAt,
+		UpdatedAt:     j.UpdatedAt,
+		StartedAt:     j.StartedAt,
+		CompletedAt:   j.CompletedAt,
+	}
+
+	if j.Transaction != nil {
+		response.Transaction = j.Transaction.ToResponse()
+	}
+
+	return response
+}
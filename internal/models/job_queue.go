@@ -0,0 +1,155 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueuedJob represents a pending worker pool job stored in the durable queue
+type QueuedJob struct {
+	ID                  uuid.UUID   `json:"id" gorm:"type:uuid;primary_key"`
+	TransactionType     string      `json:"transaction_type" gorm:"not null;size:20"`
+	UserID              *uuid.UUID  `json:"user_id,omitempty" gorm:"type:uuid"`
+	Priority            JobPriority `json:"priority" gorm:"not null;size:20;default:'normal';index"`
+	FromAccountID       *uuid.UUID  `json:"from_account_id,omitempty" gorm:"type:uuid"`
+	ToAccountID         *uuid.UUID  `json:"to_account_id,omitempty" gorm:"type:uuid"`
+	Amount              float64     `json:"amount" gorm:"not null;type:decimal(15,2)"`
+	SourceTransactionID *uuid.UUID  `json:"source_transaction_id,omitempty" gorm:"type:uuid"` // refunded transaction
+	Reference           string      `json:"reference,omitempty" gorm:"size:100"`
+	RetryCount          int         `json:"retry_count" gorm:"not null;default:0"`
+	MaxRetries          int         `json:"max_retries" gorm:"not null;default:3"`
+	Deliveries          int         `json:"deliveries" gorm:"not null;default:0"`
+	AvailableAt         time.Time   `json:"available_at" gorm:"not null;index"`
+	LeasedUntil         *time.Time  `json:"leased_until,omitempty" gorm:"index"`
+	LeaseOwner          string      `json:"lease_owner,omitempty" gorm:"size:100"`
+	LastError           string      `json:"last_error,omitempty" gorm:"type:text"`
+	ErrorChain          string      `json:"-" gorm:"type:text"` // JSON encoded list of attempt errors
+	CreatedAt           time.Time   `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for QueuedJob model
+func (QueuedJob) TableName() string {
+	return "job_queue"
+}
+
+// DeadLetterStatus represents the resolution state of a dead-lettered job
+type DeadLetterStatus string
+
+const (
+	DeadLetterStatusPending   DeadLetterStatus = "pending"
+	DeadLetterStatusReplayed  DeadLetterStatus = "replayed"
+	DeadLetterStatusDiscarded DeadLetterStatus = "discarded"
+)
+
+// DeadLetterJob represents a job that could not be processed and was removed from the queue
+type DeadLetterJob struct {
+	ID                  uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	JobID               uuid.UUID        `json:"job_id" gorm:"type:uuid;not null;index"`
+	TransactionType     string           `json:"transaction_type" gorm:"not null;size:20"`
+	UserID              *uuid.UUID       `json:"user_id,omitempty" gorm:"type:uuid"`
+	Priority            JobPriority      `json:"priority" gorm:"not null;size:20;default:'normal'"`
+	FromAccountID       *uuid.UUID       `json:"from_account_id,omitempty" gorm:"type:uuid"`
+	ToAccountID         *uuid.UUID       `json:"to_account_id,omitempty" gorm:"type:uuid"`
+	Amount              float64          `json:"amount" gorm:"not null;type:decimal(15,2)"`
+	SourceTransactionID *uuid.UUID       `json:"source_transaction_id,omitempty" gorm:"type:uuid"` // refunded transaction
+	Reference           string           `json:"reference,omitempty" gorm:"size:100"`
+	RetryCount          int              `json:"retry_count" gorm:"not null;default:0"`
+	MaxRetries          int              `json:"max_retries" gorm:"not null;default:3"`
+	Reason              string           `json:"reason" gorm:"type:text"`
+	ErrorChain          string           `json:"-" gorm:"type:text"` // JSON encoded list of attempt errors
+	Status              DeadLetterStatus `json:"status" gorm:"not null;size:20;default:'pending';index"`
+	ResolvedBy          *uuid.UUID       `json:"resolved_by,omitempty" gorm:"type:uuid"`
+	ResolvedAt          *time.Time       `json:"resolved_at,omitempty"`
+	FailedAt            time.Time        `json:"failed_at" gorm:"not null;index"`
+	CreatedAt           time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for DeadLetterJob model
+func (DeadLetterJob) TableName() string {
+	return "dead_letter_jobs"
+}
+
+// IsPending checks if the dead-lettered job still awaits a decision
+func (d *DeadLetterJob) IsPending() bool {
+	return d.Status == DeadLetterStatusPending
+}
+
+// UpdateDeadLetterJobRequest represents the editable fields of a dead-lettered job
+type UpdateDeadLetterJobRequest struct {
+	FromAccountID *uuid.UUID `json:"from_account_id,omitempty"`
+	ToAccountID   *uuid.UUID `json:"to_account_id,omitempty"`
+	Amount        *float64   `json:"amount,omitempty" binding:"omitempty,gt=0"`
+	MaxRetries    *int       `json:"max_retries,omitempty" binding:"omitempty,gte=0,lte=10"`
+	Reason        string     `json:"reason,omitempty" binding:"omitempty,min=5,max=500"` // approval reason for account or amount edits
+}
+
+// DeadLetterJobResponse represents the dead-lettered job data returned in API responses
+type DeadLetterJobResponse struct {
+	ID              uuid.UUID        `json:"id"`
+	JobID           uuid.UUID        `json:"job_id"`
+	TransactionType string           `json:"transaction_type"`
+	UserID          *uuid.UUID       `json:"user_id,omitempty"`
+	Priority        JobPriority      `json:"priority"`
+	FromAccountID   *uuid.UUID       `json:"from_account_id,omitempty"`
+	ToAccountID     *uuid.UUID       `json:"to_account_id,omitempty"`
+	Amount          float64          `json:"amount"`
+	RetryCount      int              `json:"retry_count"`
+	MaxRetries      int              `json:"max_retries"`
+	Reason          string           `json:"reason"`
+	ErrorChain      []string         `json:"error_chain"`
+	Status          DeadLetterStatus `json:"status"`
+	ResolvedBy      *uuid.UUID       `json:"resolved_by,omitempty"`
+	ResolvedAt      *time.Time       `json:"resolved_at,omitempty"`
+	FailedAt        time.Time        `json:"failed_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// ToResponse converts DeadLetterJob to DeadLetterJobResponse
+func (d *DeadLetterJob) ToResponse() *DeadLetterJobResponse {
+	return &DeadLetterJobResponse{
+		ID:              d.ID,
+		JobID:           d.JobID,
+		TransactionType: d.TransactionType,
+		UserID:          d.UserID,
+		Priority:        d.Priority,
+		FromAccountID:   d.FromAccountID,
+		ToAccountID:     d.ToAccountID,
+		Amount:          d.Amount,
+		RetryCount:      d.RetryCount,
+		MaxRetries:      d.MaxRetries,
+		Reason:          d.Reason,
+		ErrorChain:      DecodeErrorChain(d.ErrorChain),
+		Status:          d.Status,
+		ResolvedBy:      d.ResolvedBy,
+		ResolvedAt:      d.ResolvedAt,
+		FailedAt:        d.FailedAt,
+		UpdatedAt:       d.UpdatedAt,
+	}
+}
+
+// EncodeErrorChain serializes a list of attempt errors for storage
+func EncodeErrorChain(chain []string) string {
+	if len(chain) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(chain)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// DecodeErrorChain deserializes a stored list of attempt errors
+func DecodeErrorChain(data string) []string {
+	chain := []string{}
+	if data == "" {
+		return chain
+	}
+	if err := json.Unmarshal([]byte(data), &chain); err != nil {
+		return []string{data}
+	}
+	return chain
+}
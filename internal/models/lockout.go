@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LockoutScope is what failed login attempts are counted against
+type LockoutScope string
+
+const (
+	LockoutScopeUser LockoutScope = "user"
+	LockoutScopeIP   LockoutScope = "ip"
+)
+
+// LoginThrottle counts failed logins of one account or client IP. It lives in the database
+// so every replica enforces the same delays and lockouts.
+type LoginThrottle struct {
+	Scope        LockoutScope `json:"scope" gorm:"primaryKey;size:10"`
+	Key          string       `json:"key" gorm:"primaryKey;size:64"` // user ID or IP address
+	FailedCount  int          `json:"failed_count" gorm:"not null;default:0"`
+	LockCount    int          `json:"lock_count" gorm:"not null;default:0"` // lockouts so far; each next one lasts longer
+	LastFailedAt *time.Time   `json:"last_failed_at,omitempty"`
+	LockedUntil  *time.Time   `json:"locked_until,omitempty" gorm:"index"`
+	UpdatedAt    time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for LoginThrottle model
+func (LoginThrottle) TableName() string {
+	return "login_throttles"
+}
+
+// IsLocked reports whether the throttle blocks logins at now
+func (t *LoginThrottle) IsLocked(now time.Time) bool {
+	return t.LockedUntil != nil && t.LockedUntil.After(now)
+}
+
+// LockoutStatus is the lockout state of an account shown to admins
+type LockoutStatus struct {
+	UserID         uuid.UUID  `json:"user_id"`
+	Locked         bool       `json:"locked"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+	FailedAttempts int        `json:"failed_attempts"`
+	LockCount      int        `json:"lock_count"`
+	LastFailedAt   *time.Time `json:"last_failed_at,omitempty"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MFARecoveryCodeCount is the number of recovery codes issued at a time
+const MFARecoveryCodeCount = 10
+
+// AuthMethod is an authentication method reference (RFC 8176 amr value)
+type AuthMethod string
+
+const (
+	AuthMethodPassword AuthMethod = "pwd"
+	AuthMethodOTP      AuthMethod = "otp"
+	// AuthMethodRecoveryCode is not registered in RFC 8176; recovery codes are one-time passwords
+	AuthMethodRecoveryCode AuthMethod = "rc"
+)
+
+// UserMFA holds a user's TOTP secret. The secret is stored encrypted; MFA is enabled only
+// once the user proved the authenticator works (ConfirmedAt).
+type UserMFA struct {
+	UserID          uuid.UUID  `json:"user_id" gorm:"type:uuid;primary_key"`
+	SecretEncrypted string     `json:"-" gorm:"not null"`
+	ConfirmedAt     *time.Time `json:"confirmed_at,omitempty"`
+	LastUsedStep    int64      `json:"-"` // TOTP time step of the last accepted code, to reject replays
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for UserMFA model
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// MFARecoveryCode is a hashed one-time recovery code
+type MFARecoveryCode struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	CodeHash  string     `json:"-" gorm:"not null;size:64"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for MFARecoveryCode model
+func (MFARecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+// MFAEnrollment is returned when TOTP enrollment starts
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFACodeRequest carries a TOTP code or a recovery code
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+// MFALoginRequest is the second step of a login for users with MFA enabled
+type MFALoginRequest struct {
+	MFAToken   string `json:"mfa_token" binding:"required"`
+	Code       string `json:"code" binding:"required,max=32"` // TOTP code or recovery code
+	DeviceName string `json:"device_name,omitempty" binding:"omitempty,max=100"`
+}
+
+// MFAChallengeResponse is returned by the first login step when a second factor is required
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordHistory keeps the hashes of a user's recent passwords so they cannot be reused
+type PasswordHistory struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID       uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index:idx_password_histories_user_created"`
+	PasswordHash string    `json:"-" gorm:"not null;size:255"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_password_histories_user_created"`
+}
+
+// TableName returns the table name for PasswordHistory model
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
+
+// PasswordChangeRequest changes the password of the authenticated user
+type PasswordChangeRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentImport represents an uploaded bulk payment file awaiting confirmation
+type PaymentImport struct {
+	ID          uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID      uuid.UUID           `json:"user_id" gorm:"type:uuid;not null;index"`
+	Format      PaymentFileFormat   `json:"format" gorm:"not null;size:20"`
+	FileName    string              `json:"file_name" gorm:"size:255"`
+	FileHash    string              `json:"-" gorm:"not null;size:64;index"`
+	MessageID   string              `json:"message_id" gorm:"not null;size:35"`
+	Status      PaymentImportStatus `json:"status" gorm:"not null;size:20;index"`
+	RowCount    int                 `json:"row_count" gorm:"not null"`
+	ValidCount  int                 `json:"valid_count" gorm:"not null"`
+	ValidTotal  float64             `json:"valid_total" gorm:"not null;type:decimal(15,2)"`
+	BatchID     *uuid.UUID          `json:"batch_id,omitempty" gorm:"type:uuid"`
+	ExpiresAt   time.Time           `json:"expires_at" gorm:"not null"`
+	ConfirmedAt *time.Time          `json:"confirmed_at,omitempty"`
+	CreatedAt   time.Time           `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time           `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relationships
+	Rows []PaymentImportRow `json:"rows,omitempty" gorm:"foreignKey:ImportID"`
+}
+
+// PaymentImportRow represents a single parsed and validated payment of an import
+type PaymentImportRow struct {
+	ID                   uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ImportID             uuid.UUID  `json:"import_id" gorm:"type:uuid;not null;index"`
+	Line                 int        `json:"line" gorm:"not null"`
+	PaymentInformationID string     `json:"payment_information_id,omitempty" gorm:"size:35"`
+	EndToEndID           string     `json:"end_to_end_id,omitempty" gorm:"size:35"`
+	Recipient            string     `json:"recipient" gorm:"size:100"`
+	RecipientName        string     `json:"recipient_name,omitempty" gorm:"size:140"`
+	ToUserID             *uuid.UUID `json:"to_user_id,omitempty" gorm:"type:uuid"`
+	Amount               float64    `json:"amount" gorm:"not null;type:decimal(15,2)"`
+	Currency             string     `json:"currency" gorm:"size:3"`
+	Reference            string     `json:"reference,omitempty" gorm:"size:140"`
+	Valid                bool       `json:"valid" gorm:"not null"`
+	Error                string     `json:"error,omitempty" gorm:"type:text"`
+	BatchPosition        *int       `json:"batch_position,omitempty"`
+}
+
+// PaymentFileFormat defines the supported bulk payment file formats
+type PaymentFileFormat string
+
+const (
+	PaymentFileFormatCSV     PaymentFileFormat = "csv"
+	PaymentFileFormatPain001 PaymentFileFormat = "pain.001"
+)
+
+// PaymentImportStatus defines the lifecycle of an import
+type PaymentImportStatus string
+
+const (
+	PaymentImportStatusPreview   PaymentImportStatus = "preview"   // parsed, waiting for confirmation
+	PaymentImportStatusConfirmed PaymentImportStatus = "confirmed" // executed as a batch
+	PaymentImportStatusCancelled PaymentImportStatus = "cancelled"
+	PaymentImportStatusExpired   PaymentImportStatus = "expired" // not confirmed in time
+)
+
+// TableName returns the table name for PaymentImport model
+func (PaymentImport) TableName() string {
+	return "payment_imports"
+}
+
+// TableName returns the table name for PaymentImportRow model
+func (PaymentImportRow) TableName() string {
+	return "payment_import_rows"
+}
+
+// IsValidPaymentFileFormat checks if the given string is a supported file format
+func IsValidPaymentFileFormat(format string) bool {
+	switch PaymentFileFormat(format) {
+	case PaymentFileFormatCSV, PaymentFileFormatPain001:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsExpired checks if a previewed import passed its confirmation deadline
+func (p *PaymentImport) IsExpired(now time.Time) bool {
+	return p.Status == PaymentImportStatusPreview && now.After(p.ExpiresAt)
+}
+
+// PaymentImportResponse represents the response for import data
+type PaymentImportResponse struct {
+	ID              uuid.UUID           `json:"id"`
+	Format          PaymentFileFormat   `json:"format"`
+	FileName        string              `json:"file_name"`
+	MessageID       string              `json:"message_id"`
+	Status          PaymentImportStatus `json:"status"`
+	RowCount        int                 `json:"row_count"`
+	ValidCount      int                 `json:"valid_count"`
+	InvalidCount    int                 `json:"invalid_count"`
+	ValidTotal      float64             `json:"valid_total"`
+	FundsSufficient *bool               `json:"funds_sufficient,omitempty"`
+	ExpiresAt       time.Time           `json:"expires_at"`
+	ConfirmedAt     *time.Time          `json:"confirmed_at,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+	Rows            []PaymentImportRow  `json:"rows"`
+	Batch           *BatchResponse      `json:"batch,omitempty"`
+}
+
+// ToResponse converts PaymentImport to PaymentImportResponse
+func (p *PaymentImport) ToResponse() *PaymentImportResponse {
+	return &PaymentImportResponse{
+		ID:           p.ID,
+		Format:       p.Format,
+		FileName:     p.FileName,
+		MessageID:    p.MessageID,
+		Status:       p.Status,
+		RowCount:     p.RowCount,
+		ValidCount:   p.ValidCount,
+		InvalidCount: p.RowCount - p.ValidCount,
+		ValidTotal:   p.ValidTotal,
+		ExpiresAt:    p.ExpiresAt,
+		ConfirmedAt:  p.ConfirmedAt,
+		CreatedAt:    p.CreatedAt,
+		Rows:         p.Rows,
+	}
+}
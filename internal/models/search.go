@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Search result limits
+const (
+	DefaultSearchLimit  = 20
+	MaxSearchLimit      = 50
+	MaxSearchTextLength = 200
+)
+
+// SearchResultType defines which kind of record a search result refers to
+type SearchResultType string
+
+const (
+	SearchResultTransaction SearchResultType = "transaction"
+	SearchResultAuditLog    SearchResultType = "audit_log"
+)
+
+// SearchQuery is a full-text search over transactions and audit logs
+type SearchQuery struct {
+	Text  string
+	Types []SearchResultType
+	From  *time.Time
+	To    *time.Time
+	Limit int
+
+	// OwnerID restricts results to the given user's records; nil searches everything
+	OwnerID *uuid.UUID
+}
+
+// Includes reports whether the query searches the given result type
+func (q *SearchQuery) Includes(resultType SearchResultType) bool {
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, t := range q.Types {
+		if t == resultType {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchResult is a single ranked search hit. Highlight is HTML-escaped text with the
+// matching terms wrapped in <mark> tags.
+type SearchResult struct {
+	Type        SearchResultType     `json:"type"`
+	ID          uuid.UUID            `json:"id"`
+	Rank        float64              `json:"rank"`
+	Highlight   string               `json:"highlight"`
+	CreatedAt   time.Time            `json:"created_at"`
+	Transaction *TransactionResponse `json:"transaction,omitempty"`
+	AuditLog    *AuditLogResponse    `json:"audit_log,omitempty"`
+}
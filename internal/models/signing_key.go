@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// JWTSigningKey is a stored asymmetric JWT key. The private key is PKCS#8 DER, encrypted
+// with AES-256-GCM; public keys are derived from it and published in the JWKS.
+type JWTSigningKey struct {
+	ID          string    `json:"kid" gorm:"primaryKey;size:64"`
+	Algorithm   string    `json:"alg" gorm:"not null;size:10"`
+	PrivateKey  string    `json:"-" gorm:"type:text;not null"`
+	ActivatesAt time.Time `json:"activates_at" gorm:"not null;index"` // signs from here until a newer key activates
+	ExpiresAt   time.Time `json:"expires_at" gorm:"not null;index"`   // verifies and stays in the JWKS until here
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for JWTSigningKey model
+func (JWTSigningKey) TableName() string {
+	return "jwt_signing_keys"
+}
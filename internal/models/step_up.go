@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Authentication context class references carried in the acr claim of step-up tokens
+// (NIST SP 800-63B authenticator assurance levels)
+const (
+	ACRPassword    = "aal1" // single factor: password
+	ACRMultiFactor = "aal2" // second factor: TOTP or recovery code
+)
+
+// StepUpChallenge is returned when an operation needs fresh authentication
+type StepUpChallenge struct {
+	StepUpRequired bool         `json:"step_up_required"`
+	ChallengeToken string       `json:"challenge_token"`
+	Methods        []AuthMethod `json:"methods"` // methods accepted to complete the challenge
+	ACR            string       `json:"acr"`     // assurance level the elevated token will carry
+	ExpiresIn      int64        `json:"expires_in"`
+}
+
+// StepUpRequest completes a step-up challenge. Users with MFA enabled send a TOTP or
+// recovery code, other users their password.
+type StepUpRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Password       string `json:"password,omitempty"`
+	Code           string `json:"code,omitempty" binding:"max=32"`
+}
+
+// StepUpResponse carries the elevated token, sent in the X-Step-Up-Token header of sensitive requests
+type StepUpResponse struct {
+	StepUpToken string       `json:"step_up_token"`
+	ExpiresIn   int64        `json:"expires_in"`
+	AMR         []AuthMethod `json:"amr"`
+	ACR         string       `json:"acr"`
+}
+
+// StepUpChallengeFailure counts wrong credentials sent for one step-up challenge; the
+// challenge is used up once the count reaches the limit
+type StepUpChallengeFailure struct {
+	ChallengeID uuid.UUID `json:"challenge_id" gorm:"type:uuid;primary_key"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Failures    int       `json:"failures" gorm:"not null;default:0"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"not null;index"` // when the challenge itself expires
+}
+
+// TableName returns the table name for StepUpChallengeFailure model
+func (StepUpChallengeFailure) TableName() string {
+	return "step_up_challenge_failures"
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenRevocationReason records why a token family or token was revoked
+type TokenRevocationReason string
+
+const (
+	TokenRevokedLogout          TokenRevocationReason = "logout"
+	TokenRevokedLogoutAll       TokenRevocationReason = "logout_all"
+	TokenRevokedReuseDetected   TokenRevocationReason = "reuse_detected"
+	TokenRevokedSession         TokenRevocationReason = "session_revoked"
+	TokenRevokedByAdmin         TokenRevocationReason = "admin_revoked"
+	TokenRevokedChallengeUsed   TokenRevocationReason = "challenge_used"
+	TokenRevokedPasswordReset   TokenRevocationReason = "password_reset"
+	TokenRevokedPasswordChanged TokenRevocationReason = "password_changed"
+)
+
+// TokenFamily is the chain of refresh tokens issued from one login, i.e. a session on one
+// device. Every access token carries its family ID in the sid claim, so revoking the
+// family ends the whole login.
+type TokenFamily struct {
+	ID            uuid.UUID             `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID        uuid.UUID             `json:"user_id" gorm:"type:uuid;not null;index"`
+	Device        string                `json:"device" gorm:"size:100"`
+	UserAgent     string                `json:"user_agent" gorm:"size:512"`
+	IPAddress     string                `json:"ip_address" gorm:"size:45"` // last seen client IP
+	LastSeenAt    time.Time             `json:"last_seen_at"`
+	ExpiresAt     time.Time             `json:"expires_at" gorm:"not null"`
+	RevokedAt     *time.Time            `json:"revoked_at,omitempty"`
+	RevokedReason TokenRevocationReason `json:"revoked_reason,omitempty" gorm:"size:32"`
+	CreatedAt     time.Time             `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for TokenFamily model
+func (TokenFamily) TableName() string {
+	return "token_families"
+}
+
+// IsActive reports whether the family can still be refreshed
+func (f *TokenFamily) IsActive(now time.Time) bool {
+	return f.RevokedAt == nil && now.Before(f.ExpiresAt)
+}
+
+// SessionClient describes the client a session is opened from
+type SessionClient struct {
+	Device    string // name given by the client; derived from the user agent when empty
+	UserAgent string
+	IPAddress string
+}
+
+// SessionResponse represents a login session shown to its user or an admin
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// ToSessionResponse converts TokenFamily to SessionResponse; current marks the caller's own session
+func (f *TokenFamily) ToSessionResponse(current bool) *SessionResponse {
+	return &SessionResponse{
+		ID:         f.ID,
+		Device:     f.Device,
+		UserAgent:  f.UserAgent,
+		IPAddress:  f.IPAddress,
+		CreatedAt:  f.CreatedAt,
+		LastSeenAt: f.LastSeenAt,
+		ExpiresAt:  f.ExpiresAt,
+		Current:    current,
+	}
+}
+
+// RefreshToken is one issued refresh token; its ID is the token's jti. A refresh token
+// can be exchanged once: presenting it again after UsedAt is set is treated as theft.
+type RefreshToken struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	FamilyID  uuid.UUID  `json:"family_id" gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty" gorm:"type:uuid"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for RefreshToken model
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// RevokedToken is an entry of the token revocation list (access tokens and used MFA
+// challenges), kept until the token would have expired anyway
+type RevokedToken struct {
+	JTI       uuid.UUID             `json:"jti" gorm:"column:jti;type:uuid;primary_key"`
+	UserID    uuid.UUID             `json:"user_id" gorm:"type:uuid;not null"`
+	Reason    TokenRevocationReason `json:"reason" gorm:"size:32"`
+	ExpiresAt time.Time             `json:"expires_at" gorm:"not null;index"`
+	RevokedAt time.Time             `json:"revoked_at" gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for RevokedToken model
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+// TokenPair is the result of a login or refresh
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	SessionID    uuid.UUID
+	ExpiresIn    int64 // access token lifetime in seconds
+}
@@ -11,14 +11,17 @@ import (
 
 // Transaction represents a financial transaction
 type Transaction struct {
-	ID         uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	FromUserID *uuid.UUID        `json:"from_user_id" gorm:"type:uuid;index"`
-	ToUserID   *uuid.UUID        `json:"to_user_id" gorm:"type:uuid;index"`
-	Amount     float64           `json:"amount" gorm:"not null;type:decimal(15,2)"`
-	Type       TransactionType   `json:"type" gorm:"not null"`
-	Status     TransactionStatus `json:"status" gorm:"not null;default:'pending'"`
-	Reference  string            `json:"reference,omitempty" gorm:"size:100"`
-	CreatedAt  time.Time         `json:"created_at" gorm:"autoCreateTime"`
+	ID                 uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();index:idx_transactions_created_at_id,priority:2"`
+	FromUserID         *uuid.UUID          `json:"from_user_id" gorm:"type:uuid;index"`
+	ToUserID           *uuid.UUID          `json:"to_user_id" gorm:"type:uuid;index"`
+	Amount             float64             `json:"amount" gorm:"not null;type:decimal(15,2)"`
+	Type               TransactionType     `json:"type" gorm:"not null"`
+	Status             TransactionStatus   `json:"status" gorm:"not null;default:'pending'"`
+	Reference          string              `json:"reference,omitempty" gorm:"size:100"`
+	Category           TransactionCategory `json:"category,omitempty" gorm:"size:30;index"`
+	CategoryOverridden bool                `json:"category_overridden" gorm:"not null;default:false"` // set by the user, kept by recategorization
+	JobID              *uuid.UUID          `json:"job_id,omitempty" gorm:"type:uuid;uniqueIndex"`
+	CreatedAt          time.Time           `json:"created_at" gorm:"autoCreateTime;index:idx_transactions_created_at_id,priority:1"`
 
 	// Relationships
 	FromUser *User `json:"from_user,omitempty" gorm:"foreignKey:FromUserID"`
@@ -61,8 +64,9 @@ type TransferRequest struct {
 
 // DepositRequest represents a deposit request
 type DepositRequest struct {
-	Amount    float64 `json:"amount" binding:"required,gt=0"`
-	Reference string  `json:"reference,omitempty" binding:"max=100"`
+	Amount    float64    `json:"amount" binding:"required,gt=0"`
+	Reference string     `json:"reference,omitempty" binding:"max=100"`
+	AccountID *uuid.UUID `json:"account_id,omitempty"` // account to credit; defaults to the caller's own
 }
 
 // WithdrawRequest represents a withdrawal request
@@ -73,14 +77,15 @@ type WithdrawRequest struct {
 
 // TransactionResponse represents the response for transaction data
 type TransactionResponse struct {
-	ID         uuid.UUID         `json:"id"`
-	FromUserID *uuid.UUID        `json:"from_user_id"`
-	ToUserID   *uuid.UUID        `json:"to_user_id"`
-	Amount     float64           `json:"amount"`
-	Type       TransactionType   `json:"type"`
-	Status     TransactionStatus `json:"status"`
-	Reference  string            `json:"reference,omitempty"`
-	CreatedAt  time.Time         `json:"created_at"`
+	ID         uuid.UUID           `json:"id"`
+	FromUserID *uuid.UUID          `json:"from_user_id"`
+	ToUserID   *uuid.UUID          `json:"to_user_id"`
+	Amount     float64             `json:"amount"`
+	Type       TransactionType     `json:"type"`
+	Status     TransactionStatus   `json:"status"`
+	Reference  string              `json:"reference,omitempty"`
+	Category   TransactionCategory `json:"category,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
 }
 
 // ToResponse converts Transaction to TransactionResponse
@@ -93,6 +98,7 @@ func (t *Transaction) ToResponse() *TransactionResponse {
 		Type:       t.Type,
 		Status:     t.Status,
 		Reference:  t.Reference,
+		Category:   t.Category,
 		CreatedAt:  t.CreatedAt,
 	}
 }
@@ -169,6 +175,23 @@ func (t *Transaction) IsFinalized() bool {
 		t.Status == TransactionStatusCancelled
 }
 
+// IsRefundable reports whether the transaction is a completed deposit, withdrawal or
+// transfer that has not been refunded
+func (t *Transaction) IsRefundable() bool {
+	switch t.Type {
+	case TransactionTypeDeposit, TransactionTypeWithdraw, TransactionTypeTransfer:
+		return t.CanTransitionTo(TransactionStatusRefund)
+	default:
+		return false
+	}
+}
+
+// IsParty reports whether the user sent or received the transaction
+func (t *Transaction) IsParty(userID uuid.UUID) bool {
+	return (t.FromUserID != nil && *t.FromUserID == userID) ||
+		(t.ToUserID != nil && *t.ToUserID == userID)
+}
+
 // Validate validates the transaction fields
 func (t *Transaction) Validate() error {
 	if t.Amount <= 0 {
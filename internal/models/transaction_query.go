@@ -0,0 +1,235 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transaction page size limits
+const (
+	DefaultTransactionPageSize = 50
+	MaxTransactionPageSize     = 100
+)
+
+// Transaction query errors
+var (
+	ErrInvalidTransactionQuery = errors.New("invalid transaction query")
+	ErrInvalidCursor           = errors.New("invalid cursor")
+)
+
+// TransactionSortField defines the column transactions are ordered by
+type TransactionSortField string
+
+const (
+	TransactionSortCreatedAt TransactionSortField = "created_at"
+	TransactionSortAmount    TransactionSortField = "amount"
+)
+
+// SortDirection defines the ordering direction
+type SortDirection string
+
+const (
+	SortAscending  SortDirection = "asc"
+	SortDescending SortDirection = "desc"
+)
+
+// TransactionFilter narrows down the transactions a query returns; zero values are ignored
+type TransactionFilter struct {
+	AccountID      *uuid.UUID
+	Types          []TransactionType
+	Statuses       []TransactionStatus
+	From           *time.Time
+	To             *time.Time
+	MinAmount      *float64
+	MaxAmount      *float64
+	CounterpartyID *uuid.UUID
+	Reference      string
+}
+
+// TransactionQuery is a filtered, sorted and keyset-paginated transaction lookup
+type TransactionQuery struct {
+	Filter  TransactionFilter
+	SortBy  TransactionSortField
+	SortDir SortDirection
+	Cursor  *TransactionCursor
+	Limit   int
+}
+
+// TransactionCursor marks the last transaction of a page; the next page starts after it
+type TransactionCursor struct {
+	CreatedAt   time.Time `json:"c"`
+	Amount      float64   `json:"a,omitempty"`
+	ID          uuid.UUID `json:"i"`
+	Fingerprint string    `json:"f"`
+}
+
+// TransactionPage is a single page of a transaction query
+type TransactionPage struct {
+	Transactions []*Transaction
+	NextCursor   string
+	HasMore      bool
+}
+
+// NewTransactionQuery creates a query sorted by newest first with the default page size
+func NewTransactionQuery() *TransactionQuery {
+	return &TransactionQuery{
+		SortBy:  TransactionSortCreatedAt,
+		SortDir: SortDescending,
+		Limit:   DefaultTransactionPageSize,
+	}
+}
+
+// Validate checks the query and fills in defaults for sorting and page size
+func (q *TransactionQuery) Validate() error {
+	if q.SortBy == "" {
+		q.SortBy = TransactionSortCreatedAt
+	}
+	if q.SortDir == "" {
+		q.SortDir = SortDescending
+	}
+	if q.SortBy != TransactionSortCreatedAt && q.SortBy != TransactionSortAmount {
+		return fmt.Errorf("%w: sort must be created_at or amount", ErrInvalidTransactionQuery)
+	}
+	if q.SortDir != SortAscending && q.SortDir != SortDescending {
+		return fmt.Errorf("%w: order must be asc or desc", ErrInvalidTransactionQuery)
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultTransactionPageSize
+	}
+	if q.Limit > MaxTransactionPageSize {
+		return fmt.Errorf("%w: limit must be at most %d", ErrInvalidTransactionQuery, MaxTransactionPageSize)
+	}
+
+	f := q.Filter
+	for _, transactionType := range f.Types {
+		switch transactionType {
+		case TransactionTypeTransfer, TransactionTypeDeposit, TransactionTypeWithdraw,
+			TransactionTypePayment, TransactionTypeRefund:
+		default:
+			return fmt.Errorf("%w: unknown type %q", ErrInvalidTransactionQuery, transactionType)
+		}
+	}
+	for _, status := range f.Statuses {
+		switch status {
+		case TransactionStatusPending, TransactionStatusCompleted, TransactionStatusFailed,
+			TransactionStatusCancelled, TransactionStatusRefund:
+		default:
+			return fmt.Errorf("%w: unknown status %q", ErrInvalidTransactionQuery, status)
+		}
+	}
+	if f.From != nil && f.To != nil && f.From.After(*f.To) {
+		return fmt.Errorf("%w: from must not be after to", ErrInvalidTransactionQuery)
+	}
+	if f.MinAmount != nil && f.MaxAmount != nil && *f.MinAmount > *f.MaxAmount {
+		return fmt.Errorf("%w: min_amount must not exceed max_amount", ErrInvalidTransactionQuery)
+	}
+	if len(f.Reference) > 100 {
+		return fmt.Errorf("%w: reference must be at most 100 characters", ErrInvalidTransactionQuery)
+	}
+
+	if q.Cursor != nil && q.Cursor.Fingerprint != q.fingerprint() {
+		return fmt.Errorf("%w: cursor belongs to a different query", ErrInvalidCursor)
+	}
+	return nil
+}
+
+// CacheKey returns a deterministic key for the query, including the page position
+func (q *TransactionQuery) CacheKey() string {
+	position := "first"
+	if q.Cursor != nil {
+		position = q.Cursor.Encode()
+	}
+	return fmt.Sprintf("transactions:%s:%d:%s", q.fingerprint(), q.Limit, position)
+}
+
+// NewPage builds a page from rows fetched with a limit of Limit+1; the extra row only
+// signals that another page exists
+func (q *TransactionQuery) NewPage(rows []*Transaction) *TransactionPage {
+	page := &TransactionPage{Transactions: rows}
+	if len(rows) > q.Limit {
+		page.Transactions = rows[:q.Limit]
+		page.HasMore = true
+	}
+	if page.HasMore {
+		last := page.Transactions[len(page.Transactions)-1]
+		cursor := &TransactionCursor{
+			CreatedAt:   last.CreatedAt,
+			ID:          last.ID,
+			Fingerprint: q.fingerprint(),
+		}
+		if q.SortBy == TransactionSortAmount {
+			cursor.Amount = last.Amount
+		}
+		page.NextCursor = cursor.Encode()
+	}
+	return page
+}
+
+// fingerprint identifies the filter and ordering so a cursor cannot be replayed against another query
+func (q *TransactionQuery) fingerprint() string {
+	f := q.Filter
+	parts := []string{string(q.SortBy), string(q.SortDir)}
+	parts = append(parts, optionalUUID(f.AccountID), optionalUUID(f.CounterpartyID))
+	types := make([]string, len(f.Types))
+	for i, transactionType := range f.Types {
+		types[i] = string(transactionType)
+	}
+	statuses := make([]string, len(f.Statuses))
+	for i, status := range f.Statuses {
+		statuses[i] = string(status)
+	}
+	parts = append(parts, strings.Join(types, ","), strings.Join(statuses, ","))
+	parts = append(parts, optionalTime(f.From), optionalTime(f.To))
+	parts = append(parts, optionalAmount(f.MinAmount), optionalAmount(f.MaxAmount), f.Reference)
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Encode returns the opaque string form of the cursor
+func (c *TransactionCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeTransactionCursor parses a cursor produced by Encode
+func DecodeTransactionCursor(value string) (*TransactionCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor TransactionCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+func optionalUUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func optionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func optionalAmount(amount *float64) string {
+	if amount == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", *amount)
+}
@@ -6,7 +6,6 @@ import (
 	"regexp"
 	"strings"
 	"time"
-	"unicode"
 
 	"github.com/google/uuid"
 )
@@ -18,8 +17,13 @@ type User struct {
 	Email        string    `json:"email" gorm:"uniqueIndex;not null;size:100"`
 	PasswordHash string    `json:"-" gorm:"not null;size:255"`
 	Role         UserRole  `json:"role" gorm:"not null;default:'customer'"`
-	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	MFAEnabled   bool      `json:"mfa_enabled" gorm:"not null;default:false"`
+	// EmailVerifiedAt is set once the user proves control of Email; changing Email clears it
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// PasswordChangedAt starts the password's maximum age; nil for accounts created before it was tracked
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// Relationships
 	Balance      *Balance      `json:"balance,omitempty" gorm:"foreignKey:UserID"`
@@ -41,33 +45,37 @@ func (User) TableName() string {
 	return "users"
 }
 
-// UserCreateRequest represents the request to create a new user
+// UserCreateRequest represents the request to create a new user; new users are
+// always customers and other roles are granted through an approved role change
 type UserCreateRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role,omitempty"`
 }
 
 // UserResponse represents the response for user data (without sensitive info)
 type UserResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Role      UserRole  `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            uuid.UUID `json:"id"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email"`
+	Role          UserRole  `json:"role"`
+	MFAEnabled    bool      `json:"mfa_enabled"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 // ToResponse converts User to UserResponse
 func (u *User) ToResponse() *UserResponse {
 	return &UserResponse{
-		ID:        u.ID,
-		Username:  u.Username,
-		Email:     u.Email,
-		Role:      u.Role,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:            u.ID,
+		Username:      u.Username,
+		Email:         u.Email,
+		Role:          u.Role,
+		MFAEnabled:    u.MFAEnabled,
+		EmailVerified: u.IsEmailVerified(),
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
 	}
 }
 
@@ -105,60 +113,22 @@ func (u *User) ValidateEmail() error {
 	return nil
 }
 
-// ValidatePassword validates the password strength
-func ValidatePassword(password string) error {
-	if len(password) < 6 {
-		return errors.New("şifre en az 6 karakter olmalıdır")
-	}
-
-	if len(password) > 128 {
-		return errors.New("şifre en fazla 128 karakter olabilir")
-	}
-
-	var (
-		hasUpper   = false
-		hasLower   = false
-		hasNumber  = false
-		hasSpecial = false
-	)
-
-	for _, char := range password {
-		switch {
-		case unicode.IsUpper(char):
-			hasUpper = true
-		case unicode.IsLower(char):
-			hasLower = true
-		case unicode.IsNumber(char):
-			hasNumber = true
-		case unicode.IsPunct(char) || unicode.IsSymbol(char):
-			hasSpecial = true
-		}
-	}
-
-	if !hasUpper {
-		return errors.New("şifre en az bir büyük harf içermelidir")
-	}
-	if !hasLower {
-		return errors.New("şifre en az bir küçük harf içermelidir")
-	}
-	if !hasNumber {
-		return errors.New("şifre en az bir rakam içermelidir")
-	}
-	if !hasSpecial {
-		return errors.New("şifre en az bir özel karakter içermelidir")
+// IsValidRole checks if the given string is a user role
+func IsValidRole(role string) bool {
+	switch UserRole(role) {
+	case RoleCustomer, RoleAdmin, RoleTeller:
+		return true
+	default:
+		return false
 	}
-
-	return nil
 }
 
 // ValidateRole validates the user role
 func (u *User) ValidateRole() error {
-	switch u.Role {
-	case RoleCustomer, RoleAdmin, RoleTeller:
-		return nil
-	default:
+	if !IsValidRole(string(u.Role)) {
 		return errors.New("geçersiz kullanıcı rolü")
 	}
+	return nil
 }
 
 // Validate validates all user fields
@@ -185,6 +155,11 @@ func (u *User) IsActive() bool {
 	return true
 }
 
+// IsEmailVerified checks if the user verified their current email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
 // CanPerformTransaction checks if user can perform transactions
 func (u *User) CanPerformTransaction() bool {
 	return u.IsActive() && (u.Role == RoleCustomer || u.Role == RoleTeller)
@@ -0,0 +1,235 @@
+package processing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// accountSequencer orders jobs per account. Every job is appended to the FIFO
+// lane of each account it touches and only becomes ready once it is at the head
+// of all of them, so jobs sharing an account run serially in arrival order while
+// jobs on disjoint accounts run in parallel. A transfer therefore waits for both
+// its source and destination accounts. Ready jobs are handed out by a fair scheduler.
+//
+// Ordering holds within one process; with a shared durable queue, other
+// instances may pick up jobs for the same account.
+type accountSequencer struct {
+	mu      sync.Mutex
+	lanes   map[uuid.UUID][]*sequencedJob
+	jobs    map[uuid.UUID]*sequencedJob
+	ready   *fairScheduler
+	waiting map[models.JobPriority]int // held jobs not yet handed to a worker
+	limit   int
+	changed chan struct{} // closed and replaced whenever ready or capacity changes
+}
+
+// sequencedJob tracks a job held by the sequencer
+type sequencedJob struct {
+	job       *TransactionJob
+	accounts  []uuid.UUID
+	scheduled bool // handed to the ready list, running or waiting for a retry
+}
+
+// newAccountSequencer creates a sequencer holding at most limit jobs
+func newAccountSequencer(limit int, weights map[models.JobPriority]int) *accountSequencer {
+	return &accountSequencer{
+		lanes:   make(map[uuid.UUID][]*sequencedJob),
+		jobs:    make(map[uuid.UUID]*sequencedJob),
+		ready:   newFairScheduler(weights),
+		waiting: make(map[models.JobPriority]int),
+		limit:   limit,
+		changed: make(chan struct{}),
+	}
+}
+
+// jobAccounts returns the distinct accounts a job touches
+func jobAccounts(job *TransactionJob) []uuid.UUID {
+	var accounts []uuid.UUID
+	if job.FromAccountID != uuid.Nil {
+		accounts = append(accounts, job.FromAccountID)
+	}
+	if job.ToAccountID != uuid.Nil && job.ToAccountID != job.FromAccountID {
+		accounts = append(accounts, job.ToAccountID)
+	}
+	return accounts
+}
+
+// Add places a job in its account lanes. It returns false if the job is already held,
+// which happens when a durable queue redelivers a job whose lease expired.
+func (s *accountSequencer) Add(job *TransactionJob) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.ID]; exists {
+		return false
+	}
+
+	entry := &sequencedJob{job: job, accounts: jobAccounts(job)}
+	s.jobs[job.ID] = entry
+	s.waiting[normalizePriority(job.Priority)]++
+	for _, account := range entry.accounts {
+		s.lanes[account] = append(s.lanes[account], entry)
+	}
+
+	if s.isHead(entry) {
+		s.schedule(entry)
+	}
+	return true
+}
+
+// Next blocks until a job is ready to run or the context is done
+func (s *accountSequencer) Next(ctx context.Context) (*TransactionJob, error) {
+	for {
+		s.mu.Lock()
+		if job, ok := s.ready.Pop(); ok {
+			s.waiting[normalizePriority(job.Priority)]--
+			s.mu.Unlock()
+			return job, nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Requeue makes a job waiting for a retry ready again. It keeps its place at
+// the head of its lanes, so later jobs on the same accounts stay blocked.
+func (s *accountSequencer) Requeue(job *TransactionJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.jobs[job.ID]
+	if !exists {
+		return
+	}
+	entry.job = job
+	job.readyAt = time.Now()
+	s.waiting[normalizePriority(job.Priority)]++
+	s.ready.Push(job)
+	s.notify()
+}
+
+// Done releases a settled job and schedules the jobs it was blocking
+func (s *accountSequencer) Done(job *TransactionJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.jobs[job.ID]
+	if !exists {
+		return
+	}
+	delete(s.jobs, job.ID)
+
+	for _, account := range entry.accounts {
+		lane := s.lanes[account]
+		for i, queued := range lane {
+			if queued == entry {
+				lane = append(lane[:i], lane[i+1:]...)
+				break
+			}
+		}
+		if len(lane) == 0 {
+			delete(s.lanes, account)
+			continue
+		}
+		s.lanes[account] = lane
+
+		if head := lane[0]; !head.scheduled && s.isHead(head) {
+			s.schedule(head)
+		}
+	}
+
+	s.notify()
+}
+
+// WaitForCapacity blocks until the sequencer can accept another job or the context is done
+func (s *accountSequencer) WaitForCapacity(ctx context.Context) error {
+	for {
+		s.mu.Lock()
+		if len(s.jobs) < s.limit {
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Stats returns the number of held jobs, ready jobs and accounts with pending work
+func (s *accountSequencer) Stats() (held, ready, accounts int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.jobs), s.ready.Len(), len(s.lanes)
+}
+
+// HeldIDs returns the IDs of the held jobs, including running ones
+func (s *accountSequencer) HeldIDs() []uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]uuid.UUID, 0, len(s.jobs))
+	for id := range s.jobs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Waiting returns the number of held jobs not yet handed to a worker
+func (s *accountSequencer) Waiting() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	for _, count := range s.waiting {
+		total += count
+	}
+	return total
+}
+
+// WaitingByPriority returns the number of held jobs not yet handed to a worker per priority
+func (s *accountSequencer) WaitingByPriority() map[models.JobPriority]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	waiting := make(map[models.JobPriority]int, len(s.waiting))
+	for priority, count := range s.waiting {
+		waiting[priority] = count
+	}
+	return waiting
+}
+
+// isHead reports whether the entry is first in all of its lanes. Must be called with mu held.
+func (s *accountSequencer) isHead(entry *sequencedJob) bool {
+	for _, account := range entry.accounts {
+		if lane := s.lanes[account]; len(lane) == 0 || lane[0] != entry {
+			return false
+		}
+	}
+	return true
+}
+
+// schedule moves an entry to the ready list. Must be called with mu held.
+func (s *accountSequencer) schedule(entry *sequencedJob) {
+	entry.scheduled = true
+	entry.job.readyAt = time.Now()
+	s.ready.Push(entry.job)
+	s.notify()
+}
+
+// notify wakes up goroutines waiting for a change. Must be called with mu held.
+func (s *accountSequencer) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
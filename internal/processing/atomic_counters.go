@@ -130,7 +130,7 @@ func (tc *TransactionCounters) RecordProcessingTime(duration time.Duration) {
 		total := atomic.LoadInt64(&tc.successfulTransactions)
 		if total == 0 {
 			newAverage := durationNs
-			if atomic.CompareAndSwapInt64(&tc.averageProcessingTime, 0, newAverage) {
+			if atomic.CompareAndSwapInt64(&tc.averageProcessingTime, current, newAverage) {
 				break
 			}
 		} else {
@@ -272,11 +272,14 @@ func (tc *TransactionCounters) RecordTransaction(transaction *models.Transaction
 	// Increment total transactions
 	tc.IncrementTotalTransactions()
 
-	// Increment transaction type
-	tc.IncrementTransactionType(transaction.Type)
+	// Failed jobs have no transaction to break down
+	if transaction != nil {
+		// Increment transaction type
+		tc.IncrementTransactionType(transaction.Type)
 
-	// Record amount
-	tc.AddAmountProcessed(transaction.Amount)
+		// Record amount
+		tc.AddAmountProcessed(transaction.Amount)
+	}
 
 	// Record processing time
 	tc.RecordProcessingTime(processingTime)
@@ -0,0 +1,244 @@
+package processing
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PoolConfig holds worker pool sizing, autoscaling and backpressure settings
+type PoolConfig struct {
+	MinWorkers        int           // workers kept running at all times
+	MaxWorkers        int           // upper bound for autoscaling; equal to MinWorkers disables it
+	MaxPendingJobs    int           // waiting jobs at which submissions are rejected, 0 means no limit
+	ScaleUpQueueDepth int           // waiting jobs per worker that trigger a scale up
+	ScaleUpLatency    time.Duration // queue wait that triggers a scale up
+	ScaleDownAfter    time.Duration // idle time before workers are removed
+	ScaleInterval     time.Duration // how often load is measured
+}
+
+// DefaultPoolConfig returns default worker pool configuration
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MinWorkers:        2,
+		MaxWorkers:        20,
+		MaxPendingJobs:    1000,
+		ScaleUpQueueDepth: 5,
+		ScaleUpLatency:    2 * time.Second,
+		ScaleDownAfter:    30 * time.Second,
+		ScaleInterval:     time.Second,
+	}
+}
+
+// normalize fills in missing or inconsistent values
+func (c PoolConfig) normalize() PoolConfig {
+	defaults := DefaultPoolConfig()
+	if c.MinWorkers < 1 {
+		c.MinWorkers = 1
+	}
+	if c.MaxWorkers < c.MinWorkers {
+		c.MaxWorkers = c.MinWorkers
+	}
+	if c.ScaleUpQueueDepth < 1 {
+		c.ScaleUpQueueDepth = defaults.ScaleUpQueueDepth
+	}
+	if c.ScaleUpLatency <= 0 {
+		c.ScaleUpLatency = defaults.ScaleUpLatency
+	}
+	if c.ScaleDownAfter <= 0 {
+		c.ScaleDownAfter = defaults.ScaleDownAfter
+	}
+	if c.ScaleInterval <= 0 {
+		c.ScaleInterval = defaults.ScaleInterval
+	}
+	return c
+}
+
+// autoscaling reports whether the pool may change its size on its own
+func (c PoolConfig) autoscaling() bool {
+	return c.MaxWorkers > c.MinWorkers
+}
+
+// WorkerCount returns the current number of workers
+func (wp *WorkerPool) WorkerCount() int {
+	wp.workersMu.Lock()
+	defer wp.workersMu.Unlock()
+	return len(wp.workers)
+}
+
+// Resize sets the number of workers. Removed workers finish their current job first.
+// n must stay within the pool's MinWorkers and MaxWorkers.
+func (wp *WorkerPool) Resize(n int) error {
+	if n < wp.config.MinWorkers || n > wp.config.MaxWorkers {
+		return fmt.Errorf("worker sayısı %d ile %d arasında olmalıdır", wp.config.MinWorkers, wp.config.MaxWorkers)
+	}
+
+	wp.workersMu.Lock()
+	defer wp.workersMu.Unlock()
+
+	if wp.ctx.Err() != nil {
+		return ErrQueueClosed
+	}
+
+	current := len(wp.workers)
+	switch {
+	case n > current:
+		wp.addWorkers(n - current)
+	case n < current:
+		for _, worker := range wp.workers[n:] {
+			worker.Stop()
+		}
+		wp.workers = wp.workers[:n]
+	default:
+		return nil
+	}
+
+	wp.logger.Info("Worker pool yeniden boyutlandırıldı",
+		zap.Int("previous_workers", current),
+		zap.Int("worker_count", n))
+	return nil
+}
+
+// addWorkers starts count new workers. Must be called with workersMu held.
+func (wp *WorkerPool) addWorkers(count int) {
+	for i := 0; i < count; i++ {
+		wp.nextWorkerID++
+		workerCtx, workerCancel := context.WithCancel(wp.ctx)
+		worker := &Worker{
+			id:     wp.nextWorkerID,
+			pool:   wp,
+			ctx:    workerCtx,
+			cancel: workerCancel,
+			logger: wp.logger.With(zap.Int("worker_id", wp.nextWorkerID)),
+		}
+		wp.workers = append(wp.workers, worker)
+		wp.wg.Add(1)
+		go worker.start()
+	}
+}
+
+// IsSaturated reports whether new jobs should be rejected until the backlog drains
+func (wp *WorkerPool) IsSaturated() bool {
+	if capacity := wp.queue.Capacity(); capacity > 0 && wp.queue.Len() >= capacity {
+		return true
+	}
+	return wp.config.MaxPendingJobs > 0 && atomic.LoadInt64(&wp.pendingJobs) >= int64(wp.config.MaxPendingJobs)
+}
+
+// SuggestedRetryAfter estimates how long a rejected client should wait before retrying
+func (wp *WorkerPool) SuggestedRetryAfter() time.Duration {
+	pending := atomic.LoadInt64(&wp.pendingJobs)
+	if capacity := wp.queue.Capacity(); capacity > 0 && int64(wp.queue.Len()) > pending {
+		pending = int64(wp.queue.Len())
+	}
+	averageNs := atomic.LoadInt64(&wp.counters.averageProcessingTime)
+	workers := int64(wp.WorkerCount())
+	if workers == 0 {
+		workers = 1
+	}
+
+	retryAfter := time.Duration(pending * averageNs / workers)
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	if retryAfter > time.Minute {
+		retryAfter = time.Minute
+	}
+	return retryAfter
+}
+
+// recordQueueWait updates the moving average of the time jobs wait for a worker
+func (wp *WorkerPool) recordQueueWait(wait time.Duration) {
+	for {
+		current := atomic.LoadInt64(&wp.queueWaitNs)
+		updated := current + (wait.Nanoseconds()-current)/5
+		if atomic.CompareAndSwapInt64(&wp.queueWaitNs, current, updated) {
+			return
+		}
+	}
+}
+
+// measurePendingJobs counts the jobs waiting in the queue and in the pool
+func (wp *WorkerPool) measurePendingJobs() int64 {
+	pending := wp.sequencer.Waiting()
+	for _, count := range wp.queue.LenByPriority() {
+		pending += count
+	}
+	atomic.StoreInt64(&wp.pendingJobs, int64(pending))
+	return int64(pending)
+}
+
+// autoscaler decides the worker count from the measured load
+type autoscaler struct {
+	config   PoolConfig
+	lastBusy time.Time // last measurement with waiting jobs
+}
+
+// newAutoscaler creates an autoscaler that considers the pool busy at now
+func newAutoscaler(config PoolConfig, now time.Time) *autoscaler {
+	return &autoscaler{config: config, lastBusy: now}
+}
+
+// target returns the worker count for the load measured at now
+func (a *autoscaler) target(now time.Time, workers int, pending int64, queueWait time.Duration) int {
+	if pending > 0 {
+		a.lastBusy = now
+	}
+
+	switch {
+	case pending > int64(workers*a.config.ScaleUpQueueDepth) || (pending > 0 && queueWait > a.config.ScaleUpLatency):
+		// Enough workers to bring the backlog below the per-worker threshold, at least one more
+		target := int((pending + int64(a.config.ScaleUpQueueDepth) - 1) / int64(a.config.ScaleUpQueueDepth))
+		if target <= workers {
+			target = workers + 1
+		}
+		if target > a.config.MaxWorkers {
+			target = a.config.MaxWorkers
+		}
+		return target
+	case pending == 0 && now.Sub(a.lastBusy) >= a.config.ScaleDownAfter:
+		// Shed one idle worker per interval
+		if workers-1 < a.config.MinWorkers {
+			return a.config.MinWorkers
+		}
+		return workers - 1
+	default:
+		return workers
+	}
+}
+
+// monitor periodically measures load and scales the pool
+func (wp *WorkerPool) monitor() {
+	ticker := time.NewTicker(wp.config.ScaleInterval)
+	defer ticker.Stop()
+
+	scaler := newAutoscaler(wp.config, time.Now())
+	for {
+		select {
+		case <-wp.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pending := wp.measurePendingJobs()
+		if !wp.config.autoscaling() {
+			continue
+		}
+
+		workers := wp.WorkerCount()
+		queueWait := time.Duration(atomic.LoadInt64(&wp.queueWaitNs))
+		target := scaler.target(time.Now(), workers, pending, queueWait)
+		if target != workers {
+			wp.logger.Debug("Worker pool otomatik ölçekleniyor",
+				zap.Int64("pending_jobs", pending),
+				zap.Duration("queue_wait", queueWait),
+				zap.Int("target_workers", target))
+			if err := wp.Resize(target); err != nil {
+				wp.logger.Warn("Worker pool ölçeklenemedi", zap.Error(err))
+			}
+		}
+	}
+}
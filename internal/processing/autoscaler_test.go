@@ -0,0 +1,211 @@
+package processing
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func testScalingConfig() PoolConfig {
+	return PoolConfig{
+		MinWorkers:        2,
+		MaxWorkers:        10,
+		ScaleUpQueueDepth: 5,
+		ScaleUpLatency:    2 * time.Second,
+		ScaleDownAfter:    30 * time.Second,
+		ScaleInterval:     time.Second,
+	}
+}
+
+func TestAutoscalerScalesUp(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		name      string
+		workers   int
+		pending   int64
+		queueWait time.Duration
+		want      int
+	}{
+		{"backlog within threshold", 2, 10, 0, 2},
+		{"backlog above threshold", 2, 23, 0, 5},
+		{"capped at max workers", 2, 200, 0, 10},
+		{"slow queue adds one worker", 4, 3, 3 * time.Second, 5},
+		{"slow but empty queue", 4, 0, 3 * time.Second, 4},
+		{"already at max", 10, 500, time.Minute, 10},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			scaler := newAutoscaler(testScalingConfig(), now)
+			if got := scaler.target(now, tc.workers, tc.pending, tc.queueWait); got != tc.want {
+				t.Errorf("expected %d workers, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAutoscalerScalesDownAfterCooldown(t *testing.T) {
+	start := time.Now()
+	scaler := newAutoscaler(testScalingConfig(), start)
+
+	if got := scaler.target(start.Add(29*time.Second), 5, 0, 0); got != 5 {
+		t.Fatalf("scaled down to %d before the cooldown passed", got)
+	}
+
+	// One worker is shed per measurement until the minimum is reached
+	workers := 5
+	for i, want := range []int{4, 3, 2, 2} {
+		workers = scaler.target(start.Add(time.Duration(30+i)*time.Second), workers, 0, 0)
+		if workers != want {
+			t.Fatalf("step %d: expected %d workers, got %d", i, want, workers)
+		}
+	}
+
+	// Waiting jobs restart the cooldown
+	busy := start.Add(time.Minute)
+	if got := scaler.target(busy, 4, 1, 0); got != 4 {
+		t.Fatalf("expected 4 workers while busy, got %d", got)
+	}
+	if got := scaler.target(busy.Add(29*time.Second), 4, 0, 0); got != 4 {
+		t.Errorf("scaled down to %d before the restarted cooldown passed", got)
+	}
+	if got := scaler.target(busy.Add(30*time.Second), 4, 0, 0); got != 3 {
+		t.Errorf("expected 3 workers after the restarted cooldown, got %d", got)
+	}
+}
+
+// depthQueue is a memory queue reporting a settable depth to the autoscaler
+type depthQueue struct {
+	*MemoryJobQueue
+	mu    sync.Mutex
+	depth int
+}
+
+func (q *depthQueue) setDepth(depth int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.depth = depth
+}
+
+func (q *depthQueue) LenByPriority() map[models.JobPriority]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return map[models.JobPriority]int{models.JobPriorityNormal: q.depth}
+}
+
+// waitForWorkers waits until the pool runs the given number of workers
+func waitForWorkers(t *testing.T, pool *WorkerPool, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.WorkerCount() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d workers, got %d", want, pool.WorkerCount())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWorkerPoolAutoscalesWithQueueDepth(t *testing.T) {
+	queue := &depthQueue{MemoryJobQueue: NewMemoryJobQueue(10, zap.NewNop())}
+	pool := NewAutoscalingWorkerPool(PoolConfig{
+		MinWorkers:        1,
+		MaxWorkers:        4,
+		ScaleUpQueueDepth: 5,
+		ScaleDownAfter:    20 * time.Millisecond,
+		ScaleInterval:     5 * time.Millisecond,
+	}, queue, zap.NewNop())
+	defer pool.Shutdown(5 * time.Second)
+
+	queue.setDepth(100)
+	waitForWorkers(t, pool, 4)
+
+	queue.setDepth(0)
+	waitForWorkers(t, pool, 1)
+}
+
+func TestWorkerPoolResizeDrainsRemovedWorkers(t *testing.T) {
+	release := make(chan struct{})
+	var running sync.WaitGroup
+	running.Add(3)
+
+	service := &funcTransactionService{
+		credit: func(ctx context.Context) error {
+			running.Done()
+			<-release
+			return nil
+		},
+		debit: func(ctx context.Context) error { return nil },
+	}
+
+	// A long scale interval keeps the autoscaler out of the way
+	pool := NewAutoscalingWorkerPool(PoolConfig{MinWorkers: 1, MaxWorkers: 3, ScaleInterval: time.Hour},
+		NewMemoryJobQueue(10, zap.NewNop()), zap.NewNop())
+	defer pool.Shutdown(5 * time.Second)
+
+	listener := &completionListener{done: make(chan uuid.UUID, 4)}
+	pool.AddListener(listener)
+
+	if err := pool.Resize(0); err == nil {
+		t.Errorf("expected an error below MinWorkers")
+	}
+	if err := pool.Resize(4); err == nil {
+		t.Errorf("expected an error above MaxWorkers")
+	}
+	if err := pool.Resize(3); err != nil {
+		t.Fatalf("resize up: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		job := &TransactionJob{ID: uuid.New(), TransactionType: "credit", ToAccountID: uuid.New(), Amount: 1,
+			TransactionService: service, AuditService: &MockAuditService{}}
+		if err := pool.SubmitJob(job); err != nil {
+			t.Fatalf("submit job: %v", err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("jobs did not start on three workers")
+	}
+
+	// Shrinking stops the removed workers only after their current job
+	if err := pool.Resize(1); err != nil {
+		t.Fatalf("resize down: %v", err)
+	}
+	if got := pool.WorkerCount(); got != 1 {
+		t.Errorf("expected 1 worker after shrinking, got %d", got)
+	}
+
+	close(release)
+	for i := 0; i < 3; i++ {
+		select {
+		case <-listener.done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("running jobs were not finished after shrinking")
+		}
+	}
+
+	// The remaining worker keeps processing
+	debit := &TransactionJob{ID: uuid.New(), TransactionType: "debit", FromAccountID: uuid.New(), Amount: 1,
+		TransactionService: service, AuditService: &MockAuditService{}}
+	if err := pool.SubmitJob(debit); err != nil {
+		t.Fatalf("submit job: %v", err)
+	}
+	select {
+	case <-listener.done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("job did not complete after shrinking")
+	}
+}
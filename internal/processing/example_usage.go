@@ -155,12 +155,12 @@ func performHealthCheck(workerPool *WorkerPool, logger *zap.Logger) {
 	logger.Info("Sağlık kontrolü yapılıyor...")
 
 	// Simple health check for worker pool
-	queueLength := len(workerPool.jobQueue)
-	queueCapacity := cap(workerPool.jobQueue)
-	workerCount := len(workerPool.workers)
+	queueLength := workerPool.queue.Len()
+	queueCapacity := workerPool.queue.Capacity()
+	workerCount := workerPool.WorkerCount()
 
 	status := "healthy"
-	if queueLength >= queueCapacity*9/10 { // 90% full
+	if queueCapacity > 0 && queueLength >= queueCapacity*9/10 { // 90% full
 		status = "warning"
 	}
 	if workerCount == 0 {
@@ -186,22 +186,31 @@ func performHealthCheck(workerPool *WorkerPool, logger *zap.Logger) {
 
 type MockTransactionService struct{}
 
-func (m *MockTransactionService) Credit(ctx context.Context, accountID uuid.UUID, amount float64) error {
+func (m *MockTransactionService) Credit(ctx context.Context, accountID uuid.UUID, amount float64, reference string) (*models.Transaction, error) {
 	// Simulate processing time
 	time.Sleep(100 * time.Millisecond)
-	return nil
+	return &models.Transaction{ID: uuid.New(), ToUserID: &accountID, Amount: amount, Reference: reference,
+		Type: models.TransactionTypeDeposit, Status: models.TransactionStatusCompleted}, nil
 }
 
-func (m *MockTransactionService) Debit(ctx context.Context, accountID uuid.UUID, amount float64) error {
+func (m *MockTransactionService) Debit(ctx context.Context, accountID uuid.UUID, amount float64, reference string) (*models.Transaction, error) {
 	// Simulate processing time
 	time.Sleep(100 * time.Millisecond)
-	return nil
+	return &models.Transaction{ID: uuid.New(), FromUserID: &accountID, Amount: amount, Reference: reference,
+		Type: models.TransactionTypeWithdraw, Status: models.TransactionStatusCompleted}, nil
 }
 
-func (m *MockTransactionService) Transfer(ctx context.Context, fromAccountID, toAccountID uuid.UUID, amount float64) error {
+func (m *MockTransactionService) Transfer(ctx context.Context, fromAccountID, toAccountID uuid.UUID, amount float64, reference string) (*models.Transaction, error) {
 	// Simulate processing time
 	time.Sleep(100 * time.Millisecond)
-	return nil
+	return &models.Transaction{ID: uuid.New(), FromUserID: &fromAccountID, ToUserID: &toAccountID, Amount: amount, Reference: reference,
+		Type: models.TransactionTypeTransfer, Status: models.TransactionStatusCompleted}, nil
+}
+
+func (m *MockTransactionService) Refund(ctx context.Context, transactionID uuid.UUID) (*models.Transaction, error) {
+	// Simulate processing time
+	time.Sleep(100 * time.Millisecond)
+	return &models.Transaction{ID: uuid.New(), Type: models.TransactionTypeRefund, Status: models.TransactionStatusCompleted}, nil
 }
 
 type MockBalanceService struct{}
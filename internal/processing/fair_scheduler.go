@@ -0,0 +1,119 @@
+package processing
+
+import (
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// DefaultPriorityWeights returns how many jobs each priority class may start per
+// scheduling round. Lower classes always get their share, so they are never starved.
+func DefaultPriorityWeights() map[models.JobPriority]int {
+	return map[models.JobPriority]int{
+		models.JobPriorityHigh:   4,
+		models.JobPriorityBatch:  2,
+		models.JobPriorityNormal: 2,
+	}
+}
+
+// fairScheduler orders ready jobs with weighted round robin across priority
+// lanes and plain round robin across users within a lane, so one user's bulk
+// submission only delays that user's own jobs.
+type fairScheduler struct {
+	lanes   map[models.JobPriority]*priorityLane
+	weights map[models.JobPriority]int
+	current int // index into models.JobPriorities
+	credit  int // jobs the current lane may still start this round
+	size    int
+}
+
+// priorityLane holds the ready jobs of one priority class, queued per user
+type priorityLane struct {
+	users map[uuid.UUID][]*TransactionJob
+	ring  []uuid.UUID // users with queued jobs, in round robin order
+	next  int
+	size  int
+}
+
+// newFairScheduler creates a scheduler with the given lane weights
+func newFairScheduler(weights map[models.JobPriority]int) *fairScheduler {
+	s := &fairScheduler{
+		lanes:   make(map[models.JobPriority]*priorityLane),
+		weights: make(map[models.JobPriority]int),
+	}
+	for _, priority := range models.JobPriorities {
+		s.lanes[priority] = &priorityLane{users: make(map[uuid.UUID][]*TransactionJob)}
+		s.weights[priority] = 1
+		if weight, ok := weights[priority]; ok && weight > 0 {
+			s.weights[priority] = weight
+		}
+	}
+	s.credit = s.weights[models.JobPriorities[0]]
+	return s
+}
+
+// normalizePriority maps unknown or empty priorities to the normal class
+func normalizePriority(priority models.JobPriority) models.JobPriority {
+	if models.IsValidJobPriority(string(priority)) {
+		return priority
+	}
+	return models.JobPriorityNormal
+}
+
+// Push adds a ready job to its lane
+func (s *fairScheduler) Push(job *TransactionJob) {
+	lane := s.lanes[normalizePriority(job.Priority)]
+	if _, exists := lane.users[job.UserID]; !exists {
+		lane.ring = append(lane.ring, job.UserID)
+	}
+	lane.users[job.UserID] = append(lane.users[job.UserID], job)
+	lane.size++
+	s.size++
+}
+
+// Pop returns the next job to run
+func (s *fairScheduler) Pop() (*TransactionJob, bool) {
+	if s.size == 0 {
+		return nil, false
+	}
+
+	for {
+		priority := models.JobPriorities[s.current]
+		if lane := s.lanes[priority]; s.credit > 0 && lane.size > 0 {
+			s.credit--
+			s.size--
+			return lane.pop(), true
+		}
+
+		// Lane used up its share or is empty, move on to the next one
+		s.current = (s.current + 1) % len(models.JobPriorities)
+		s.credit = s.weights[models.JobPriorities[s.current]]
+	}
+}
+
+// Len returns the number of ready jobs
+func (s *fairScheduler) Len() int {
+	return s.size
+}
+
+// pop takes the oldest job of the next user in round robin order
+func (l *priorityLane) pop() *TransactionJob {
+	if l.next >= len(l.ring) {
+		l.next = 0
+	}
+	user := l.ring[l.next]
+	queue := l.users[user]
+	job := queue[0]
+	queue[0] = nil
+	l.size--
+
+	if len(queue) == 1 {
+		// User has nothing left, the following user moves into this slot
+		delete(l.users, user)
+		l.ring = append(l.ring[:l.next], l.ring[l.next+1:]...)
+		return job
+	}
+
+	l.users[user] = queue[1:]
+	l.next++
+	return job
+}
@@ -0,0 +1,129 @@
+package processing
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func schedulerJob(user uuid.UUID, priority models.JobPriority) *TransactionJob {
+	return &TransactionJob{ID: uuid.New(), UserID: user, Priority: priority}
+}
+
+// popAll drains the scheduler and returns the jobs in the order they were handed out
+func popAll(t *testing.T, s *fairScheduler) []*TransactionJob {
+	t.Helper()
+	var jobs []*TransactionJob
+	for s.Len() > 0 {
+		job, ok := s.Pop()
+		if !ok {
+			t.Fatalf("pop failed with %d jobs left", s.Len())
+		}
+		jobs = append(jobs, job)
+	}
+	if _, ok := s.Pop(); ok {
+		t.Fatalf("pop succeeded on an empty scheduler")
+	}
+	return jobs
+}
+
+func TestFairSchedulerWeightsPriorityLanes(t *testing.T) {
+	s := newFairScheduler(DefaultPriorityWeights())
+	user := uuid.New()
+	for i := 0; i < 8; i++ {
+		s.Push(schedulerJob(user, models.JobPriorityHigh))
+		s.Push(schedulerJob(user, models.JobPriorityBatch))
+		s.Push(schedulerJob(user, models.JobPriorityNormal))
+	}
+
+	var order []models.JobPriority
+	for _, job := range popAll(t, s)[:16] {
+		order = append(order, job.Priority)
+	}
+
+	// Two full rounds of 4 high, 2 batch, 2 normal
+	round := []models.JobPriority{
+		models.JobPriorityHigh, models.JobPriorityHigh, models.JobPriorityHigh, models.JobPriorityHigh,
+		models.JobPriorityBatch, models.JobPriorityBatch,
+		models.JobPriorityNormal, models.JobPriorityNormal,
+	}
+	expected := append(append([]models.JobPriority(nil), round...), round...)
+	if fmt.Sprint(order) != fmt.Sprint(expected) {
+		t.Errorf("expected order %v, got %v", expected, order)
+	}
+}
+
+func TestFairSchedulerDoesNotStarveLowerLanes(t *testing.T) {
+	s := newFairScheduler(DefaultPriorityWeights())
+	user := uuid.New()
+	for i := 0; i < 100; i++ {
+		s.Push(schedulerJob(user, models.JobPriorityHigh))
+	}
+	normal := schedulerJob(user, models.JobPriorityNormal)
+	s.Push(normal)
+
+	for i, job := range popAll(t, s) {
+		if job == normal {
+			if i >= 8 {
+				t.Errorf("normal job ran at position %d behind the high priority backlog", i)
+			}
+			return
+		}
+	}
+	t.Fatalf("normal job never ran")
+}
+
+func TestFairSchedulerRoundRobinAcrossUsers(t *testing.T) {
+	s := newFairScheduler(DefaultPriorityWeights())
+	bulk, other := uuid.New(), uuid.New()
+	for i := 0; i < 50; i++ {
+		s.Push(schedulerJob(bulk, models.JobPriorityNormal))
+	}
+	first := schedulerJob(other, models.JobPriorityNormal)
+	second := schedulerJob(other, models.JobPriorityNormal)
+	s.Push(first)
+	s.Push(second)
+
+	jobs := popAll(t, s)
+	if len(jobs) != 52 {
+		t.Fatalf("expected 52 jobs, got %d", len(jobs))
+	}
+	// The other user's jobs alternate with the bulk submission instead of waiting behind it
+	if jobs[1] != first || jobs[3] != second {
+		t.Errorf("other user's jobs were not interleaved with the bulk submission")
+	}
+}
+
+func TestFairSchedulerKeepsOrderPerUser(t *testing.T) {
+	s := newFairScheduler(DefaultPriorityWeights())
+	users := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	sequence := make(map[uuid.UUID]int)
+	for i := 0; i < 30; i++ {
+		job := schedulerJob(users[i%len(users)], models.JobPriorityBatch)
+		sequence[job.ID] = i
+		s.Push(job)
+	}
+
+	last := make(map[uuid.UUID]int)
+	for _, job := range popAll(t, s) {
+		if previous, ok := last[job.UserID]; ok && sequence[job.ID] < previous {
+			t.Errorf("user %s ran job %d after job %d", job.UserID, sequence[job.ID], previous)
+		}
+		last[job.UserID] = sequence[job.ID]
+	}
+}
+
+func TestFairSchedulerNormalizesUnknownPriority(t *testing.T) {
+	s := newFairScheduler(nil)
+	s.Push(schedulerJob(uuid.New(), ""))
+	s.Push(schedulerJob(uuid.New(), "urgent"))
+
+	if got := s.lanes[models.JobPriorityNormal].size; got != 2 {
+		t.Errorf("expected both jobs in the normal lane, got %d", got)
+	}
+	if got := len(popAll(t, s)); got != 2 {
+		t.Errorf("expected 2 jobs, got %d", got)
+	}
+}
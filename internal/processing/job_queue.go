@@ -0,0 +1,272 @@
+package processing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrQueueFull is returned when a bounded queue cannot accept more jobs
+	ErrQueueFull = errors.New("iş kuyruğu dolu")
+	// ErrQueueClosed is returned when the queue no longer accepts jobs
+	ErrQueueClosed = errors.New("iş kuyruğu kapatılmış")
+)
+
+// JobQueue is the storage backing the worker pool's pending jobs.
+// Dequeue leases a job to the pool; the pool must then Ack, Retry or
+// DeadLetter it. Retry records a failed attempt while the pool keeps the job
+// and runs it again after the delay, so its lease must outlive the delay.
+// While the pool holds leased jobs it renews them at least three times per
+// LeaseTimeout, and it calls Started right before each execution.
+// Durable implementations redeliver leased jobs that are never settled
+// (e.g. after a crash).
+type JobQueue interface {
+	Enqueue(ctx context.Context, job *TransactionJob) error
+	Dequeue(ctx context.Context) (*TransactionJob, error)
+	Started(ctx context.Context, job *TransactionJob) error
+	Renew(ctx context.Context, jobIDs []uuid.UUID) error
+	Ack(ctx context.Context, job *TransactionJob) error
+	Retry(ctx context.Context, job *TransactionJob, cause error, delay time.Duration) error
+	DeadLetter(ctx context.Context, job *TransactionJob, cause error) error
+	LeaseTimeout() time.Duration // 0 means leases never expire
+	Len() int
+	LenByPriority() map[models.JobPriority]int
+	Capacity() int // 0 means unbounded
+	Durable() bool
+	Close() error
+}
+
+// TransactionalJobQueue is a queue stored in the application database. EnqueueTx
+// writes the job with the caller's transaction, so the job is committed or rolled
+// back together with the caller's rows.
+type TransactionalJobQueue interface {
+	JobQueue
+	EnqueueTx(tx *gorm.DB, job *TransactionJob) error
+}
+
+// JobServices holds the services attached to jobs restored from durable storage
+type JobServices struct {
+	TransactionService interfaces.TransactionService
+	BalanceService     interfaces.BalanceService
+	AuditService       interfaces.AuditService
+}
+
+// bind attaches the services to a job
+func (s JobServices) bind(job *TransactionJob) {
+	job.TransactionService = s.TransactionService
+	job.BalanceService = s.BalanceService
+	job.AuditService = s.AuditService
+}
+
+// NewDeadLetterJob builds the dead-letter record of a job
+func NewDeadLetterJob(job *TransactionJob, cause error) *models.DeadLetterJob {
+	reason := ""
+	if cause != nil {
+		reason = cause.Error()
+	}
+	return &models.DeadLetterJob{
+		JobID:               job.ID,
+		TransactionType:     job.TransactionType,
+		UserID:              optionalUUID(job.UserID),
+		Priority:            normalizePriority(job.Priority),
+		FromAccountID:       optionalUUID(job.FromAccountID),
+		ToAccountID:         optionalUUID(job.ToAccountID),
+		Amount:              job.Amount,
+		SourceTransactionID: optionalUUID(job.SourceTransactionID),
+		Reference:           job.Reference,
+		RetryCount:          job.RetryCount,
+		MaxRetries:          job.MaxRetries,
+		Reason:              reason,
+		ErrorChain:          models.EncodeErrorChain(job.ErrorChain),
+		Status:              models.DeadLetterStatusPending,
+		FailedAt:            time.Now(),
+	}
+}
+
+// JobFromDeadLetter rebuilds a fresh worker pool job from a dead-letter record for replay
+func JobFromDeadLetter(deadLetter *models.DeadLetterJob, services JobServices) *TransactionJob {
+	job := &TransactionJob{
+		ID:              deadLetter.JobID,
+		TransactionType: deadLetter.TransactionType,
+		Priority:        deadLetter.Priority,
+		Amount:          deadLetter.Amount,
+		Reference:       deadLetter.Reference,
+		MaxRetries:      deadLetter.MaxRetries,
+		CreatedAt:       time.Now(),
+	}
+	if deadLetter.UserID != nil {
+		job.UserID = *deadLetter.UserID
+	}
+	if deadLetter.FromAccountID != nil {
+		job.FromAccountID = *deadLetter.FromAccountID
+	}
+	if deadLetter.ToAccountID != nil {
+		job.ToAccountID = *deadLetter.ToAccountID
+	}
+	if deadLetter.SourceTransactionID != nil {
+		job.SourceTransactionID = *deadLetter.SourceTransactionID
+	}
+	services.bind(job)
+	return job
+}
+
+// jobIDContextKey is the context key carrying the ID of the job being processed
+type jobIDContextKey struct{}
+
+// WithJobID returns a context carrying the given job ID
+func WithJobID(ctx context.Context, jobID uuid.UUID) context.Context {
+	return context.WithValue(ctx, jobIDContextKey{}, jobID)
+}
+
+// JobIDFromContext returns the job ID carried by the context, if any
+func JobIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	jobID, ok := ctx.Value(jobIDContextKey{}).(uuid.UUID)
+	return jobID, ok
+}
+
+// MemoryJobQueue is a bounded, non-durable queue backed by a channel.
+// Dead-lettered jobs are kept in memory unless a dead-letter repository is set.
+type MemoryJobQueue struct {
+	jobs           chan *TransactionJob
+	closed         chan struct{}
+	closeOnce      sync.Once
+	deadLetters    []*TransactionJob
+	deadLetterRepo interfaces.DeadLetterRepository
+	depth          map[models.JobPriority]int
+	mu             sync.Mutex
+	logger         *zap.Logger
+}
+
+// NewMemoryJobQueue creates an in-memory queue holding at most maxSize jobs
+func NewMemoryJobQueue(maxSize int, logger *zap.Logger) *MemoryJobQueue {
+	return &MemoryJobQueue{
+		jobs:   make(chan *TransactionJob, maxSize),
+		closed: make(chan struct{}),
+		depth:  make(map[models.JobPriority]int),
+		logger: logger,
+	}
+}
+
+// Enqueue adds a job without blocking
+func (q *MemoryJobQueue) Enqueue(ctx context.Context, job *TransactionJob) error {
+	select {
+	case <-q.closed:
+		return ErrQueueClosed
+	default:
+	}
+
+	select {
+	case q.jobs <- job:
+		q.mu.Lock()
+		q.depth[normalizePriority(job.Priority)]++
+		q.mu.Unlock()
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Dequeue blocks until a job is available or the context is done
+func (q *MemoryJobQueue) Dequeue(ctx context.Context) (*TransactionJob, error) {
+	select {
+	case job := <-q.jobs:
+		q.mu.Lock()
+		q.depth[normalizePriority(job.Priority)]--
+		q.mu.Unlock()
+		return job, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-q.closed:
+		return nil, ErrQueueClosed
+	}
+}
+
+// Started is a no-op; jobs are never redelivered
+func (q *MemoryJobQueue) Started(ctx context.Context, job *TransactionJob) error {
+	return nil
+}
+
+// Renew is a no-op; dequeued jobs are not leased
+func (q *MemoryJobQueue) Renew(ctx context.Context, jobIDs []uuid.UUID) error {
+	return nil
+}
+
+// LeaseTimeout returns 0; dequeued jobs are not leased
+func (q *MemoryJobQueue) LeaseTimeout() time.Duration {
+	return 0
+}
+
+// Ack is a no-op; dequeued jobs are already removed from the channel
+func (q *MemoryJobQueue) Ack(ctx context.Context, job *TransactionJob) error {
+	return nil
+}
+
+// Retry is a no-op; the pool keeps the job in memory until it runs again
+func (q *MemoryJobQueue) Retry(ctx context.Context, job *TransactionJob, cause error, delay time.Duration) error {
+	return nil
+}
+
+// SetDeadLetterRepository persists dead-lettered jobs through the given repository
+func (q *MemoryJobQueue) SetDeadLetterRepository(repo interfaces.DeadLetterRepository) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.deadLetterRepo = repo
+}
+
+// DeadLetter stores the job in the dead-letter repository, or in memory if none is set
+func (q *MemoryJobQueue) DeadLetter(ctx context.Context, job *TransactionJob, cause error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.deadLetterRepo != nil {
+		return q.deadLetterRepo.Create(ctx, NewDeadLetterJob(job, cause))
+	}
+	q.deadLetters = append(q.deadLetters, job)
+	return nil
+}
+
+// DeadLetters returns the jobs moved to the dead-letter list
+func (q *MemoryJobQueue) DeadLetters() []*TransactionJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]*TransactionJob(nil), q.deadLetters...)
+}
+
+// Len returns the number of queued jobs
+func (q *MemoryJobQueue) Len() int {
+	return len(q.jobs)
+}
+
+// LenByPriority returns the number of queued jobs per priority
+func (q *MemoryJobQueue) LenByPriority() map[models.JobPriority]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	depth := make(map[models.JobPriority]int, len(q.depth))
+	for priority, count := range q.depth {
+		depth[priority] = count
+	}
+	return depth
+}
+
+// Capacity returns the maximum number of queued jobs
+func (q *MemoryJobQueue) Capacity() int {
+	return cap(q.jobs)
+}
+
+// Durable reports false; jobs are lost on restart
+func (q *MemoryJobQueue) Durable() bool {
+	return false
+}
+
+// Close stops accepting and delivering jobs
+func (q *MemoryJobQueue) Close() error {
+	q.closeOnce.Do(func() { close(q.closed) })
+	return nil
+}
@@ -0,0 +1,365 @@
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresQueueConfig holds the leasing configuration of the Postgres queue
+type PostgresQueueConfig struct {
+	VisibilityTimeout time.Duration // how long a lease hides a job from other workers
+	MaxDeliveries     int           // executions without settlement before a job is dead-lettered
+	PollInterval      time.Duration // wait between polls when the queue is empty
+	MaxLeasedPerUser  int           // leased jobs per submitter, 0 means no limit
+}
+
+// DefaultPostgresQueueConfig returns default leasing configuration
+func DefaultPostgresQueueConfig() PostgresQueueConfig {
+	return PostgresQueueConfig{
+		VisibilityTimeout: 30 * time.Second,
+		MaxDeliveries:     5,
+		PollInterval:      500 * time.Millisecond,
+		MaxLeasedPerUser:  20,
+	}
+}
+
+// priorityOrder sorts queued jobs from the highest priority class to the lowest
+var priorityOrder = func() string {
+	order := "CASE priority"
+	for rank, priority := range models.JobPriorities {
+		order += fmt.Sprintf(" WHEN '%s' THEN %d", priority, rank)
+	}
+	return order + fmt.Sprintf(" ELSE %d END", len(models.JobPriorities))
+}()
+
+// PostgresJobQueue is a durable queue using SELECT ... FOR UPDATE SKIP LOCKED leasing.
+// A leased job stays invisible until its visibility timeout expires. The worker pool
+// renews the leases of the jobs it holds; if it crashes before settling a job, the
+// lease runs out and the job is delivered again.
+type PostgresJobQueue struct {
+	db       *gorm.DB
+	services JobServices
+	config   PostgresQueueConfig
+	owner    string
+	logger   *zap.Logger
+}
+
+// NewPostgresJobQueue creates a new Postgres-backed queue
+func NewPostgresJobQueue(db *gorm.DB, services JobServices, config PostgresQueueConfig, logger *zap.Logger) *PostgresJobQueue {
+	hostname, _ := os.Hostname()
+	return &PostgresJobQueue{
+		db:       db,
+		services: services,
+		config:   config,
+		owner:    fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.New().String()[:8]),
+		logger:   logger,
+	}
+}
+
+// Enqueue stores the job
+func (q *PostgresJobQueue) Enqueue(ctx context.Context, job *TransactionJob) error {
+	return q.EnqueueTx(q.db.WithContext(ctx), job)
+}
+
+// EnqueueTx stores the job with the given transaction; it cannot be leased before the transaction commits
+func (q *PostgresJobQueue) EnqueueTx(tx *gorm.DB, job *TransactionJob) error {
+	row := &models.QueuedJob{
+		ID:                  job.ID,
+		TransactionType:     job.TransactionType,
+		UserID:              optionalUUID(job.UserID),
+		Priority:            normalizePriority(job.Priority),
+		FromAccountID:       optionalUUID(job.FromAccountID),
+		ToAccountID:         optionalUUID(job.ToAccountID),
+		Amount:              job.Amount,
+		SourceTransactionID: optionalUUID(job.SourceTransactionID),
+		Reference:           job.Reference,
+		RetryCount:          job.RetryCount,
+		MaxRetries:          job.MaxRetries,
+		ErrorChain:          models.EncodeErrorChain(job.ErrorChain),
+		AvailableAt:         time.Now(),
+		CreatedAt:           job.CreatedAt,
+	}
+	if err := tx.Create(row).Error; err != nil {
+		return fmt.Errorf("iş kuyruğa yazılamadı: %w", err)
+	}
+	return nil
+}
+
+// Dequeue leases the oldest available job of the highest priority, polling until one is
+// found or the context is done
+func (q *PostgresJobQueue) Dequeue(ctx context.Context) (*TransactionJob, error) {
+	for {
+		job, found, err := q.lease(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if job != nil {
+			return job, nil
+		}
+		if found {
+			// A poison job was dead-lettered, look again right away
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(q.config.PollInterval):
+		}
+	}
+}
+
+// lease tries to lease one job. found reports whether a candidate row existed.
+func (q *PostgresJobQueue) lease(ctx context.Context) (job *TransactionJob, found bool, err error) {
+	err = q.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var row models.QueuedJob
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("available_at <= ? AND (leased_until IS NULL OR leased_until < ?)", now, now)
+		if q.config.MaxLeasedPerUser > 0 {
+			// Users at their cap wait, so a bulk submission cannot fill the worker pool's
+			// window and keep other users' jobs in the table. Concurrent leases may
+			// overshoot the cap by a few jobs.
+			saturated := tx.Model(&models.QueuedJob{}).
+				Select("user_id").
+				Where("user_id IS NOT NULL AND leased_until >= ?", now).
+				Group("user_id").
+				Having("COUNT(*) >= ?", q.config.MaxLeasedPerUser)
+			query = query.Where("user_id IS NULL OR user_id NOT IN (?)", saturated)
+		}
+		// The worker pool applies priority weights and per-user fairness to what it has leased
+		result := query.
+			Order(priorityOrder).
+			Order("available_at ASC").
+			Limit(1).
+			Find(&row)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		found = true
+
+		// Jobs whose executions keep ending without settlement are crashing their workers
+		if row.Deliveries >= q.config.MaxDeliveries {
+			reason := fmt.Sprintf("iş %d kez sonuçlanmadan yarıda kaldı", row.Deliveries)
+			row.ErrorChain = models.EncodeErrorChain(append(models.DecodeErrorChain(row.ErrorChain), reason))
+			q.logger.Error("Zehirli iş dead-letter kuyruğuna taşındı",
+				zap.String("job_id", row.ID.String()),
+				zap.Int("deliveries", row.Deliveries))
+			if err := moveToDeadLetter(tx, &row, reason); err != nil {
+				return err
+			}
+			// No worker settles the job, so its tracked status is failed here
+			return tx.Model(&models.Job{}).
+				Where("id = ?", row.ID).
+				Updates(map[string]interface{}{
+					"status":       models.JobStatusFailed,
+					"error":        reason,
+					"updated_at":   now,
+					"completed_at": now,
+				}).Error
+		}
+
+		leasedUntil := now.Add(q.config.VisibilityTimeout)
+		if err := tx.Model(&models.QueuedJob{}).
+			Where("id = ?", row.ID).
+			Updates(map[string]interface{}{
+				"leased_until": leasedUntil,
+				"lease_owner":  q.owner,
+			}).Error; err != nil {
+			return err
+		}
+
+		job = q.toTransactionJob(&row)
+		return nil
+	})
+	return job, found, err
+}
+
+// Started counts an execution of the job. Leases alone are not counted, so a job
+// held in the pool past a lease renewal is not mistaken for a poison job.
+func (q *PostgresJobQueue) Started(ctx context.Context, job *TransactionJob) error {
+	return q.db.WithContext(ctx).Model(&models.QueuedJob{}).
+		Where("id = ?", job.ID).
+		Update("deliveries", gorm.Expr("deliveries + 1")).Error
+}
+
+// Renew extends the leases this instance still owns on the given jobs. Leases
+// that already run longer, such as those extended past a retry backoff, are kept.
+func (q *PostgresJobQueue) Renew(ctx context.Context, jobIDs []uuid.UUID) error {
+	leasedUntil := time.Now().Add(q.config.VisibilityTimeout)
+	return q.db.WithContext(ctx).Model(&models.QueuedJob{}).
+		Where("id IN ? AND lease_owner = ? AND leased_until < ?", jobIDs, q.owner, leasedUntil).
+		Update("leased_until", leasedUntil).Error
+}
+
+// LeaseTimeout returns the visibility timeout of a lease
+func (q *PostgresJobQueue) LeaseTimeout() time.Duration {
+	return q.config.VisibilityTimeout
+}
+
+// Ack removes a settled job
+func (q *PostgresJobQueue) Ack(ctx context.Context, job *TransactionJob) error {
+	return q.db.WithContext(ctx).Where("id = ?", job.ID).Delete(&models.QueuedJob{}).Error
+}
+
+// Retry records the failed attempt and extends the lease past the backoff delay,
+// so the job stays with this worker pool unless it crashes in the meantime
+func (q *PostgresJobQueue) Retry(ctx context.Context, job *TransactionJob, cause error, delay time.Duration) error {
+	lastError := ""
+	if cause != nil {
+		lastError = cause.Error()
+	}
+	return q.db.WithContext(ctx).Model(&models.QueuedJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"retry_count":  job.RetryCount,
+			"leased_until": time.Now().Add(delay + q.config.VisibilityTimeout),
+			"deliveries":   0,
+			"last_error":   lastError,
+			"error_chain":  models.EncodeErrorChain(job.ErrorChain),
+		}).Error
+}
+
+// DeadLetter moves the job to the dead-letter table
+func (q *PostgresJobQueue) DeadLetter(ctx context.Context, job *TransactionJob, cause error) error {
+	return q.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var row models.QueuedJob
+		if err := tx.Where("id = ?", job.ID).First(&row).Error; err != nil {
+			return err
+		}
+		row.RetryCount = job.RetryCount
+		row.ErrorChain = models.EncodeErrorChain(job.ErrorChain)
+		reason := ""
+		if cause != nil {
+			reason = cause.Error()
+		}
+		return moveToDeadLetter(tx, &row, reason)
+	})
+}
+
+// Len returns the number of jobs in the queue, including leased ones
+func (q *PostgresJobQueue) Len() int {
+	var count int64
+	if err := q.db.Model(&models.QueuedJob{}).Count(&count).Error; err != nil {
+		q.logger.Warn("Kuyruk uzunluğu alınamadı", zap.Error(err))
+		return 0
+	}
+	return int(count)
+}
+
+// LenByPriority returns the number of jobs waiting for a lease per priority
+func (q *PostgresJobQueue) LenByPriority() map[models.JobPriority]int {
+	var rows []struct {
+		Priority models.JobPriority
+		Count    int
+	}
+	depth := make(map[models.JobPriority]int)
+	err := q.db.Model(&models.QueuedJob{}).
+		Select("priority, COUNT(*) AS count").
+		Where("leased_until IS NULL OR leased_until < ?", time.Now()).
+		Group("priority").
+		Scan(&rows).Error
+	if err != nil {
+		q.logger.Warn("Önceliğe göre kuyruk uzunluğu alınamadı", zap.Error(err))
+		return depth
+	}
+	for _, row := range rows {
+		depth[row.Priority] = row.Count
+	}
+	return depth
+}
+
+// Capacity returns 0; the table is unbounded
+func (q *PostgresJobQueue) Capacity() int {
+	return 0
+}
+
+// Durable reports true; jobs survive restarts
+func (q *PostgresJobQueue) Durable() bool {
+	return true
+}
+
+// Close is a no-op; the database connection is owned by the caller
+func (q *PostgresJobQueue) Close() error {
+	return nil
+}
+
+// toTransactionJob restores a worker pool job from its stored row
+func (q *PostgresJobQueue) toTransactionJob(row *models.QueuedJob) *TransactionJob {
+	job := &TransactionJob{
+		ID:              row.ID,
+		TransactionType: row.TransactionType,
+		Priority:        row.Priority,
+		Amount:          row.Amount,
+		Reference:       row.Reference,
+		RetryCount:      row.RetryCount,
+		MaxRetries:      row.MaxRetries,
+		ErrorChain:      models.DecodeErrorChain(row.ErrorChain),
+		CreatedAt:       row.CreatedAt,
+	}
+	if row.UserID != nil {
+		job.UserID = *row.UserID
+	}
+	if row.FromAccountID != nil {
+		job.FromAccountID = *row.FromAccountID
+	}
+	if row.ToAccountID != nil {
+		job.ToAccountID = *row.ToAccountID
+	}
+	if row.SourceTransactionID != nil {
+		job.SourceTransactionID = *row.SourceTransactionID
+	}
+	q.services.bind(job)
+	return job
+}
+
+// moveToDeadLetter copies the row to the dead-letter table and removes it from the queue
+func moveToDeadLetter(tx *gorm.DB, row *models.QueuedJob, reason string) error {
+	deadLetter := &models.DeadLetterJob{
+		JobID:               row.ID,
+		TransactionType:     row.TransactionType,
+		UserID:              row.UserID,
+		Priority:            row.Priority,
+		FromAccountID:       row.FromAccountID,
+		ToAccountID:         row.ToAccountID,
+		Amount:              row.Amount,
+		SourceTransactionID: row.SourceTransactionID,
+		Reference:           row.Reference,
+		RetryCount:          row.RetryCount,
+		MaxRetries:          row.MaxRetries,
+		Reason:              reason,
+		ErrorChain:          row.ErrorChain,
+		Status:              models.DeadLetterStatusPending,
+		FailedAt:            time.Now(),
+	}
+	if err := tx.Create(deadLetter).Error; err != nil {
+		return fmt.Errorf("dead-letter kaydı oluşturulamadı: %w", err)
+	}
+	if err := tx.Where("id = ?", row.ID).Delete(&models.QueuedJob{}).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// optionalUUID returns nil for the zero UUID
+func optionalUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
@@ -2,56 +2,81 @@ package processing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/barannkoca/banking-backend/internal/interfaces"
 	"github.com/barannkoca/banking-backend/internal/models"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // WorkerPool represents a pool of workers for processing transactions
 type WorkerPool struct {
 	workers      []*Worker
-	jobQueue     chan *TransactionJob
+	workersMu    sync.Mutex
+	nextWorkerID int
+	config       PoolConfig
+	queue        JobQueue
+	sequencer    *accountSequencer
 	results      chan *TransactionResult
-	workerCount  int
-	maxQueueSize int
 	shutdownChan chan struct{}
 	wg           sync.WaitGroup
 	ctx          context.Context
 	cancel       context.CancelFunc
 	logger       *zap.Logger
 
+	// Lifecycle listeners (e.g. persistent job status tracking)
+	listeners  []JobListener
+	listenerMu sync.RWMutex
+
 	// Atomic counters for detailed statistics
 	counters *TransactionCounters
+
+	// Load tracking for autoscaling and backpressure
+	pendingJobs int64 // last measured number of jobs waiting to run
+	queueWaitNs int64 // moving average of the time ready jobs wait for a worker
+}
+
+// JobListener receives lifecycle notifications for jobs processed by the pool
+type JobListener interface {
+	OnJobStarted(job *TransactionJob)
+	OnJobCompleted(result *TransactionResult)
 }
 
 // Worker represents a single worker in the pool
 type Worker struct {
-	id       int
-	pool     *WorkerPool
-	jobChan  chan *TransactionJob
-	stopChan chan struct{}
-	wg       sync.WaitGroup
-	logger   *zap.Logger
+	id     int
+	pool   *WorkerPool
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger *zap.Logger
 }
 
 // TransactionJob represents a transaction processing job
 type TransactionJob struct {
-	ID                 uuid.UUID
-	TransactionType    string // "credit", "debit", "transfer"
-	FromAccountID      uuid.UUID
-	ToAccountID        uuid.UUID
-	Amount             float64
-	TransactionService interfaces.TransactionService
-	BalanceService     interfaces.BalanceService
-	AuditService       interfaces.AuditService
-	RetryCount         int
-	MaxRetries         int
-	CreatedAt          time.Time
+	ID                  uuid.UUID
+	TransactionType     string             // "credit", "debit", "transfer", "refund"
+	UserID              uuid.UUID          // submitter, used for fair scheduling across users
+	Priority            models.JobPriority // scheduling class, defaults to normal
+	FromAccountID       uuid.UUID
+	ToAccountID         uuid.UUID
+	Amount              float64
+	SourceTransactionID uuid.UUID // transaction a refund reverses
+	Reference           string    // caller's reference recorded on the transaction
+	TransactionService  interfaces.TransactionService
+	BalanceService      interfaces.BalanceService
+	AuditService        interfaces.AuditService
+	RetryCount          int
+	MaxRetries          int
+	ErrorChain          []string // errors of previous attempts, oldest first
+	CreatedAt           time.Time
+
+	readyAt time.Time // when the job became ready to run, for queue wait tracking
 }
 
 // TransactionResult represents the result of processing a transaction
@@ -63,18 +88,45 @@ type TransactionResult struct {
 	ProcessedAt    time.Time
 	ProcessingTime time.Duration
 	RetryCount     int
+	WillRetry      bool
 }
 
-// NewWorkerPool creates a new worker pool for transaction processing
+// NewWorkerPool creates a new fixed-size worker pool backed by an in-memory queue
 func NewWorkerPool(workerCount, maxQueueSize int, logger *zap.Logger) *WorkerPool {
+	return NewWorkerPoolWithQueue(workerCount, NewMemoryJobQueue(maxQueueSize, logger), logger)
+}
+
+// NewWorkerPoolWithQueue creates a new fixed-size worker pool pulling jobs from the given queue
+func NewWorkerPoolWithQueue(workerCount int, queue JobQueue, logger *zap.Logger) *WorkerPool {
+	config := DefaultPoolConfig()
+	config.MinWorkers = workerCount
+	config.MaxWorkers = workerCount
+	config.MaxPendingJobs = 0
+	return NewAutoscalingWorkerPool(config, queue, logger)
+}
+
+// NewAutoscalingWorkerPool creates a worker pool that scales between
+// config.MinWorkers and config.MaxWorkers based on queue depth and wait time
+func NewAutoscalingWorkerPool(config PoolConfig, queue JobQueue, logger *zap.Logger) *WorkerPool {
+	config = config.normalize()
 	ctx, cancel := context.WithCancel(context.Background())
 
+	resultBuffer := queue.Capacity()
+	if resultBuffer <= 0 {
+		resultBuffer = config.MaxWorkers * 10
+	}
+
+	// Jobs held for per-account ordering; leases of durable jobs are renewed while they wait
+	sequencerLimit := queue.Capacity()
+	if sequencerLimit <= 0 {
+		sequencerLimit = config.MaxWorkers * 20
+	}
+
 	pool := &WorkerPool{
-		workers:      make([]*Worker, workerCount),
-		jobQueue:     make(chan *TransactionJob, maxQueueSize),
-		results:      make(chan *TransactionResult, maxQueueSize),
-		workerCount:  workerCount,
-		maxQueueSize: maxQueueSize,
+		config:       config,
+		queue:        queue,
+		sequencer:    newAccountSequencer(sequencerLimit, DefaultPriorityWeights()),
+		results:      make(chan *TransactionResult, resultBuffer),
 		shutdownChan: make(chan struct{}),
 		ctx:          ctx,
 		cancel:       cancel,
@@ -83,54 +135,115 @@ func NewWorkerPool(workerCount, maxQueueSize int, logger *zap.Logger) *WorkerPoo
 	}
 
 	// Initialize workers
-	for i := 0; i < workerCount; i++ {
-		worker := &Worker{
-			id:       i + 1,
-			pool:     pool,
-			jobChan:  make(chan *TransactionJob, 1),
-			stopChan: make(chan struct{}),
-			logger:   logger.With(zap.Int("worker_id", i+1)),
-		}
-		pool.workers[i] = worker
-		pool.wg.Add(1)
-		go worker.start()
-	}
+	pool.workersMu.Lock()
+	pool.addWorkers(config.MinWorkers)
+	pool.workersMu.Unlock()
+
+	// Start dispatcher feeding the account sequencer
+	pool.wg.Add(1)
+	go pool.dispatch()
 
 	// Start result processor
 	go pool.processResults()
 
+	// Start load monitor for autoscaling and backpressure
+	go pool.monitor()
+
+	// Keep the leases of held jobs alive
+	if leaseTimeout := queue.LeaseTimeout(); leaseTimeout > 0 {
+		go pool.renewLeases(leaseTimeout / 3)
+	}
+
 	logger.Info("Worker pool başlatıldı",
-		zap.Int("worker_count", workerCount),
-		zap.Int("max_queue_size", maxQueueSize))
+		zap.Int("min_workers", config.MinWorkers),
+		zap.Int("max_workers", config.MaxWorkers),
+		zap.Int("max_queue_size", queue.Capacity()),
+		zap.Bool("durable_queue", queue.Durable()))
 
 	return pool
 }
 
+// AddListener registers a listener for job lifecycle events
+func (wp *WorkerPool) AddListener(listener JobListener) {
+	wp.listenerMu.Lock()
+	defer wp.listenerMu.Unlock()
+	wp.listeners = append(wp.listeners, listener)
+}
+
+// getListeners returns a snapshot of the registered listeners
+func (wp *WorkerPool) getListeners() []JobListener {
+	wp.listenerMu.RLock()
+	defer wp.listenerMu.RUnlock()
+	return append([]JobListener(nil), wp.listeners...)
+}
+
 // SubmitJob submits a transaction job to the worker pool
 func (wp *WorkerPool) SubmitJob(job *TransactionJob) error {
-	select {
-	case wp.jobQueue <- job:
-		// Increment pending transactions counter
-		wp.counters.IncrementPendingTransactions()
-		wp.logger.Debug("İş kuyruğa eklendi",
-			zap.String("job_id", job.ID.String()),
-			zap.String("transaction_type", job.TransactionType))
-		return nil
-	case <-wp.ctx.Done():
-		return fmt.Errorf("worker pool kapatılmış")
-	default:
-		return fmt.Errorf("iş kuyruğu dolu")
+	if wp.ctx.Err() != nil {
+		return ErrQueueClosed
+	}
+	if wp.IsSaturated() {
+		return ErrQueueFull
+	}
+
+	if err := wp.queue.Enqueue(wp.ctx, job); err != nil {
+		return err
+	}
+
+	wp.recordSubmitted(job)
+	return nil
+}
+
+// SubmitJobTx enqueues the job with tx if the queue is stored in the application
+// database, so the job is only delivered once tx commits. For other queues it
+// reports false without enqueuing; the caller then submits the job after committing.
+func (wp *WorkerPool) SubmitJobTx(tx *gorm.DB, job *TransactionJob) (bool, error) {
+	queue, ok := wp.queue.(TransactionalJobQueue)
+	if !ok {
+		return false, nil
+	}
+	if wp.ctx.Err() != nil {
+		return false, ErrQueueClosed
+	}
+	if wp.IsSaturated() {
+		return false, ErrQueueFull
+	}
+
+	if err := queue.EnqueueTx(tx, job); err != nil {
+		return false, err
 	}
+
+	wp.recordSubmitted(job)
+	return true, nil
+}
+
+// recordSubmitted updates the load counters for an enqueued job
+func (wp *WorkerPool) recordSubmitted(job *TransactionJob) {
+	// Count the job as waiting until the monitor measures again
+	atomic.AddInt64(&wp.pendingJobs, 1)
+
+	// Increment pending transactions counter
+	wp.counters.IncrementPendingTransactions()
+	wp.logger.Debug("İş kuyruğa eklendi",
+		zap.String("job_id", job.ID.String()),
+		zap.String("transaction_type", job.TransactionType))
+}
+
+// IsDurable reports whether queued jobs survive a restart
+func (wp *WorkerPool) IsDurable() bool {
+	return wp.queue.Durable()
 }
 
-// SubmitBatch submits multiple transaction jobs
-func (wp *WorkerPool) SubmitBatch(jobs []*TransactionJob) error {
-	for _, job := range jobs {
+// SubmitBatch submits multiple transaction jobs. Every job is attempted; the
+// returned slice holds the submit error of each job at the same index, or nil.
+func (wp *WorkerPool) SubmitBatch(jobs []*TransactionJob) []error {
+	errs := make([]error, len(jobs))
+	for i, job := range jobs {
 		if err := wp.SubmitJob(job); err != nil {
-			return fmt.Errorf("iş gönderilirken hata: %w", err)
+			errs[i] = fmt.Errorf("iş gönderilirken hata: %w", err)
 		}
 	}
-	return nil
+	return errs
 }
 
 // GetStatistics returns current pool statistics
@@ -139,13 +252,39 @@ func (wp *WorkerPool) GetStatistics() map[string]interface{} {
 	transactionStats := wp.counters.GetStatistics()
 
 	// Add worker pool specific statistics
+	workerCount := wp.WorkerCount()
 	poolStats := map[string]interface{}{
-		"worker_count":   wp.workerCount,
-		"max_queue_size": wp.maxQueueSize,
-		"queue_length":   len(wp.jobQueue),
-		"queue_capacity": cap(wp.jobQueue),
-		"active_workers": len(wp.workers),
+		"worker_count":   workerCount,
+		"min_workers":    wp.config.MinWorkers,
+		"max_workers":    wp.config.MaxWorkers,
+		"autoscaling":    wp.config.autoscaling(),
+		"max_queue_size": wp.queue.Capacity(),
+		"queue_length":   wp.queue.Len(),
+		"queue_capacity": wp.queue.Capacity(),
+		"queue_durable":  wp.queue.Durable(),
+		"active_workers": workerCount,
+		"pending_jobs":   atomic.LoadInt64(&wp.pendingJobs),
+		"queue_wait_ms":  float64(atomic.LoadInt64(&wp.queueWaitNs)) / float64(time.Millisecond),
+		"saturated":      wp.IsSaturated(),
+	}
+
+	// Per-account ordering statistics
+	held, ready, accounts := wp.sequencer.Stats()
+	poolStats["sequenced_jobs"] = held
+	poolStats["ready_jobs"] = ready
+	poolStats["blocked_jobs"] = held - ready
+	poolStats["active_accounts"] = accounts
+
+	// Queue depth per priority: jobs still in the queue plus jobs waiting in the pool
+	depthByPriority := wp.queue.LenByPriority()
+	for priority, count := range wp.sequencer.WaitingByPriority() {
+		depthByPriority[priority] += count
+	}
+	queueDepth := make(map[string]int, len(models.JobPriorities))
+	for _, priority := range models.JobPriorities {
+		queueDepth[string(priority)] = depthByPriority[priority]
 	}
+	poolStats["queue_depth_by_priority"] = queueDepth
 
 	// Merge statistics
 	for key, value := range poolStats {
@@ -172,6 +311,7 @@ func (wp *WorkerPool) Shutdown(timeout time.Duration) error {
 
 	select {
 	case <-done:
+		wp.queue.Close()
 		wp.logger.Info("Worker pool başarıyla kapatıldı")
 		return nil
 	case <-time.After(timeout):
@@ -183,8 +323,12 @@ func (wp *WorkerPool) Shutdown(timeout time.Duration) error {
 // processResults processes results from workers
 func (wp *WorkerPool) processResults() {
 	for result := range wp.results {
-		// Record transaction in atomic counters (simplified)
-		wp.counters.RecordTransaction(nil, result.Success, result.ProcessingTime, result.Error)
+		// Record transaction in atomic counters
+		wp.counters.RecordTransaction(result.Transaction, result.Success, result.ProcessingTime, result.Error)
+
+		for _, listener := range wp.getListeners() {
+			listener.OnJobCompleted(result)
+		}
 
 		if !result.Success {
 			wp.logger.Error("İşlem başarısız",
@@ -199,24 +343,77 @@ func (wp *WorkerPool) processResults() {
 	}
 }
 
+// dispatch moves jobs from the queue into the account sequencer
+func (wp *WorkerPool) dispatch() {
+	defer wp.wg.Done()
+
+	for {
+		if err := wp.sequencer.WaitForCapacity(wp.ctx); err != nil {
+			return
+		}
+
+		job, err := wp.queue.Dequeue(wp.ctx)
+		if err != nil {
+			if wp.ctx.Err() != nil || errors.Is(err, ErrQueueClosed) {
+				return
+			}
+
+			wp.logger.Error("Kuyruktan iş alınamadı", zap.Error(err))
+			select {
+			case <-time.After(time.Second):
+			case <-wp.ctx.Done():
+				return
+			}
+			continue
+		}
+
+		if !wp.sequencer.Add(job) {
+			wp.logger.Debug("Zaten işlenmekte olan iş tekrar teslim edildi, atlanıyor",
+				zap.String("job_id", job.ID.String()))
+		}
+	}
+}
+
+// renewLeases periodically renews the leases of the jobs held by the sequencer, so
+// a job waiting for its accounts or running is not delivered to another instance
+func (wp *WorkerPool) renewLeases(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-wp.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		jobIDs := wp.sequencer.HeldIDs()
+		if len(jobIDs) == 0 {
+			continue
+		}
+		if err := wp.queue.Renew(wp.ctx, jobIDs); err != nil {
+			wp.logger.Warn("İş kiralamaları yenilenemedi",
+				zap.Int("job_count", len(jobIDs)),
+				zap.Error(err))
+		}
+	}
+}
+
 // start starts a worker
 func (w *Worker) start() {
 	defer w.pool.wg.Done()
-	defer close(w.jobChan)
 
 	w.logger.Info("Worker başlatıldı")
 
 	for {
-		select {
-		case job := <-w.pool.jobQueue:
-			w.processJob(job)
-		case <-w.stopChan:
+		job, err := w.pool.sequencer.Next(w.ctx)
+		if err != nil {
 			w.logger.Info("Worker durduruldu")
 			return
-		case <-w.pool.ctx.Done():
-			w.logger.Info("Worker context iptal edildi")
-			return
 		}
+
+		w.pool.recordQueueWait(time.Since(job.readyAt))
+		w.processJob(job)
 	}
 }
 
@@ -229,102 +426,153 @@ func (w *Worker) processJob(job *TransactionJob) {
 		zap.String("transaction_type", job.TransactionType),
 		zap.Float64("amount", job.Amount))
 
+	if err := w.pool.queue.Started(context.Background(), job); err != nil {
+		w.logger.Warn("İş çalıştırması kaydedilemedi",
+			zap.String("job_id", job.ID.String()),
+			zap.Error(err))
+	}
+
+	for _, listener := range w.pool.getListeners() {
+		listener.OnJobStarted(job)
+	}
+
 	// Process the transaction based on its type
+	var transaction *models.Transaction
 	var err error
 	switch job.TransactionType {
 	case "credit":
-		err = w.processCredit(job)
+		transaction, err = w.processCredit(job)
 	case "debit":
-		err = w.processDebit(job)
+		transaction, err = w.processDebit(job)
 	case "transfer":
-		err = w.processTransfer(job)
+		transaction, err = w.processTransfer(job)
+	case "refund":
+		transaction, err = w.processRefund(job)
 	default:
 		err = fmt.Errorf("desteklenmeyen işlem türü: %s", job.TransactionType)
 	}
 
 	processingTime := time.Since(startTime)
+	willRetry := err != nil && job.RetryCount < job.MaxRetries
+	if err != nil {
+		job.ErrorChain = append(job.ErrorChain, fmt.Sprintf("deneme %d: %v", job.RetryCount+1, err))
+	}
 
 	// Create result
 	result := &TransactionResult{
 		JobID:          job.ID,
-		Transaction:    nil, // Artık transaction objesi yok
+		Transaction:    transaction,
 		Success:        err == nil,
 		Error:          err,
 		ProcessedAt:    time.Now(),
 		ProcessingTime: processingTime,
 		RetryCount:     job.RetryCount,
+		WillRetry:      willRetry,
 	}
 
-	// Send result
+	// Send result; listeners rely on every result being delivered, so wait for room
 	select {
 	case w.pool.results <- result:
 		// Result sent successfully
-	default:
-		w.logger.Warn("Sonuç kuyruğu dolu, sonuç atıldı",
+	case <-w.pool.ctx.Done():
+		w.logger.Warn("Worker pool kapatılıyor, sonuç iletilemedi",
 			zap.String("job_id", job.ID.String()))
 	}
 
-	// Handle retry logic
-	if err != nil && job.RetryCount < job.MaxRetries {
+	// Handle retry logic; exhausted jobs go to the dead-letter store, the rest are settled
+	if willRetry {
 		w.handleRetry(job, err)
+		return
+	}
+	defer w.pool.sequencer.Done(job)
+
+	if err != nil {
+		w.handleDeadLetter(job, err)
+		return
+	}
+
+	if ackErr := w.pool.queue.Ack(context.Background(), job); ackErr != nil {
+		w.logger.Error("İş onaylanamadı",
+			zap.String("job_id", job.ID.String()),
+			zap.Error(ackErr))
 	}
 }
 
 // processCredit processes a credit transaction
-func (w *Worker) processCredit(job *TransactionJob) error {
-	ctx := context.Background()
+func (w *Worker) processCredit(job *TransactionJob) (*models.Transaction, error) {
+	ctx := WithJobID(context.Background(), job.ID)
 
 	// Process the credit using the service
-	err := job.TransactionService.Credit(ctx, job.ToAccountID, job.Amount)
+	transaction, err := job.TransactionService.Credit(ctx, job.ToAccountID, job.Amount, job.Reference)
 
 	if err != nil {
 		// Log audit trail for failed transaction
 		job.AuditService.LogSystemActivity(ctx, "CREDIT_FAILED", fmt.Sprintf("Credit failed for account %s: %v", job.ToAccountID, err))
-		return fmt.Errorf("credit işlemi başarısız: %w", err)
+		return nil, fmt.Errorf("credit işlemi başarısız: %w", err)
 	}
 
 	// Log audit trail for successful transaction
 	job.AuditService.LogSystemActivity(ctx, "CREDIT_SUCCESS", fmt.Sprintf("Credit successful for account %s: %f", job.ToAccountID, job.Amount))
 
-	return nil
+	return transaction, nil
 }
 
 // processDebit processes a debit transaction
-func (w *Worker) processDebit(job *TransactionJob) error {
-	ctx := context.Background()
+func (w *Worker) processDebit(job *TransactionJob) (*models.Transaction, error) {
+	ctx := WithJobID(context.Background(), job.ID)
 
 	// Process the debit using the service
-	err := job.TransactionService.Debit(ctx, job.FromAccountID, job.Amount)
+	transaction, err := job.TransactionService.Debit(ctx, job.FromAccountID, job.Amount, job.Reference)
 
 	if err != nil {
 		// Log audit trail for failed transaction
 		job.AuditService.LogSystemActivity(ctx, "DEBIT_FAILED", fmt.Sprintf("Debit failed for account %s: %v", job.FromAccountID, err))
-		return fmt.Errorf("debit işlemi başarısız: %w", err)
+		return nil, fmt.Errorf("debit işlemi başarısız: %w", err)
 	}
 
 	// Log audit trail for successful transaction
 	job.AuditService.LogSystemActivity(ctx, "DEBIT_SUCCESS", fmt.Sprintf("Debit successful for account %s: %f", job.FromAccountID, job.Amount))
 
-	return nil
+	return transaction, nil
 }
 
 // processTransfer processes a transfer transaction
-func (w *Worker) processTransfer(job *TransactionJob) error {
-	ctx := context.Background()
+func (w *Worker) processTransfer(job *TransactionJob) (*models.Transaction, error) {
+	ctx := WithJobID(context.Background(), job.ID)
 
 	// Process the transfer using the service
-	err := job.TransactionService.Transfer(ctx, job.FromAccountID, job.ToAccountID, job.Amount)
+	transaction, err := job.TransactionService.Transfer(ctx, job.FromAccountID, job.ToAccountID, job.Amount, job.Reference)
 
 	if err != nil {
 		// Log audit trail for failed transaction
 		job.AuditService.LogSystemActivity(ctx, "TRANSFER_FAILED", fmt.Sprintf("Transfer failed from %s to %s: %v", job.FromAccountID, job.ToAccountID, err))
-		return fmt.Errorf("transfer işlemi başarısız: %w", err)
+		return nil, fmt.Errorf("transfer işlemi başarısız: %w", err)
 	}
 
 	// Log audit trail for successful transaction
 	job.AuditService.LogSystemActivity(ctx, "TRANSFER_SUCCESS", fmt.Sprintf("Transfer successful from %s to %s: %f", job.FromAccountID, job.ToAccountID, job.Amount))
 
-	return nil
+	return transaction, nil
+}
+
+// processRefund processes the refund of a transaction. The job carries the parties of the
+// refund, payer first, so it is sequenced with other jobs on the same accounts.
+func (w *Worker) processRefund(job *TransactionJob) (*models.Transaction, error) {
+	ctx := WithJobID(context.Background(), job.ID)
+
+	// Process the refund using the service
+	transaction, err := job.TransactionService.Refund(ctx, job.SourceTransactionID)
+
+	if err != nil {
+		// Log audit trail for failed transaction
+		job.AuditService.LogSystemActivity(ctx, "REFUND_FAILED", fmt.Sprintf("Refund of transaction %s failed: %v", job.SourceTransactionID, err))
+		return nil, fmt.Errorf("iade işlemi başarısız: %w", err)
+	}
+
+	// Log audit trail for successful transaction
+	job.AuditService.LogSystemActivity(ctx, "REFUND_SUCCESS", fmt.Sprintf("Refund of transaction %s successful: %f", job.SourceTransactionID, job.Amount))
+
+	return transaction, nil
 }
 
 // handleRetry handles retry logic for failed jobs
@@ -344,19 +592,45 @@ func (w *Worker) handleRetry(job *TransactionJob, err error) {
 		zap.Duration("backoff_duration", backoffDuration),
 		zap.Error(err))
 
-	// Schedule retry
+	// Record the attempt; the job keeps its place in the account lanes until it runs again
+	if retryErr := w.pool.queue.Retry(context.Background(), job, err, backoffDuration); retryErr != nil {
+		w.logger.Error("Retry planlanamadı",
+			zap.String("job_id", job.ID.String()),
+			zap.Error(retryErr))
+		w.pool.sequencer.Done(job)
+		return
+	}
 	time.AfterFunc(backoffDuration, func() {
-		select {
-		case w.pool.jobQueue <- job:
-			// Increment retry counter
-			w.pool.counters.IncrementRetryCount()
-		case <-w.pool.ctx.Done():
-			w.logger.Warn("Retry işlemi iptal edildi, worker pool kapatılıyor")
-		}
+		w.pool.sequencer.Requeue(job)
 	})
+
+	// Increment retry counter
+	w.pool.counters.IncrementRetryCount()
+}
+
+// handleDeadLetter moves a job that exhausted its retries to the dead-letter store
+func (w *Worker) handleDeadLetter(job *TransactionJob, err error) {
+	w.logger.Error("İşlem yeniden deneme hakkını tüketti, dead-letter kuyruğuna taşınıyor",
+		zap.String("job_id", job.ID.String()),
+		zap.String("transaction_type", job.TransactionType),
+		zap.Int("retry_count", job.RetryCount),
+		zap.Strings("error_chain", job.ErrorChain),
+		zap.Error(err))
+
+	if dlErr := w.pool.queue.DeadLetter(context.Background(), job, err); dlErr != nil {
+		w.logger.Error("İş dead-letter kuyruğuna taşınamadı",
+			zap.String("job_id", job.ID.String()),
+			zap.Error(dlErr))
+		return
+	}
+
+	if job.AuditService != nil {
+		job.AuditService.LogSystemActivity(context.Background(), "JOB_DEAD_LETTERED",
+			fmt.Sprintf("Job %s (%s) moved to dead-letter store after %d retries: %v", job.ID, job.TransactionType, job.RetryCount, err))
+	}
 }
 
-// Stop stops a specific worker
+// Stop stops a specific worker after its current job
 func (w *Worker) Stop() {
-	close(w.stopChan)
+	w.cancel()
 }
@@ -0,0 +1,383 @@
+package processing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// orderingTransactionService records the order and concurrency of operations per account
+type orderingTransactionService struct {
+	mu            sync.Mutex
+	running       map[uuid.UUID]int
+	started       map[uuid.UUID][]int
+	sequence      map[uuid.UUID]int // job ID -> submission index
+	violations    []string
+	concurrent    int32
+	maxConcurrent int32
+}
+
+func newOrderingTransactionService() *orderingTransactionService {
+	return &orderingTransactionService{
+		running:  make(map[uuid.UUID]int),
+		started:  make(map[uuid.UUID][]int),
+		sequence: make(map[uuid.UUID]int),
+	}
+}
+
+func (s *orderingTransactionService) run(ctx context.Context, accounts ...uuid.UUID) {
+	jobID, _ := JobIDFromContext(ctx)
+
+	s.mu.Lock()
+	for _, account := range accounts {
+		if s.running[account] > 0 {
+			s.violations = append(s.violations, fmt.Sprintf("account %s used concurrently", account))
+		}
+		s.running[account]++
+		s.started[account] = append(s.started[account], s.sequence[jobID])
+	}
+	s.mu.Unlock()
+
+	current := atomic.AddInt32(&s.concurrent, 1)
+	for {
+		max := atomic.LoadInt32(&s.maxConcurrent)
+		if current <= max || atomic.CompareAndSwapInt32(&s.maxConcurrent, max, current) {
+			break
+		}
+	}
+
+	time.Sleep(2 * time.Millisecond)
+
+	atomic.AddInt32(&s.concurrent, -1)
+	s.mu.Lock()
+	for _, account := range accounts {
+		s.running[account]--
+	}
+	s.mu.Unlock()
+}
+
+func (s *orderingTransactionService) Credit(ctx context.Context, accountID uuid.UUID, amount float64, reference string) (*models.Transaction, error) {
+	s.run(ctx, accountID)
+	return &models.Transaction{ID: uuid.New()}, nil
+}
+
+func (s *orderingTransactionService) Debit(ctx context.Context, accountID uuid.UUID, amount float64, reference string) (*models.Transaction, error) {
+	s.run(ctx, accountID)
+	return &models.Transaction{ID: uuid.New()}, nil
+}
+
+func (s *orderingTransactionService) Transfer(ctx context.Context, fromAccountID, toAccountID uuid.UUID, amount float64, reference string) (*models.Transaction, error) {
+	s.run(ctx, fromAccountID, toAccountID)
+	return &models.Transaction{ID: uuid.New()}, nil
+}
+
+func (s *orderingTransactionService) Refund(ctx context.Context, transactionID uuid.UUID) (*models.Transaction, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// completionListener counts settled jobs
+type completionListener struct {
+	done chan uuid.UUID
+}
+
+func (l *completionListener) OnJobStarted(job *TransactionJob) {}
+
+func (l *completionListener) OnJobCompleted(result *TransactionResult) {
+	if !result.WillRetry {
+		l.done <- result.JobID
+	}
+}
+
+func TestWorkerPoolPerAccountOrdering(t *testing.T) {
+	const jobCount = 300
+
+	service := newOrderingTransactionService()
+	pool := NewWorkerPool(8, jobCount, zap.NewNop())
+	defer pool.Shutdown(5 * time.Second)
+
+	listener := &completionListener{done: make(chan uuid.UUID, jobCount)}
+	pool.AddListener(listener)
+
+	accounts := make([]uuid.UUID, 6)
+	for i := range accounts {
+		accounts[i] = uuid.New()
+	}
+
+	jobs := make([]*TransactionJob, jobCount)
+	for i := range jobs {
+		job := &TransactionJob{
+			ID:                 uuid.New(),
+			Amount:             1,
+			TransactionService: service,
+			AuditService:       &MockAuditService{},
+			CreatedAt:          time.Now(),
+		}
+		switch i % 3 {
+		case 0:
+			job.TransactionType = "credit"
+			job.ToAccountID = accounts[i%len(accounts)]
+		case 1:
+			job.TransactionType = "debit"
+			job.FromAccountID = accounts[(i*7)%len(accounts)]
+		default:
+			job.TransactionType = "transfer"
+			job.FromAccountID = accounts[i%len(accounts)]
+			job.ToAccountID = accounts[(i+1)%len(accounts)]
+		}
+		service.sequence[job.ID] = i
+		jobs[i] = job
+	}
+
+	for _, job := range jobs {
+		if err := pool.SubmitJob(job); err != nil {
+			t.Fatalf("submit job: %v", err)
+		}
+	}
+
+	timeout := time.After(10 * time.Second)
+	for i := 0; i < jobCount; i++ {
+		select {
+		case <-listener.done:
+		case <-timeout:
+			t.Fatalf("only %d of %d jobs completed", i, jobCount)
+		}
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	for _, violation := range service.violations {
+		t.Error(violation)
+	}
+
+	for account, started := range service.started {
+		for i := 1; i < len(started); i++ {
+			if started[i] < started[i-1] {
+				t.Errorf("account %s ran job %d before job %d", account, started[i-1], started[i])
+			}
+		}
+	}
+
+	if atomic.LoadInt32(&service.maxConcurrent) < 2 {
+		t.Errorf("jobs on different accounts never ran in parallel")
+	}
+}
+
+func TestWorkerPoolRetryKeepsAccountOrder(t *testing.T) {
+	account := uuid.New()
+
+	var mu sync.Mutex
+	var order []string
+	attempts := 0
+
+	service := &funcTransactionService{
+		credit: func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts++
+			if attempts == 1 {
+				order = append(order, "credit-failed")
+				return fmt.Errorf("transient error")
+			}
+			order = append(order, "credit")
+			return nil
+		},
+		debit: func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, "debit")
+			return nil
+		},
+	}
+
+	pool := NewWorkerPool(4, 10, zap.NewNop())
+	defer pool.Shutdown(5 * time.Second)
+
+	listener := &completionListener{done: make(chan uuid.UUID, 2)}
+	pool.AddListener(listener)
+
+	credit := &TransactionJob{ID: uuid.New(), TransactionType: "credit", ToAccountID: account, Amount: 1,
+		TransactionService: service, AuditService: &MockAuditService{}, MaxRetries: 1}
+	debit := &TransactionJob{ID: uuid.New(), TransactionType: "debit", FromAccountID: account, Amount: 1,
+		TransactionService: service, AuditService: &MockAuditService{}, MaxRetries: 1}
+
+	if err := pool.SubmitJob(credit); err != nil {
+		t.Fatalf("submit credit: %v", err)
+	}
+	if err := pool.SubmitJob(debit); err != nil {
+		t.Fatalf("submit debit: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-listener.done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("jobs did not complete")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{"credit-failed", "credit", "debit"}
+	if fmt.Sprint(order) != fmt.Sprint(expected) {
+		t.Errorf("expected order %v, got %v", expected, order)
+	}
+}
+
+// funcTransactionService delegates credits and debits to functions
+type funcTransactionService struct {
+	credit func(ctx context.Context) error
+	debit  func(ctx context.Context) error
+}
+
+func (s *funcTransactionService) Credit(ctx context.Context, accountID uuid.UUID, amount float64, reference string) (*models.Transaction, error) {
+	if err := s.credit(ctx); err != nil {
+		return nil, err
+	}
+	return &models.Transaction{ID: uuid.New()}, nil
+}
+
+func (s *funcTransactionService) Debit(ctx context.Context, accountID uuid.UUID, amount float64, reference string) (*models.Transaction, error) {
+	if err := s.debit(ctx); err != nil {
+		return nil, err
+	}
+	return &models.Transaction{ID: uuid.New()}, nil
+}
+
+func (s *funcTransactionService) Transfer(ctx context.Context, fromAccountID, toAccountID uuid.UUID, amount float64, reference string) (*models.Transaction, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *funcTransactionService) Refund(ctx context.Context, transactionID uuid.UUID) (*models.Transaction, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// leasingQueue is a memory queue that reports a lease timeout and records renewals and executions
+type leasingQueue struct {
+	*MemoryJobQueue
+	mu      sync.Mutex
+	renewed map[uuid.UUID]int
+	started map[uuid.UUID]int
+}
+
+func newLeasingQueue() *leasingQueue {
+	return &leasingQueue{
+		MemoryJobQueue: NewMemoryJobQueue(10, zap.NewNop()),
+		renewed:        make(map[uuid.UUID]int),
+		started:        make(map[uuid.UUID]int),
+	}
+}
+
+func (q *leasingQueue) Started(ctx context.Context, job *TransactionJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.started[job.ID]++
+	return nil
+}
+
+func (q *leasingQueue) Renew(ctx context.Context, jobIDs []uuid.UUID) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, id := range jobIDs {
+		q.renewed[id]++
+	}
+	return nil
+}
+
+func (q *leasingQueue) LeaseTimeout() time.Duration {
+	return 30 * time.Millisecond
+}
+
+func (q *leasingQueue) counts(id uuid.UUID) (renewed, started int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.renewed[id], q.started[id]
+}
+
+func TestWorkerPoolRenewsLeasesOfHeldJobs(t *testing.T) {
+	account := uuid.New()
+	release := make(chan struct{})
+	running := make(chan struct{})
+
+	service := &funcTransactionService{
+		credit: func(ctx context.Context) error {
+			close(running)
+			<-release
+			return nil
+		},
+		debit: func(ctx context.Context) error { return nil },
+	}
+
+	queue := newLeasingQueue()
+	pool := NewWorkerPoolWithQueue(2, queue, zap.NewNop())
+	defer pool.Shutdown(5 * time.Second)
+
+	listener := &completionListener{done: make(chan uuid.UUID, 2)}
+	pool.AddListener(listener)
+
+	// The debit waits in the sequencer behind the running credit on the same account
+	credit := &TransactionJob{ID: uuid.New(), TransactionType: "credit", ToAccountID: account, Amount: 1,
+		TransactionService: service, AuditService: &MockAuditService{}}
+	debit := &TransactionJob{ID: uuid.New(), TransactionType: "debit", FromAccountID: account, Amount: 1,
+		TransactionService: service, AuditService: &MockAuditService{}}
+	for _, job := range []*TransactionJob{credit, debit} {
+		if err := pool.SubmitJob(job); err != nil {
+			t.Fatalf("submit job: %v", err)
+		}
+	}
+
+	select {
+	case <-running:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("credit did not start")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		creditRenewed, _ := queue.counts(credit.ID)
+		debitRenewed, _ := queue.counts(debit.ID)
+		if creditRenewed >= 2 && debitRenewed >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("leases not renewed: credit %d, debit %d", creditRenewed, debitRenewed)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if _, started := queue.counts(debit.ID); started != 0 {
+		t.Errorf("held debit counted as executed %d times", started)
+	}
+
+	close(release)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-listener.done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("jobs did not complete")
+		}
+	}
+
+	for _, job := range []*TransactionJob{credit, debit} {
+		if _, started := queue.counts(job.ID); started != 1 {
+			t.Errorf("job %s executed %d times, expected 1", job.TransactionType, started)
+		}
+	}
+}
+
+func TestDeadLetterReplayKeepsReference(t *testing.T) {
+	job := &TransactionJob{ID: uuid.New(), TransactionType: "transfer", FromAccountID: uuid.New(), ToAccountID: uuid.New(),
+		Amount: 10, Reference: "invoice 4471", MaxRetries: 3}
+
+	replay := JobFromDeadLetter(NewDeadLetterJob(job, fmt.Errorf("failed")), JobServices{})
+	if replay.Reference != job.Reference {
+		t.Errorf("expected reference %q after replay, got %q", job.Reference, replay.Reference)
+	}
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BatchRepository implements the BatchRepository interface
+type BatchRepository struct {
+	db *gorm.DB
+}
+
+// NewBatchRepository creates a new BatchRepository instance
+func NewBatchRepository(db *gorm.DB) interfaces.BatchRepository {
+	return &BatchRepository{db: db}
+}
+
+// Create stores a batch together with its items
+func (r *BatchRepository) Create(ctx context.Context, batch *models.Batch) error {
+	return r.db.WithContext(ctx).Create(batch).Error
+}
+
+// FindByID retrieves a batch with its items in submission order, including their jobs
+func (r *BatchRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Batch, error) {
+	var batch models.Batch
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Items.Job").
+		Where("id = ?", id).
+		First(&batch).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("batch not found")
+		}
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// UpdateStatus updates the status of a batch
+func (r *BatchRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.BatchStatus, completedAt *time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Batch{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"completed_at": completedAt,
+			"updated_at":   time.Now(),
+		}).Error
+}
+
+// UpdateItems saves the status fields of the given items in one database transaction
+func (r *BatchRepository) UpdateItems(ctx context.Context, items []models.BatchItem) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			if err := tx.Model(&models.BatchItem{}).
+				Where("id = ?", item.ID).
+				Updates(map[string]interface{}{
+					"status":         item.Status,
+					"error":          item.Error,
+					"job_id":         item.JobID,
+					"transaction_id": item.TransactionID,
+					"updated_at":     time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CategoryRuleRepository implements the CategoryRuleRepository interface
+type CategoryRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryRuleRepository creates a new CategoryRuleRepository instance
+func NewCategoryRuleRepository(db *gorm.DB) interfaces.CategoryRuleRepository {
+	return &CategoryRuleRepository{db: db}
+}
+
+// Create stores a new categorization rule
+func (r *CategoryRuleRepository) Create(ctx context.Context, rule *models.CategoryRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+// FindByID retrieves a categorization rule by ID
+func (r *CategoryRuleRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.CategoryRule, error) {
+	var rule models.CategoryRule
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&rule).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("category rule not found")
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ListByUser retrieves all rules of a user, oldest first
+func (r *CategoryRuleRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.CategoryRule, error) {
+	var rules []*models.CategoryRule
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// Update saves changes to a categorization rule
+func (r *CategoryRuleRepository) Update(ctx context.Context, rule *models.CategoryRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+// Delete removes a categorization rule
+func (r *CategoryRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.CategoryRule{}).Error
+}
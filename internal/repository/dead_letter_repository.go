@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeadLetterRepository implements the DeadLetterRepository interface
+type DeadLetterRepository struct {
+	db *gorm.DB
+}
+
+// NewDeadLetterRepository creates a new DeadLetterRepository instance
+func NewDeadLetterRepository(db *gorm.DB) interfaces.DeadLetterRepository {
+	return &DeadLetterRepository{db: db}
+}
+
+// Create stores a dead-lettered job
+func (r *DeadLetterRepository) Create(ctx context.Context, deadLetter *models.DeadLetterJob) error {
+	return r.db.WithContext(ctx).Create(deadLetter).Error
+}
+
+// FindByID retrieves a dead-lettered job by ID
+func (r *DeadLetterRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.DeadLetterJob, error) {
+	var deadLetter models.DeadLetterJob
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&deadLetter).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("dead-letter job not found")
+		}
+		return nil, err
+	}
+	return &deadLetter, nil
+}
+
+// List retrieves dead-lettered jobs, newest first; an empty status matches all
+func (r *DeadLetterRepository) List(ctx context.Context, status models.DeadLetterStatus, limit, offset int) ([]*models.DeadLetterJob, error) {
+	var deadLetters []*models.DeadLetterJob
+	query := r.db.WithContext(ctx).Order("failed_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+	err := query.Find(&deadLetters).Error
+	return deadLetters, err
+}
+
+// Count counts dead-lettered jobs; an empty status matches all
+func (r *DeadLetterRepository) Count(ctx context.Context, status models.DeadLetterStatus) (int64, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&models.DeadLetterJob{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// Update saves the editable fields of a pending dead-lettered job
+func (r *DeadLetterRepository) Update(ctx context.Context, deadLetter *models.DeadLetterJob) error {
+	result := r.db.WithContext(ctx).Model(&models.DeadLetterJob{}).
+		Where("id = ? AND status = ?", deadLetter.ID, models.DeadLetterStatusPending).
+		Updates(map[string]interface{}{
+			"from_account_id": deadLetter.FromAccountID,
+			"to_account_id":   deadLetter.ToAccountID,
+			"amount":          deadLetter.Amount,
+			"max_retries":     deadLetter.MaxRetries,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("dead-letter job is not pending")
+	}
+	return nil
+}
+
+// MarkResolved moves a pending dead-lettered job to a final status.
+// It fails if the job was already resolved, so concurrent replays cannot both succeed.
+func (r *DeadLetterRepository) MarkResolved(ctx context.Context, id uuid.UUID, status models.DeadLetterStatus, resolvedBy uuid.UUID) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&models.DeadLetterJob{}).
+		Where("id = ? AND status = ?", id, models.DeadLetterStatusPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"resolved_by": resolvedBy,
+			"resolved_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("dead-letter job is not pending")
+	}
+	return nil
+}
+
+// Reopen returns a resolved dead-lettered job to pending, e.g. when a replay could not be submitted
+func (r *DeadLetterRepository) Reopen(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.DeadLetterJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":      models.DeadLetterStatusPending,
+			"resolved_by": nil,
+			"resolved_at": nil,
+			"updated_at":  time.Now(),
+		}).Error
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// JobRepository implements the JobRepository interface
+type JobRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRepository creates a new JobRepository instance
+func NewJobRepository(db *gorm.DB) interfaces.JobRepository {
+	return &JobRepository{db: db}
+}
+
+// Create creates a new job record
+func (r *JobRepository) Create(ctx context.Context, job *models.Job) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// FindByID retrieves a job by ID together with its linked transaction
+func (r *JobRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	var job models.Job
+	err := r.db.WithContext(ctx).Preload("Transaction").Where("id = ?", id).First(&job).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("job not found")
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindByStatus retrieves jobs in the given status, oldest first
+func (r *JobRepository) FindByStatus(ctx context.Context, status models.JobStatus, limit int) ([]*models.Job, error) {
+	var jobs []*models.Job
+	query := r.db.WithContext(ctx).Where("status = ?", status).Order("created_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&jobs).Error
+	return jobs, err
+}
+
+// MarkProcessing marks a job as picked up by a worker
+func (r *JobRepository) MarkProcessing(ctx context.Context, id uuid.UUID, retryCount int) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&models.Job{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":      models.JobStatusProcessing,
+			"retry_count": retryCount,
+			"started_at":  now,
+			"updated_at":  now,
+		}).Error
+}
+
+// MarkCompleted marks a job as completed and links the resulting transaction
+func (r *JobRepository) MarkCompleted(ctx context.Context, id uuid.UUID, transactionID *uuid.UUID) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&models.Job{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":         models.JobStatusCompleted,
+			"transaction_id": transactionID,
+			"error":          "",
+			"completed_at":   now,
+			"updated_at":     now,
+		}).Error
+}
+
+// MarkFailed records a failed attempt; status is either retrying or failed
+func (r *JobRepository) MarkFailed(ctx context.Context, id uuid.UUID, status models.JobStatus, errMsg string) error {
+	now := time.Now()
+	fields := map[string]interface{}{
+		"status":     status,
+		"error":      errMsg,
+		"updated_at": now,
+	}
+	if status == models.JobStatusFailed {
+		fields["completed_at"] = now
+	}
+	return r.db.WithContext(ctx).Model(&models.Job{}).
+		Where("id = ?", id).
+		Updates(fields).Error
+}
+
+// Requeue resets a failed job to queued for a replay, applying any edited fields
+func (r *JobRepository) Requeue(ctx context.Context, id uuid.UUID, fromAccountID, toAccountID *uuid.UUID, amount float64) error {
+	return r.db.WithContext(ctx).Model(&models.Job{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          models.JobStatusQueued,
+			"from_account_id": fromAccountID,
+			"to_account_id":   toAccountID,
+			"amount":          amount,
+			"error":           "",
+			"retry_count":     0,
+			"started_at":      nil,
+			"completed_at":    nil,
+			"updated_at":      time.Now(),
+		}).Error
+}
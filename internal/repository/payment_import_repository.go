@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PaymentImportRepository implements the PaymentImportRepository interface
+type PaymentImportRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentImportRepository creates a new PaymentImportRepository instance
+func NewPaymentImportRepository(db *gorm.DB) interfaces.PaymentImportRepository {
+	return &PaymentImportRepository{db: db}
+}
+
+// Create stores an import together with its rows
+func (r *PaymentImportRepository) Create(ctx context.Context, paymentImport *models.PaymentImport) error {
+	return r.db.WithContext(ctx).Create(paymentImport).Error
+}
+
+// FindByID retrieves an import with its rows in file order
+func (r *PaymentImportRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.PaymentImport, error) {
+	var paymentImport models.PaymentImport
+	err := r.db.WithContext(ctx).
+		Preload("Rows", func(db *gorm.DB) *gorm.DB {
+			return db.Order("line ASC")
+		}).
+		Where("id = ?", id).
+		First(&paymentImport).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("payment import not found")
+		}
+		return nil, err
+	}
+	return &paymentImport, nil
+}
+
+// ExistsByHash checks if the user already uploaded the same file, ignoring cancelled and expired imports
+func (r *PaymentImportRepository) ExistsByHash(ctx context.Context, userID uuid.UUID, fileHash string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.PaymentImport{}).
+		Where("user_id = ? AND file_hash = ? AND status IN ?", userID, fileHash,
+			[]models.PaymentImportStatus{models.PaymentImportStatusPreview, models.PaymentImportStatusConfirmed}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// TransitionStatus moves an import from one status to another.
+// It fails if the import is no longer in the expected status, so concurrent confirmations cannot both succeed.
+func (r *PaymentImportRepository) TransitionStatus(ctx context.Context, id uuid.UUID, from, to models.PaymentImportStatus) error {
+	result := r.db.WithContext(ctx).Model(&models.PaymentImport{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{
+			"status":     to,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("payment import is not %s", from)
+	}
+	return nil
+}
+
+// SetBatch links a confirmed import to the batch executing it
+func (r *PaymentImportRepository) SetBatch(ctx context.Context, id, batchID uuid.UUID, confirmedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.PaymentImport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"batch_id":     batchID,
+			"confirmed_at": confirmedAt,
+			"updated_at":   time.Now(),
+		}).Error
+}
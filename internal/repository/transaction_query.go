@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// TransactionFilterScope applies a transaction filter to a query. It is shared by the
+// history endpoints, reports and anything else that selects transactions.
+func TransactionFilterScope(filter models.TransactionFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch {
+		case filter.AccountID != nil && filter.CounterpartyID != nil:
+			db = db.Where("(from_user_id = ? AND to_user_id = ?) OR (from_user_id = ? AND to_user_id = ?)",
+				*filter.AccountID, *filter.CounterpartyID, *filter.CounterpartyID, *filter.AccountID)
+		case filter.AccountID != nil:
+			db = db.Where("from_user_id = ? OR to_user_id = ?", *filter.AccountID, *filter.AccountID)
+		case filter.CounterpartyID != nil:
+			db = db.Where("from_user_id = ? OR to_user_id = ?", *filter.CounterpartyID, *filter.CounterpartyID)
+		}
+
+		if len(filter.Types) > 0 {
+			db = db.Where("type IN ?", filter.Types)
+		}
+		if len(filter.Statuses) > 0 {
+			db = db.Where("status IN ?", filter.Statuses)
+		}
+		if filter.From != nil {
+			db = db.Where("created_at >= ?", *filter.From)
+		}
+		if filter.To != nil {
+			db = db.Where("created_at <= ?", *filter.To)
+		}
+		if filter.MinAmount != nil {
+			db = db.Where("amount >= ?", *filter.MinAmount)
+		}
+		if filter.MaxAmount != nil {
+			db = db.Where("amount <= ?", *filter.MaxAmount)
+		}
+		if filter.Reference != "" {
+			db = db.Where("reference ILIKE ?", "%"+escapeLike(filter.Reference)+"%")
+		}
+		return db
+	}
+}
+
+// TransactionQueryScope applies the filter, ordering and keyset position of a query and
+// fetches one row more than the page size so the caller can tell whether more pages exist
+func TransactionQueryScope(query *models.TransactionQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(TransactionFilterScope(query.Filter))
+
+		column := string(query.SortBy)
+		direction := "DESC"
+		comparison := "<"
+		if query.SortDir == models.SortAscending {
+			direction = "ASC"
+			comparison = ">"
+		}
+
+		if cursor := query.Cursor; cursor != nil {
+			var value interface{} = cursor.CreatedAt
+			if query.SortBy == models.TransactionSortAmount {
+				value = cursor.Amount
+			}
+			db = db.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, comparison), value, cursor.ID)
+		}
+
+		return db.Order(fmt.Sprintf("%s %s, id %s", column, direction, direction)).
+			Limit(query.Limit + 1)
+	}
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -35,12 +35,15 @@ func (tr *TransactionRepository) FindByID(ctx context.Context, id uuid.UUID) (*m
 	return &transaction, nil
 }
 
-// FindByAccount finds transactions by account ID
-func (tr *TransactionRepository) FindByAccount(ctx context.Context, accountID uuid.UUID) ([]*models.Transaction, error) {
+// FindByAccount finds a page of an account's transactions matching the query. The result
+// holds up to query.Limit+1 rows; use query.NewPage to split off the next cursor.
+func (tr *TransactionRepository) FindByAccount(ctx context.Context, accountID uuid.UUID, query *models.TransactionQuery) ([]*models.Transaction, error) {
+	scoped := *query
+	scoped.Filter.AccountID = &accountID
+
 	var transactions []*models.Transaction
 	err := tr.db.WithContext(ctx).
-		Where("from_user_id = ? OR to_user_id = ?", accountID, accountID).
-		Order("created_at DESC").
+		Scopes(TransactionQueryScope(&scoped)).
 		Find(&transactions).Error
 	return transactions, err
 }
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInvalidAccountToken is returned for an unknown, expired or already used account token
+	ErrInvalidAccountToken = errors.New("invalid account token")
+	// ErrEmailAlreadyVerified is returned when verification is requested for a verified address
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+	// ErrVerificationRecentlySent is returned when a verification email was sent within the resend interval
+	ErrVerificationRecentlySent = errors.New("verification email recently sent")
+)
+
+// accountTokenBytes is the entropy of emailed tokens
+const accountTokenBytes = 32
+
+// AccountService handles password resets and email verification with single-use emailed tokens
+type AccountService struct {
+	userRepo             interfaces.UserRepository
+	userService          *UserService
+	notificationService  interfaces.NotificationService
+	auditService         interfaces.AuditService
+	passwordResetTTL     time.Duration
+	emailVerificationTTL time.Duration
+	resendInterval       time.Duration
+	logger               *zap.Logger
+}
+
+// NewAccountService creates a new AccountService
+func NewAccountService(
+	userRepo interfaces.UserRepository,
+	userService *UserService,
+	notificationService interfaces.NotificationService,
+	auditService interfaces.AuditService,
+	passwordResetTTL time.Duration,
+	emailVerificationTTL time.Duration,
+	resendInterval time.Duration,
+	logger *zap.Logger,
+) *AccountService {
+	return &AccountService{
+		userRepo:             userRepo,
+		userService:          userService,
+		notificationService:  notificationService,
+		auditService:         auditService,
+		passwordResetTTL:     passwordResetTTL,
+		emailVerificationTTL: emailVerificationTTL,
+		resendInterval:       resendInterval,
+		logger:               logger,
+	}
+}
+
+// RequestPasswordReset emails a reset token if the address belongs to a user. Unknown
+// addresses are not reported, so the endpoint cannot be used to enumerate accounts.
+func (s *AccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, strings.TrimSpace(strings.ToLower(email)))
+	if err != nil {
+		s.logger.Info("Password reset requested for unknown email", zap.String("type", "password_reset_unknown"))
+		return nil
+	}
+
+	token, err := s.issueToken(ctx, user, models.AccountTokenPasswordReset, s.passwordResetTTL)
+	if err != nil {
+		return err
+	}
+	if err := s.notificationService.SendPasswordResetEmail(ctx, user, token); err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, user.ID, "PASSWORD_RESET_REQUESTED", "user", user.ID.String(), "Password reset email sent")
+	}
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token, signs the user out of every
+// session and revokes their API keys. The token stays usable if the new password is rejected.
+func (s *AccountService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	var userID uuid.UUID
+	now := time.Now()
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		accountToken, err := claimToken(tx, token, models.AccountTokenPasswordReset, now)
+		if err != nil {
+			return err
+		}
+		userID = accountToken.UserID
+
+		if err := s.userService.ResetPassword(tx, userID, newPassword); err != nil {
+			return err
+		}
+
+		// Other reset links sent before this one must not work either
+		if err := tx.Model(&models.AccountToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, models.AccountTokenPasswordReset).
+			Update("used_at", now).Error; err != nil {
+			return fmt.Errorf("failed to invalidate reset tokens: %w", err)
+		}
+
+		if _, err := revokeFamilies(tx, models.TokenRevokedPasswordReset, now, "user_id = ?", userID); err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+
+		// API keys may have leaked along with the password
+		if err := tx.Model(&models.APIKey{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error; err != nil {
+			return fmt.Errorf("failed to revoke api keys: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "PASSWORD_RESET", "user", userID.String(), "Password reset with emailed token")
+	}
+
+	s.logger.Info("Password reset completed",
+		zap.String("user_id", userID.String()),
+		zap.String("type", "password_reset"),
+	)
+	return nil
+}
+
+// ChangePassword changes the password of a signed-in user and signs out their other
+// sessions, returning how many were revoked
+func (s *AccountService) ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, currentPassword, newPassword string) (int64, error) {
+	if err := s.userService.ChangePassword(ctx, userID, currentPassword, newPassword); err != nil {
+		return 0, err
+	}
+
+	revoked, err := revokeFamilies(database.GetDB().WithContext(ctx), models.TokenRevokedPasswordChanged, time.Now(),
+		"user_id = ? AND id <> ?", userID, sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return revoked, nil
+}
+
+// VerifyEmail marks the address a verification token was sent to as verified. Tokens sent
+// to an address the user has since changed are rejected.
+func (s *AccountService) VerifyEmail(ctx context.Context, token string) (*models.User, error) {
+	var user *models.User
+	now := time.Now()
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		accountToken, err := claimToken(tx, token, models.AccountTokenEmailVerification, now)
+		if err != nil {
+			return err
+		}
+
+		user = &models.User{}
+		if err := tx.First(user, "id = ?", accountToken.UserID).Error; err != nil {
+			return ErrInvalidAccountToken
+		}
+		if !strings.EqualFold(user.Email, accountToken.Email) {
+			return ErrInvalidAccountToken
+		}
+		if user.IsEmailVerified() {
+			return ErrEmailAlreadyVerified
+		}
+
+		if err := tx.Model(user).Update("email_verified_at", now).Error; err != nil {
+			return fmt.Errorf("failed to verify email: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, user.ID, "EMAIL_VERIFIED", "user", user.ID.String(), "Email address verified")
+	}
+	return user, nil
+}
+
+// SendVerification emails a verification token for the user's current address, at most
+// once per resend interval. Earlier verification tokens stop working.
+func (s *AccountService) SendVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	var recent int64
+	if err := database.GetDB().WithContext(ctx).Model(&models.AccountToken{}).
+		Where("user_id = ? AND purpose = ? AND created_at > ?", userID, models.AccountTokenEmailVerification, time.Now().Add(-s.resendInterval)).
+		Count(&recent).Error; err != nil {
+		return fmt.Errorf("failed to check verification emails: %w", err)
+	}
+	if recent > 0 {
+		return ErrVerificationRecentlySent
+	}
+
+	token, err := s.issueToken(ctx, user, models.AccountTokenEmailVerification, s.emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+	if err := s.notificationService.SendEmailVerification(ctx, user, token); err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+	return nil
+}
+
+// IsEmailVerified reports whether the user verified their current email address
+func (s *AccountService) IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, ErrUserNotFound
+	}
+	return user.IsEmailVerified(), nil
+}
+
+// issueToken stores the hash of a new token for the user's current email and invalidates
+// the user's earlier unused tokens of the same purpose
+func (s *AccountService) issueToken(ctx context.Context, user *models.User, purpose models.AccountTokenPurpose, ttl time.Duration) (string, error) {
+	raw := make([]byte, accountTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate account token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.AccountToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", user.ID, purpose).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.AccountToken{
+			UserID:    user.ID,
+			Purpose:   purpose,
+			TokenHash: hashAccountToken(token),
+			Email:     user.Email,
+			ExpiresAt: now.Add(ttl),
+		}).Error
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store account token: %w", err)
+	}
+	return token, nil
+}
+
+// claimToken locks a valid token of the given purpose and marks it used; the caller's
+// transaction decides whether the use sticks
+func claimToken(tx *gorm.DB, token string, purpose models.AccountTokenPurpose, now time.Time) (*models.AccountToken, error) {
+	var accountToken models.AccountToken
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", hashAccountToken(token), purpose, now).
+		First(&accountToken).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAccountToken
+		}
+		return nil, fmt.Errorf("failed to load account token: %w", err)
+	}
+
+	if err := tx.Model(&accountToken).Update("used_at", now).Error; err != nil {
+		return nil, fmt.Errorf("failed to use account token: %w", err)
+	}
+	return &accountToken, nil
+}
+
+// hashAccountToken hashes an emailed token. Tokens carry 256 random bits, so a fast hash
+// is enough to keep stored values useless to a database reader.
+func hashAccountToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
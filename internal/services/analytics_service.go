@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// topCounterpartyLimit is the number of counterparties listed in a spending report
+const topCounterpartyLimit = 5
+
+// ErrInvalidSpendingPeriod is returned for an unknown analytics period
+var ErrInvalidSpendingPeriod = errors.New("invalid spending period")
+
+// AnalyticsService computes spending analytics from completed outgoing transactions
+type AnalyticsService struct {
+	logger *zap.Logger
+}
+
+// NewAnalyticsService creates a new AnalyticsService
+func NewAnalyticsService(logger *zap.Logger) *AnalyticsService {
+	return &AnalyticsService{
+		logger: logger,
+	}
+}
+
+// categoryTotal is a per-category aggregate row
+type categoryTotal struct {
+	Category models.TransactionCategory
+	Total    float64
+	Count    int64
+}
+
+// Spending reports a user's outgoing money per category for the period containing at,
+// compared with the preceding period, together with the top counterparties
+func (s *AnalyticsService) Spending(ctx context.Context, userID uuid.UUID, period models.SpendingPeriod, at time.Time) (*models.SpendingReport, error) {
+	from, to, previousFrom, err := periodBounds(period, at)
+	if err != nil {
+		return nil, err
+	}
+	to, previousTo := comparedBounds(from, to, previousFrom, time.Now())
+
+	current, err := s.categoryTotals(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.categoryTotals(ctx, userID, previousFrom, previousTo)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.SpendingReport{
+		Period:            period,
+		From:              from,
+		To:                to,
+		PreviousFrom:      previousFrom,
+		PreviousTo:        previousTo,
+		Categories:        make([]*models.CategorySpending, 0),
+		TopCounterparties: make([]*models.CounterpartySpending, 0),
+	}
+
+	byCategory := make(map[models.TransactionCategory]*models.CategorySpending)
+	entry := func(category models.TransactionCategory) *models.CategorySpending {
+		if spending, ok := byCategory[category]; ok {
+			return spending
+		}
+		spending := &models.CategorySpending{Category: category}
+		byCategory[category] = spending
+		report.Categories = append(report.Categories, spending)
+		return spending
+	}
+	for _, row := range current {
+		spending := entry(row.Category)
+		spending.Total = row.Total
+		spending.Count = row.Count
+		report.Total += row.Total
+	}
+	for _, row := range previous {
+		entry(row.Category).PreviousTotal = row.Total
+		report.PreviousTotal += row.Total
+	}
+	for _, spending := range report.Categories {
+		spending.Delta = roundAmount(spending.Total - spending.PreviousTotal)
+		if spending.PreviousTotal > 0 {
+			percent := math.Round((spending.Total-spending.PreviousTotal)/spending.PreviousTotal*10000) / 100
+			spending.DeltaPercent = &percent
+		}
+	}
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		if report.Categories[i].Total != report.Categories[j].Total {
+			return report.Categories[i].Total > report.Categories[j].Total
+		}
+		return report.Categories[i].Category < report.Categories[j].Category
+	})
+	report.Total = roundAmount(report.Total)
+	report.PreviousTotal = roundAmount(report.PreviousTotal)
+
+	err = s.outgoing(ctx, userID, from, to).
+		Joins("JOIN users ON users.id = transactions.to_user_id").
+		Select("transactions.to_user_id AS user_id, users.username, SUM(transactions.amount) AS total, COUNT(*) AS count").
+		Group("transactions.to_user_id, users.username").
+		Order("total DESC").
+		Limit(topCounterpartyLimit).
+		Scan(&report.TopCounterparties).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top counterparties: %w", err)
+	}
+
+	s.logger.Debug("Spending report generated",
+		zap.String("user_id", userID.String()),
+		zap.String("period", string(period)),
+		zap.Int("category_count", len(report.Categories)))
+
+	return report, nil
+}
+
+// categoryTotals sums outgoing money per category in [from, to); uncategorized rows count as other
+func (s *AnalyticsService) categoryTotals(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]categoryTotal, error) {
+	var rows []categoryTotal
+	err := s.outgoing(ctx, userID, from, to).
+		Select("COALESCE(NULLIF(transactions.category, ''), ?) AS category, SUM(transactions.amount) AS total, COUNT(*) AS count", models.CategoryOther).
+		Group("1").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum spending by category: %w", err)
+	}
+	return rows, nil
+}
+
+// outgoing selects the completed transactions paid by the user in [from, to)
+func (s *AnalyticsService) outgoing(ctx context.Context, userID uuid.UUID, from, to time.Time) *gorm.DB {
+	return database.GetDB().WithContext(ctx).Model(&models.Transaction{}).
+		Where("transactions.from_user_id = ? AND transactions.status = ? AND transactions.created_at >= ? AND transactions.created_at < ?",
+			userID, models.TransactionStatusCompleted, from, to)
+}
+
+// periodBounds returns the period containing at and the start of the preceding period
+func periodBounds(period models.SpendingPeriod, at time.Time) (time.Time, time.Time, time.Time, error) {
+	year, month, day := at.Date()
+	location := at.Location()
+
+	switch period {
+	case models.SpendingPeriodWeek:
+		weekday := (int(at.Weekday()) + 6) % 7 // Monday starts the week
+		from := time.Date(year, month, day-weekday, 0, 0, 0, 0, location)
+		return from, from.AddDate(0, 0, 7), from.AddDate(0, 0, -7), nil
+	case models.SpendingPeriodMonth:
+		from := time.Date(year, month, 1, 0, 0, 0, 0, location)
+		return from, from.AddDate(0, 1, 0), from.AddDate(0, -1, 0), nil
+	case models.SpendingPeriodYear:
+		from := time.Date(year, 1, 1, 0, 0, 0, 0, location)
+		return from, from.AddDate(1, 0, 0), from.AddDate(-1, 0, 0), nil
+	default:
+		return time.Time{}, time.Time{}, time.Time{}, fmt.Errorf("%w: period must be week, month or year", ErrInvalidSpendingPeriod)
+	}
+}
+
+// comparedBounds returns the ends of the current and preceding periods to compare at now.
+// A running period is compared with the same length from the start of the preceding one,
+// so a partial month is not set against a whole month.
+func comparedBounds(from, to, previousFrom, now time.Time) (time.Time, time.Time) {
+	if !now.Before(to) {
+		return to, from
+	}
+	if now.Before(from) {
+		now = from
+	}
+	previousTo := previousFrom.Add(now.Sub(from))
+	if previousTo.After(from) {
+		// The preceding period is shorter, e.g. February against the end of March
+		previousTo = from
+	}
+	return now, previousTo
+}
+
+// roundAmount rounds an amount to cents
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/pkg/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidClient is returned for unknown or revoked clients and wrong secrets
+	ErrInvalidClient = errors.New("invalid client")
+	// ErrInvalidScope is returned for unknown scopes or scopes the client was not granted
+	ErrInvalidScope = errors.New("invalid scope")
+	// ErrAPIClientNotFound is returned when an API client does not exist
+	ErrAPIClientNotFound = errors.New("api client not found")
+)
+
+const (
+	// clientIDBytes and clientSecretBytes are the entropy of generated credentials
+	clientIDBytes     = 12
+	clientSecretBytes = 32
+)
+
+// ClientToken is an access token issued with the client credentials grant
+type ClientToken struct {
+	AccessToken string
+	ExpiresIn   time.Duration
+	Scopes      []string
+}
+
+// APIClientService registers API clients and issues and verifies their access tokens
+type APIClientService struct {
+	jwtConfig    *utils.JWTConfig
+	tokenTTL     time.Duration
+	auditService interfaces.AuditService
+	logger       *zap.Logger
+}
+
+// NewAPIClientService creates a new APIClientService
+func NewAPIClientService(jwtConfig *utils.JWTConfig, tokenTTL time.Duration, auditService interfaces.AuditService, logger *zap.Logger) *APIClientService {
+	return &APIClientService{
+		jwtConfig:    jwtConfig,
+		tokenTTL:     tokenTTL,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// CreateClient registers an API client and returns it with its secret. The secret is only
+// available here; a lost secret means registering a new client.
+func (s *APIClientService) CreateClient(ctx context.Context, adminID uuid.UUID, req *models.APIClientCreateRequest) (*models.APIClient, string, error) {
+	scopes, err := normalizeScopes(req.Scopes, models.IsValidScope)
+	if err != nil {
+		return nil, "", err
+	}
+
+	clientID, err := randomCredential(clientIDBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomCredential(clientSecretBytes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := &models.APIClient{
+		ClientID:   "cli_" + clientID,
+		SecretHash: hashCredential(secret),
+		Name:       strings.TrimSpace(req.Name),
+		Scopes:     strings.Join(scopes, " "),
+		CreatedBy:  adminID,
+	}
+	if err := database.GetDB().WithContext(ctx).Create(client).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to create api client: %w", err)
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, adminID, "API_CLIENT_CREATED", "api_client", client.ID.String(),
+			fmt.Sprintf("API client %s created with scopes %s", client.ClientID, client.Scopes))
+	}
+	return client, secret, nil
+}
+
+// ListClients returns all API clients, newest first
+func (s *APIClientService) ListClients(ctx context.Context) ([]models.APIClient, error) {
+	var clients []models.APIClient
+	if err := database.GetDB().WithContext(ctx).Order("created_at DESC").Find(&clients).Error; err != nil {
+		return nil, fmt.Errorf("failed to list api clients: %w", err)
+	}
+	return clients, nil
+}
+
+// RevokeClient revokes an API client. Its tokens are rejected from the next request on.
+func (s *APIClientService) RevokeClient(ctx context.Context, adminID, id uuid.UUID) error {
+	result := database.GetDB().WithContext(ctx).Model(&models.APIClient{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke api client: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIClientNotFound
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, adminID, "API_CLIENT_REVOKED", "api_client", id.String(), "API client revoked")
+	}
+	return nil
+}
+
+// IssueToken authenticates a client with its secret and issues an access token for the
+// requested scopes, or for all granted scopes when none are requested
+func (s *APIClientService) IssueToken(ctx context.Context, clientID, secret string, requested []string) (*ClientToken, error) {
+	var client models.APIClient
+	err := database.GetDB().WithContext(ctx).Where("client_id = ?", clientID).First(&client).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidClient
+		}
+		return nil, fmt.Errorf("failed to load api client: %w", err)
+	}
+	if client.IsRevoked() || subtle.ConstantTimeCompare([]byte(hashCredential(secret)), []byte(client.SecretHash)) != 1 {
+		return nil, ErrInvalidClient
+	}
+
+	granted := client.ScopeList()
+	scopes := granted
+	if len(requested) > 0 {
+		scopes, err = normalizeScopes(requested, models.IsValidScope)
+		if err != nil {
+			return nil, err
+		}
+		for _, scope := range scopes {
+			if !containsScope(granted, scope) {
+				return nil, ErrInvalidScope
+			}
+		}
+	}
+
+	accessToken, err := utils.GenerateClientAccessToken(client.ClientID, scopes, s.tokenTTL, s.jwtConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate client token: %w", err)
+	}
+
+	if err := database.GetDB().WithContext(ctx).Model(&client).Update("last_used_at", time.Now()).Error; err != nil {
+		s.logger.Warn("Failed to record api client use",
+			zap.String("client_id", client.ClientID),
+			zap.Error(err),
+			zap.String("type", "api_client_error"),
+		)
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogSystemActivity(ctx, "API_CLIENT_TOKEN_ISSUED",
+			fmt.Sprintf("Token issued to API client %s with scopes %s", client.ClientID, strings.Join(scopes, " ")))
+	}
+	return &ClientToken{AccessToken: accessToken, ExpiresIn: s.tokenTTL, Scopes: scopes}, nil
+}
+
+// VerifyClientToken validates an API client access token and checks the client was not
+// revoked since. It returns nil claims and no error for tokens that are not client
+// tokens, so callers can fall back to user authentication.
+func (s *APIClientService) VerifyClientToken(ctx context.Context, token string) (*utils.JWTClaims, error) {
+	claims, err := utils.ValidateToken(token, s.jwtConfig)
+	if err != nil || claims.TokenType != utils.TokenTypeClientAccess {
+		return nil, nil
+	}
+
+	var client models.APIClient
+	err = database.GetDB().WithContext(ctx).Where("client_id = ?", claims.ClientID).First(&client).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidClient
+		}
+		return nil, fmt.Errorf("failed to load api client: %w", err)
+	}
+	if client.IsRevoked() {
+		return nil, ErrInvalidClient
+	}
+	return claims, nil
+}
+
+// normalizeScopes validates scopes and removes duplicates
+func normalizeScopes(scopes []string, valid func(string) bool) ([]string, error) {
+	var normalized []string
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !valid(scope) {
+			return nil, ErrInvalidScope
+		}
+		if !containsScope(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, ErrInvalidScope
+	}
+	return normalized, nil
+}
+
+// containsScope reports whether scopes includes scope
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// randomCredential returns n random bytes encoded as lowercase hex
+func randomCredential(n int) (string, error) {
+	raw := make([]byte, n)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate credential: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// hashCredential hashes a generated secret. Secrets carry 256 random bits, so a fast hash
+// is enough and keeps authentication cheap.
+func hashCredential(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
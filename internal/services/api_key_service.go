@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidAPIKey is returned for malformed, unknown, revoked or expired API keys
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrAPIKeyIPNotAllowed is returned when an API key is used from an address outside its allow-list
+	ErrAPIKeyIPNotAllowed = errors.New("api key not allowed from this ip")
+	// ErrAPIKeyNotFound is returned when the user has no such active API key
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrInvalidAPIKeyExpiry is returned for an expiry in the past or beyond the maximum lifetime
+	ErrInvalidAPIKeyExpiry = errors.New("invalid api key expiry")
+	// ErrInvalidIPAllowList is returned when an allow-list entry is not an IP address or CIDR
+	ErrInvalidIPAllowList = errors.New("invalid ip allow-list")
+	// ErrAPIKeyLimitReached is returned when the user already has the maximum number of active keys
+	ErrAPIKeyLimitReached = errors.New("api key limit reached")
+)
+
+const (
+	// apiKeyPrefix marks API keys so they are recognizable in code and secret scanners
+	apiKeyPrefix = "bk_"
+	// apiKeyIDBytes and apiKeySecretBytes are the entropy of the public and secret parts
+	apiKeyIDBytes     = 6
+	apiKeySecretBytes = 32
+	// apiKeyTouchInterval limits last-used writes to one per key per interval
+	apiKeyTouchInterval = time.Minute
+)
+
+// APIKeyService manages personal API keys and authenticates requests made with them
+type APIKeyService struct {
+	auditService interfaces.AuditService
+	maxLifetime  time.Duration
+	maxPerUser   int
+	logger       *zap.Logger
+}
+
+// NewAPIKeyService creates a new APIKeyService
+func NewAPIKeyService(auditService interfaces.AuditService, maxLifetime time.Duration, maxPerUser int, logger *zap.Logger) *APIKeyService {
+	return &APIKeyService{
+		auditService: auditService,
+		maxLifetime:  maxLifetime,
+		maxPerUser:   maxPerUser,
+		logger:       logger,
+	}
+}
+
+// CreateKey creates an API key for the user and returns it with the full key, which is
+// only available here. Keys have the form bk_<prefix>_<secret>.
+func (s *APIKeyService) CreateKey(ctx context.Context, userID uuid.UUID, req *models.APIKeyCreateRequest) (*models.APIKey, string, error) {
+	scopes, err := normalizeScopes(req.Scopes, models.IsValidAPIKeyScope)
+	if err != nil {
+		return nil, "", err
+	}
+	allowedIPs, err := normalizeAllowedIPs(req.AllowedIPs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.maxLifetime)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) || req.ExpiresAt.After(expiresAt) {
+			return nil, "", ErrInvalidAPIKeyExpiry
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	id, err := randomCredential(apiKeyIDBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomCredential(apiKeySecretBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	prefix := apiKeyPrefix + id
+	key := prefix + "_" + secret
+
+	apiKey := &models.APIKey{
+		UserID:     userID,
+		Name:       strings.TrimSpace(req.Name),
+		Prefix:     prefix,
+		KeyHash:    hashCredential(key),
+		Scopes:     strings.Join(scopes, " "),
+		AllowedIPs: strings.Join(allowedIPs, " "),
+		ExpiresAt:  expiresAt,
+	}
+
+	err = database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialize creation per user so concurrent requests cannot exceed the limit
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "api_keys:"+userID.String()).Error; err != nil {
+			return fmt.Errorf("failed to lock api keys: %w", err)
+		}
+
+		var active int64
+		if err := tx.Model(&models.APIKey{}).
+			Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+			Count(&active).Error; err != nil {
+			return fmt.Errorf("failed to count api keys: %w", err)
+		}
+		if active >= int64(s.maxPerUser) {
+			return ErrAPIKeyLimitReached
+		}
+
+		if err := tx.Create(apiKey).Error; err != nil {
+			return fmt.Errorf("failed to create api key: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "API_KEY_CREATED", "api_key", apiKey.ID.String(),
+			fmt.Sprintf("API key %s created with scopes %s", apiKey.Prefix, apiKey.Scopes))
+	}
+	return apiKey, key, nil
+}
+
+// ListKeys returns the user's API keys, newest first, including revoked and expired ones
+func (s *APIKeyService) ListKeys(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := database.GetDB().WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeKey revokes one of the user's API keys. It stops working immediately.
+func (s *APIKeyService) RevokeKey(ctx context.Context, userID, id uuid.UUID) error {
+	result := database.GetDB().WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke api key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "API_KEY_REVOKED", "api_key", id.String(), "API key revoked")
+	}
+	return nil
+}
+
+// VerifyAPIKey authenticates a request made with an API key from ipAddress and returns the
+// key with its user loaded. Use is recorded at most once per key per minute.
+func (s *APIKeyService) VerifyAPIKey(ctx context.Context, key, ipAddress string) (*models.APIKey, error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0]+"_" != apiKeyPrefix {
+		return nil, ErrInvalidAPIKey
+	}
+	prefix := apiKeyPrefix + parts[1]
+
+	var apiKey models.APIKey
+	err := database.GetDB().WithContext(ctx).Preload("User").Where("prefix = ?", prefix).First(&apiKey).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("failed to load api key: %w", err)
+	}
+
+	now := time.Now()
+	if subtle.ConstantTimeCompare([]byte(hashCredential(key)), []byte(apiKey.KeyHash)) != 1 ||
+		!apiKey.IsActive(now) || apiKey.User == nil {
+		return nil, ErrInvalidAPIKey
+	}
+	if !ipAllowed(apiKey.AllowedIPList(), ipAddress) {
+		return nil, ErrAPIKeyIPNotAllowed
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		if err := database.GetDB().WithContext(ctx).Model(&apiKey).
+			Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": ipAddress}).Error; err != nil {
+			s.logger.Warn("Failed to record api key use",
+				zap.String("prefix", apiKey.Prefix),
+				zap.Error(err),
+				zap.String("type", "api_key_error"),
+			)
+		}
+	}
+	return &apiKey, nil
+}
+
+// normalizeAllowedIPs validates allow-list entries and returns them in canonical form
+func normalizeAllowedIPs(entries []string) ([]string, error) {
+	normalized := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, ErrInvalidIPAllowList
+			}
+			normalized = append(normalized, network.String())
+			continue
+		}
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, ErrInvalidIPAllowList
+		}
+		normalized = append(normalized, ip.String())
+	}
+	return normalized, nil
+}
+
+// ipAllowed reports whether ipAddress matches the allow-list; an empty list allows any address
+func ipAllowed(allowed []string, ipAddress string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return false
+	}
+	for _, entry := range allowed {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+		} else if allowedIP := net.ParseIP(entry); allowedIP != nil && allowedIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
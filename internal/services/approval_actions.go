@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+
+	"github.com/barannkoca/banking-backend/internal/authz"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// RoleChangeAction changes a user's role once approved by another role manager
+func RoleChangeAction(userService *UserService) ApprovalAction {
+	return ApprovalAction{
+		Authorize: requirePermission(authz.UsersManageRoles),
+		Execute: func(ctx context.Context, request *models.ApprovalRequest) (*uuid.UUID, error) {
+			var payload models.RoleChangePayload
+			if err := decodeApprovalPayload(request, &payload); err != nil {
+				return nil, err
+			}
+			return nil, userService.UpdateUserRole(ctx, payload.UserID, payload.Role)
+		},
+	}
+}
+
+// CreditAction credits an account once approved by a user whose own limit covers the amount.
+// The credit runs as a job, so it is ordered with other jobs on the account.
+func CreditAction(transactionService *TransactionService, jobService *JobService) ApprovalAction {
+	return ApprovalAction{
+		Authorize: func(ctx context.Context, checker authz.Subject, request *models.ApprovalRequest) error {
+			var payload models.CreditPayload
+			if err := decodeApprovalPayload(request, &payload); err != nil {
+				return err
+			}
+			return transactionService.AuthorizeCredit(ctx, checker, payload.AccountID, payload.Amount)
+		},
+		Execute: func(ctx context.Context, request *models.ApprovalRequest) (*uuid.UUID, error) {
+			var payload models.CreditPayload
+			if err := decodeApprovalPayload(request, &payload); err != nil {
+				return nil, err
+			}
+			return submitApprovalJob(ctx, jobService, request, &models.Job{
+				Type:        models.JobTypeCredit,
+				ToAccountID: &payload.AccountID,
+				Amount:      payload.Amount,
+				Reference:   "Approved credit",
+			})
+		},
+	}
+}
+
+// RefundAction refunds a transaction once approved by a user who is not one of its parties.
+// The refund runs as a job, so it is ordered with other jobs on the accounts.
+func RefundAction(transactionService *TransactionService, jobService *JobService) ApprovalAction {
+	return ApprovalAction{
+		Authorize: func(ctx context.Context, checker authz.Subject, request *models.ApprovalRequest) error {
+			if err := requirePermission(authz.AccountsRefund)(ctx, checker, request); err != nil {
+				return err
+			}
+			var payload models.RefundPayload
+			if err := decodeApprovalPayload(request, &payload); err != nil {
+				return err
+			}
+			transaction, err := transactionService.GetTransactionByID(ctx, payload.TransactionID)
+			if err != nil {
+				return err
+			}
+			if transaction.IsParty(checker.UserID) {
+				return ErrSelfApproval
+			}
+			return nil
+		},
+		Execute: func(ctx context.Context, request *models.ApprovalRequest) (*uuid.UUID, error) {
+			var payload models.RefundPayload
+			if err := decodeApprovalPayload(request, &payload); err != nil {
+				return nil, err
+			}
+			transaction, err := transactionService.GetTransactionByID(ctx, payload.TransactionID)
+			if err != nil {
+				return nil, err
+			}
+			// The job names the refund's parties, payer first, for account ordering
+			return submitApprovalJob(ctx, jobService, request, &models.Job{
+				Type:                models.JobTypeRefund,
+				FromAccountID:       transaction.ToUserID,
+				ToAccountID:         transaction.FromUserID,
+				Amount:              transaction.Amount,
+				Reference:           "Approved refund",
+				SourceTransactionID: &payload.TransactionID,
+			})
+		},
+	}
+}
+
+// AccountFreezeAction freezes or unfreezes an account once approved
+func AccountFreezeAction(balanceService *BalanceService) ApprovalAction {
+	return ApprovalAction{
+		Authorize: requirePermission(authz.AccountsFreeze),
+		Execute: func(ctx context.Context, request *models.ApprovalRequest) (*uuid.UUID, error) {
+			var payload models.AccountFreezePayload
+			if err := decodeApprovalPayload(request, &payload); err != nil {
+				return nil, err
+			}
+			return nil, balanceService.SetAccountFrozen(ctx, payload.AccountID, payload.Frozen)
+		},
+	}
+}
+
+// DeadLetterEditAction applies an edit of a dead-lettered job's accounts or amount and
+// replays the job once approved by a user who may manage jobs. An edited credit must also
+// be within the checker's own credit limit.
+func DeadLetterEditAction(deadLetterService *DeadLetterService, transactionService *TransactionService) ApprovalAction {
+	return ApprovalAction{
+		Authorize: func(ctx context.Context, checker authz.Subject, request *models.ApprovalRequest) error {
+			if err := requirePermission(authz.JobsManage)(ctx, checker, request); err != nil {
+				return err
+			}
+			var payload models.DeadLetterEditPayload
+			if err := decodeApprovalPayload(request, &payload); err != nil {
+				return err
+			}
+			if models.JobType(payload.TransactionType) == models.JobTypeCredit && payload.ToAccountID != nil {
+				return transactionService.AuthorizeCredit(ctx, checker, *payload.ToAccountID, payload.Amount)
+			}
+			return nil
+		},
+		Execute: func(ctx context.Context, request *models.ApprovalRequest) (*uuid.UUID, error) {
+			var payload models.DeadLetterEditPayload
+			if err := decodeApprovalPayload(request, &payload); err != nil {
+				return nil, err
+			}
+			return deadLetterService.ReplayApprovedEdit(ctx, request, &payload)
+		},
+	}
+}
+
+// submitApprovalJob queues the money movement of an approved request as a high priority job
+// owned by the maker. The job ID is the request ID, so executing a request again finds the
+// job already queued instead of moving the money twice.
+func submitApprovalJob(ctx context.Context, jobService *JobService, request *models.ApprovalRequest, job *models.Job) (*uuid.UUID, error) {
+	if existing, err := jobService.GetJob(ctx, request.ID); err == nil {
+		return &existing.ID, nil
+	}
+
+	job.ID = request.ID
+	job.UserID = request.MakerID
+	job.Priority = models.JobPriorityHigh
+	if err := jobService.SubmitJob(ctx, job); err != nil {
+		// A concurrent execution may have queued it first
+		if existing, getErr := jobService.GetJob(ctx, request.ID); getErr == nil {
+			return &existing.ID, nil
+		}
+		return nil, err
+	}
+	return &job.ID, nil
+}
+
+// requirePermission authorizes checkers holding the permission
+func requirePermission(permission authz.Permission) func(context.Context, authz.Subject, *models.ApprovalRequest) error {
+	return func(_ context.Context, checker authz.Subject, _ *models.ApprovalRequest) error {
+		if !authz.HasPermission(checker.Role, permission) {
+			return authz.ErrPermissionDenied
+		}
+		return nil
+	}
+}
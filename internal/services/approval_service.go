@@ -0,0 +1,438 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/authz"
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrApprovalNotFound is returned when an approval request does not exist
+	ErrApprovalNotFound = errors.New("approval request not found")
+	// ErrApprovalNotPending is returned when an approval request was already decided
+	ErrApprovalNotPending = errors.New("approval request already decided")
+	// ErrApprovalExpired is returned when an approval request expired before a decision
+	ErrApprovalExpired = errors.New("approval request expired")
+	// ErrSelfApproval is returned when the maker or the affected user tries to decide a request
+	ErrSelfApproval = errors.New("own approval request")
+	// ErrUnknownApprovalType is returned for operations without a registered action
+	ErrUnknownApprovalType = errors.New("unknown approval type")
+)
+
+const (
+	// approvalSweepInterval is how often pending requests past their expiry are marked expired
+	// and interrupted executions are resumed
+	approvalSweepInterval = time.Minute
+	// approvalResumeAfter is how long an approved request may wait for its outcome before
+	// the sweep assumes its execution was interrupted and runs it again
+	approvalResumeAfter = 2 * time.Minute
+	// maxApprovalResumes caps the requests resumed per sweep
+	maxApprovalResumes = 100
+	// maxApprovalNotifications caps the approvers notified of a new request
+	maxApprovalNotifications = 100
+)
+
+// ApprovalAction is an operation that runs only after approval
+type ApprovalAction struct {
+	// Authorize returns nil if the checker may perform the operation themselves; a checker
+	// decides only what they could do without approval
+	Authorize func(ctx context.Context, checker authz.Subject, request *models.ApprovalRequest) error
+	// Execute runs the approved operation and returns the job it was queued as, or nil if it
+	// ran at once. Running it again for the same request must not repeat its effect.
+	Execute func(ctx context.Context, request *models.ApprovalRequest) (*uuid.UUID, error)
+}
+
+// ApprovalService stores operations requested by a maker until a second authorized user
+// approves or rejects them. Approved operations run immediately; requests left undecided
+// expire after the configured TTL.
+type ApprovalService struct {
+	notificationService interfaces.NotificationService
+	auditService        interfaces.AuditService
+	actions             map[models.ApprovalType]ApprovalAction
+	ttl                 time.Duration
+	logger              *zap.Logger
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewApprovalService creates a new ApprovalService running the given actions
+func NewApprovalService(
+	notificationService interfaces.NotificationService,
+	auditService interfaces.AuditService,
+	actions map[models.ApprovalType]ApprovalAction,
+	ttl time.Duration,
+	logger *zap.Logger,
+) *ApprovalService {
+	return &ApprovalService{
+		notificationService: notificationService,
+		auditService:        auditService,
+		actions:             actions,
+		ttl:                 ttl,
+		logger:              logger,
+		stop:                make(chan struct{}),
+		done:                make(chan struct{}),
+	}
+}
+
+// Submit stores an operation for approval and notifies the users who may approve it.
+// subjectID is the user the operation affects; payload is the operation's parameters.
+func (s *ApprovalService) Submit(ctx context.Context, makerID uuid.UUID, approvalType models.ApprovalType, subjectID uuid.UUID, payload interface{}, reason string) (*models.ApprovalRequest, error) {
+	if _, ok := s.actions[approvalType]; !ok {
+		return nil, ErrUnknownApprovalType
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode approval payload: %w", err)
+	}
+
+	request := &models.ApprovalRequest{
+		Type:      approvalType,
+		Payload:   string(data),
+		SubjectID: subjectID,
+		Reason:    reason,
+		Status:    models.ApprovalStatusPending,
+		MakerID:   makerID,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if err := database.GetDB().WithContext(ctx).Create(request).Error; err != nil {
+		return nil, fmt.Errorf("failed to create approval request: %w", err)
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, makerID, "APPROVAL_REQUESTED", "approval_request", request.ID.String(),
+			fmt.Sprintf("%s requested for user %s: %s", approvalType, subjectID, reason))
+	}
+	s.logger.Info("Approval requested",
+		zap.String("approval_id", request.ID.String()),
+		zap.String("approval_type", string(approvalType)),
+		zap.String("maker_id", makerID.String()),
+		zap.String("subject_id", subjectID.String()),
+		zap.String("type", "approval_requested"),
+	)
+
+	s.notifyApprovers(ctx, request)
+	return request, nil
+}
+
+// Approve approves a pending request and runs its operation. The request is returned with
+// status executed, or failed with the operation's error; a failed operation is not retried.
+// Money movements are queued as jobs; their outcome is tracked on the job in JobID.
+func (s *ApprovalService) Approve(ctx context.Context, checker authz.Subject, id uuid.UUID, note string) (*models.ApprovalRequest, error) {
+	request, action, err := s.decide(ctx, checker, id, models.ApprovalStatusApproved, note)
+	if err != nil {
+		return nil, err
+	}
+
+	// The operation must not be abandoned halfway because the approving client went away
+	return s.execute(context.WithoutCancel(ctx), request, action)
+}
+
+// execute runs the operation of an approved request and records its outcome. Operations are
+// idempotent per request, so a request whose execution was interrupted can run again; only
+// the first outcome recorded counts.
+func (s *ApprovalService) execute(ctx context.Context, request *models.ApprovalRequest, action ApprovalAction) (*models.ApprovalRequest, error) {
+	jobID, execErr := action.Execute(ctx, request)
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":      models.ApprovalStatusExecuted,
+		"executed_at": now,
+		"job_id":      jobID,
+	}
+	request.Status = models.ApprovalStatusExecuted
+	request.ExecutedAt = &now
+	request.JobID = jobID
+	if execErr != nil {
+		updates["status"] = models.ApprovalStatusFailed
+		updates["error"] = execErr.Error()
+		request.Status = models.ApprovalStatusFailed
+		request.Error = execErr.Error()
+	}
+	result := database.GetDB().WithContext(ctx).Model(&models.ApprovalRequest{}).
+		Where("id = ? AND status = ?", request.ID, models.ApprovalStatusApproved).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to record approval outcome: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// Another execution of the request recorded its outcome first
+		return s.GetRequest(ctx, request.ID)
+	}
+
+	auditAction := "APPROVAL_EXECUTED"
+	if execErr != nil {
+		auditAction = "APPROVAL_FAILED"
+		s.logger.Error("Approved operation failed",
+			zap.String("approval_id", request.ID.String()),
+			zap.String("approval_type", string(request.Type)),
+			zap.Error(execErr),
+			zap.String("type", "approval_execution_error"),
+		)
+	}
+	if s.auditService != nil && request.CheckerID != nil {
+		s.auditService.LogUserActivity(ctx, *request.CheckerID, auditAction, "approval_request", request.ID.String(),
+			fmt.Sprintf("%s approved, requested by %s", request.Type, request.MakerID))
+	}
+	s.notifyMaker(ctx, request)
+	return request, nil
+}
+
+// ResumeApproved runs again the operations of requests approved but without a recorded
+// outcome, e.g. because the process stopped between the decision and the execution, and
+// returns how many were resumed
+func (s *ApprovalService) ResumeApproved(ctx context.Context) (int, error) {
+	var requests []models.ApprovalRequest
+	if err := database.GetDB().WithContext(ctx).
+		Where("status = ? AND decided_at <= ?", models.ApprovalStatusApproved, time.Now().Add(-approvalResumeAfter)).
+		Order("decided_at").
+		Limit(maxApprovalResumes).
+		Find(&requests).Error; err != nil {
+		return 0, fmt.Errorf("failed to find interrupted approvals: %w", err)
+	}
+
+	resumed := 0
+	for i := range requests {
+		request := &requests[i]
+		action, ok := s.actions[request.Type]
+		if !ok {
+			continue
+		}
+		s.logger.Warn("Resuming interrupted approval",
+			zap.String("approval_id", request.ID.String()),
+			zap.String("approval_type", string(request.Type)),
+			zap.String("type", "approval_resumed"),
+		)
+		if _, err := s.execute(ctx, request, action); err != nil {
+			return resumed, err
+		}
+		resumed++
+	}
+	return resumed, nil
+}
+
+// Reject rejects a pending request; its operation never runs
+func (s *ApprovalService) Reject(ctx context.Context, checker authz.Subject, id uuid.UUID, note string) (*models.ApprovalRequest, error) {
+	request, _, err := s.decide(ctx, checker, id, models.ApprovalStatusRejected, note)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, checker.UserID, "APPROVAL_REJECTED", "approval_request", request.ID.String(),
+			fmt.Sprintf("%s rejected, requested by %s", request.Type, request.MakerID))
+	}
+	s.notifyMaker(ctx, request)
+	return request, nil
+}
+
+// GetRequest returns an approval request
+func (s *ApprovalService) GetRequest(ctx context.Context, id uuid.UUID) (*models.ApprovalRequest, error) {
+	var request models.ApprovalRequest
+	if err := database.GetDB().WithContext(ctx).Where("id = ?", id).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApprovalNotFound
+		}
+		return nil, fmt.Errorf("failed to get approval request: %w", err)
+	}
+	return &request, nil
+}
+
+// ListRequests returns approval requests, newest first, optionally filtered by status.
+// Pending requests past their expiry are not listed as pending.
+func (s *ApprovalService) ListRequests(ctx context.Context, status models.ApprovalStatus, limit, offset int) ([]models.ApprovalRequest, error) {
+	query := database.GetDB().WithContext(ctx).Model(&models.ApprovalRequest{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+		if status == models.ApprovalStatusPending {
+			query = query.Where("expires_at > ?", time.Now())
+		}
+	}
+
+	var requests []models.ApprovalRequest
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("failed to list approval requests: %w", err)
+	}
+	return requests, nil
+}
+
+// ExpireRequests marks pending requests past their expiry as expired and returns how many
+func (s *ApprovalService) ExpireRequests(ctx context.Context) (int64, error) {
+	result := database.GetDB().WithContext(ctx).Model(&models.ApprovalRequest{}).
+		Where("status = ? AND expires_at <= ?", models.ApprovalStatusPending, time.Now()).
+		Update("status", models.ApprovalStatusExpired)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to expire approval requests: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// Start periodically expires undecided requests and resumes interrupted executions until
+// Stop is called
+func (s *ApprovalService) Start() {
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(approvalSweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.sweep(context.Background())
+			}
+		}
+	}()
+}
+
+// sweep expires undecided requests and resumes interrupted executions
+func (s *ApprovalService) sweep(ctx context.Context) {
+	expired, err := s.ExpireRequests(ctx)
+	if err != nil {
+		s.logger.Error("Approval expiry failed",
+			zap.Error(err),
+			zap.String("type", "approval_error"),
+		)
+	} else if expired > 0 {
+		s.logger.Info("Approval requests expired",
+			zap.Int64("count", expired),
+			zap.String("type", "approval_expired"),
+		)
+	}
+
+	if _, err := s.ResumeApproved(ctx); err != nil {
+		s.logger.Error("Resuming approvals failed",
+			zap.Error(err),
+			zap.String("type", "approval_error"),
+		)
+	}
+}
+
+// Stop ends the sweep loop started by Start
+func (s *ApprovalService) Stop() error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+	return nil
+}
+
+// decide records the checker's decision on a pending request. The checker must hold
+// authz.ApprovalsDecide, be allowed to perform the operation and be neither its maker nor
+// the user it affects. The conditional update makes concurrent decisions race safely.
+func (s *ApprovalService) decide(ctx context.Context, checker authz.Subject, id uuid.UUID, status models.ApprovalStatus, note string) (*models.ApprovalRequest, ApprovalAction, error) {
+	request, err := s.GetRequest(ctx, id)
+	if err != nil {
+		return nil, ApprovalAction{}, err
+	}
+
+	now := time.Now()
+	if request.Status != models.ApprovalStatusPending {
+		return nil, ApprovalAction{}, ErrApprovalNotPending
+	}
+	if !request.IsPending(now) {
+		return nil, ApprovalAction{}, ErrApprovalExpired
+	}
+	if checker.UserID == request.MakerID || checker.UserID == request.SubjectID {
+		return nil, ApprovalAction{}, ErrSelfApproval
+	}
+	if !authz.HasPermission(checker.Role, authz.ApprovalsDecide) {
+		return nil, ApprovalAction{}, authz.ErrPermissionDenied
+	}
+	action, ok := s.actions[request.Type]
+	if !ok {
+		return nil, ApprovalAction{}, ErrUnknownApprovalType
+	}
+	if err := action.Authorize(ctx, checker, request); err != nil {
+		return nil, ApprovalAction{}, err
+	}
+
+	result := database.GetDB().WithContext(ctx).Model(&models.ApprovalRequest{}).
+		Where("id = ? AND status = ? AND expires_at > ?", id, models.ApprovalStatusPending, now).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"checker_id":    checker.UserID,
+			"decision_note": note,
+			"decided_at":    now,
+		})
+	if result.Error != nil {
+		return nil, ApprovalAction{}, fmt.Errorf("failed to record approval decision: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ApprovalAction{}, ErrApprovalNotPending
+	}
+
+	request.Status = status
+	request.CheckerID = &checker.UserID
+	request.DecisionNote = note
+	request.DecidedAt = &now
+	return request, action, nil
+}
+
+// notifyApprovers tells the users who may decide the request, other than its maker and
+// subject, that it awaits approval. Notification failures do not fail the request.
+func (s *ApprovalService) notifyApprovers(ctx context.Context, request *models.ApprovalRequest) {
+	if s.notificationService == nil {
+		return
+	}
+
+	var approverIDs []uuid.UUID
+	if err := database.GetDB().WithContext(ctx).Model(&models.User{}).
+		Where("role IN ? AND id NOT IN ?", authz.RolesWith(authz.ApprovalsDecide), []uuid.UUID{request.MakerID, request.SubjectID}).
+		Limit(maxApprovalNotifications).
+		Pluck("id", &approverIDs).Error; err != nil {
+		s.logger.Warn("Failed to find approvers",
+			zap.String("approval_id", request.ID.String()),
+			zap.Error(err),
+			zap.String("type", "approval_notification_error"),
+		)
+		return
+	}
+
+	message := fmt.Sprintf("%s işlemi onayınızı bekliyor: %s", request.Type, request.ID)
+	for _, approverID := range approverIDs {
+		if err := s.notificationService.SendPushNotification(ctx, approverID, "Onay bekleyen işlem", message); err != nil {
+			s.logger.Warn("Failed to notify approver",
+				zap.String("approval_id", request.ID.String()),
+				zap.String("user_id", approverID.String()),
+				zap.Error(err),
+				zap.String("type", "approval_notification_error"),
+			)
+		}
+	}
+}
+
+// notifyMaker tells the maker how their request was decided
+func (s *ApprovalService) notifyMaker(ctx context.Context, request *models.ApprovalRequest) {
+	if s.notificationService == nil {
+		return
+	}
+
+	message := fmt.Sprintf("%s talebiniz sonuçlandı: %s", request.Type, request.Status)
+	if err := s.notificationService.SendPushNotification(ctx, request.MakerID, "Onay talebi sonuçlandı", message); err != nil {
+		s.logger.Warn("Failed to notify maker",
+			zap.String("approval_id", request.ID.String()),
+			zap.Error(err),
+			zap.String("type", "approval_notification_error"),
+		)
+	}
+}
+
+// decodeApprovalPayload decodes a request's payload into the operation's parameters
+func decodeApprovalPayload(request *models.ApprovalRequest, payload interface{}) error {
+	if err := json.Unmarshal([]byte(request.Payload), payload); err != nil {
+		return fmt.Errorf("invalid %s approval payload: %w", request.Type, err)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/barannkoca/banking-backend/internal/database"
 	"github.com/barannkoca/banking-backend/internal/interfaces"
 	"github.com/barannkoca/banking-backend/internal/models"
 	"github.com/google/uuid"
@@ -139,6 +140,30 @@ func (bs *BalanceService) SafeUpdateBalance(ctx context.Context, accountID uuid.
 	return nil
 }
 
+// SetAccountFrozen freezes or unfreezes an account. Frozen accounts keep receiving money
+// but cannot send it. Returns ErrUserNotFound if the account has no balance.
+func (bs *BalanceService) SetAccountFrozen(ctx context.Context, accountID uuid.UUID, frozen bool) error {
+	result := database.GetDB().WithContext(ctx).Model(&models.Balance{}).
+		Where("user_id = ?", accountID).
+		Update("frozen", frozen)
+	if result.Error != nil {
+		return fmt.Errorf("hesap dondurma durumu güncellenemedi: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	if bs.auditService != nil {
+		action := "ACCOUNT_FROZEN"
+		if !frozen {
+			action = "ACCOUNT_UNFROZEN"
+		}
+		bs.auditService.LogUserActivity(ctx, accountID, action, "balance", accountID.String(),
+			fmt.Sprintf("Hesap dondurma durumu: %t", frozen))
+	}
+	return nil
+}
+
 // GetBalanceHistory retrieves balance history for a given account ID
 func (bs *BalanceService) GetBalanceHistory(ctx context.Context, accountID uuid.UUID) ([]models.BalanceHistory, error) {
 	// This would typically query a balance_history table
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/processing"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrBatchNotFound is returned when a batch does not exist
+	ErrBatchNotFound = errors.New("batch not found")
+	// ErrBatchTooLarge is returned when a batch exceeds the configured item limit
+	ErrBatchTooLarge = errors.New("batch exceeds maximum item count")
+	// ErrInsufficientFunds is returned when the sender cannot cover the batch total
+	ErrInsufficientFunds = errors.New("insufficient balance for batch total")
+)
+
+// BatchValidationError is returned when an all-or-nothing batch contains invalid items.
+// Items carries every item of the batch with its validation outcome.
+type BatchValidationError struct {
+	Items []models.BatchItem
+}
+
+func (e *BatchValidationError) Error() string {
+	rejected := 0
+	for _, item := range e.Items {
+		if item.Status == models.BatchItemStatusRejected {
+			rejected++
+		}
+	}
+	return fmt.Sprintf("batch validation failed: %d of %d items rejected", rejected, len(e.Items))
+}
+
+// BatchService validates and executes batch transfers, e.g. payroll runs
+type BatchService struct {
+	batchRepo          interfaces.BatchRepository
+	userRepo           interfaces.UserRepository
+	transactionService *TransactionService
+	jobService         *JobService
+	auditService       interfaces.AuditService
+	workerPool         *processing.WorkerPool
+	maxItems           int
+	logger             *zap.Logger
+}
+
+// NewBatchService creates a new BatchService; maxItems bounds the number of items per batch
+func NewBatchService(
+	batchRepo interfaces.BatchRepository,
+	userRepo interfaces.UserRepository,
+	transactionService *TransactionService,
+	jobService *JobService,
+	auditService interfaces.AuditService,
+	workerPool *processing.WorkerPool,
+	maxItems int,
+	logger *zap.Logger,
+) *BatchService {
+	return &BatchService{
+		batchRepo:          batchRepo,
+		userRepo:           userRepo,
+		transactionService: transactionService,
+		jobService:         jobService,
+		auditService:       auditService,
+		workerPool:         workerPool,
+		maxItems:           maxItems,
+		logger:             logger,
+	}
+}
+
+// MaxItems returns the maximum number of items accepted per batch
+func (s *BatchService) MaxItems() int {
+	return s.maxItems
+}
+
+// SubmitBatch validates every item and the total funds upfront, then executes the batch.
+// All-or-nothing batches run synchronously in one database transaction and are rejected
+// as a whole if any item is invalid. Best-effort batches reject invalid items and queue
+// the rest on the worker pool; their progress is picked up by GetBatch.
+func (s *BatchService) SubmitBatch(ctx context.Context, userID uuid.UUID, req *models.BatchTransferRequest) (*models.Batch, error) {
+	if len(req.Items) > s.maxItems {
+		return nil, fmt.Errorf("%w: %d > %d", ErrBatchTooLarge, len(req.Items), s.maxItems)
+	}
+
+	batch := &models.Batch{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Mode:      req.Mode,
+		Status:    models.BatchStatusProcessing,
+		Reference: req.Reference,
+		ItemCount: len(req.Items),
+		Items:     make([]models.BatchItem, len(req.Items)),
+	}
+
+	// 1. Validate items
+	var acceptedTotal float64
+	accepted := make([]*models.BatchItem, 0, len(req.Items))
+	for i, reqItem := range req.Items {
+		item := &batch.Items[i]
+		*item = models.BatchItem{
+			ID:        uuid.New(),
+			BatchID:   batch.ID,
+			Position:  i + 1,
+			ToUserID:  reqItem.ToUserID,
+			Amount:    reqItem.Amount,
+			Reference: strings.TrimSpace(reqItem.Reference),
+			Status:    models.BatchItemStatusPending,
+		}
+		batch.TotalAmount += item.Amount
+
+		if err := s.validateItem(ctx, userID, item); err != nil {
+			item.Status = models.BatchItemStatusRejected
+			item.Error = err.Error()
+			continue
+		}
+		accepted = append(accepted, item)
+		acceptedTotal += item.Amount
+	}
+
+	if req.Mode == models.BatchModeAllOrNothing && len(accepted) < len(batch.Items) {
+		return nil, &BatchValidationError{Items: batch.Items}
+	}
+
+	// 2. Validate total funds upfront
+	if len(accepted) > 0 {
+		canPerform, err := s.transactionService.CanPerformTransaction(ctx, userID, acceptedTotal)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check balance: %w", err)
+		}
+		if !canPerform {
+			return nil, fmt.Errorf("%w: required=%.2f", ErrInsufficientFunds, acceptedTotal)
+		}
+	}
+
+	// Reject before persisting so saturated submissions leave no batch behind
+	if req.Mode == models.BatchModeBestEffort && len(accepted) > 0 && s.workerPool.IsSaturated() {
+		return nil, processing.ErrQueueFull
+	}
+
+	if err := s.batchRepo.Create(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to persist batch: %w", err)
+	}
+
+	// 3. Execute
+	if req.Mode == models.BatchModeAllOrNothing {
+		s.executeAllOrNothing(ctx, userID, accepted)
+	} else {
+		s.submitBestEffort(ctx, userID, accepted)
+	}
+
+	if err := s.batchRepo.UpdateItems(ctx, batch.Items); err != nil {
+		s.logger.Error("Failed to update batch items",
+			zap.String("batch_id", batch.ID.String()),
+			zap.Error(err))
+	}
+	s.finalize(ctx, batch)
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "BATCH_SUBMITTED", "batch", batch.ID.String(),
+			fmt.Sprintf("%s batch submitted (Items: %d, Accepted: %d, Total: %f)", batch.Mode, batch.ItemCount, len(accepted), acceptedTotal))
+	}
+
+	s.logger.Info("Batch submitted",
+		zap.String("batch_id", batch.ID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("mode", string(batch.Mode)),
+		zap.Int("item_count", batch.ItemCount),
+		zap.Int("accepted_count", len(accepted)),
+		zap.String("status", string(batch.Status)))
+
+	return batch, nil
+}
+
+// GetBatch retrieves a batch and refreshes the status of its queued items from their jobs
+func (s *BatchService) GetBatch(ctx context.Context, id uuid.UUID) (*models.Batch, error) {
+	batch, err := s.batchRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBatchNotFound, err)
+	}
+	if batch.IsFinished() {
+		return batch, nil
+	}
+
+	changed := make([]models.BatchItem, 0)
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		if item.IsFinished() || item.Job == nil {
+			continue
+		}
+
+		status := item.Status
+		switch item.Job.Status {
+		case models.JobStatusProcessing:
+			status = models.BatchItemStatusProcessing
+		case models.JobStatusCompleted:
+			status = models.BatchItemStatusCompleted
+			item.TransactionID = item.Job.TransactionID
+		case models.JobStatusFailed:
+			status = models.BatchItemStatusFailed
+			item.Error = item.Job.Error
+		}
+		if status != item.Status {
+			item.Status = status
+			changed = append(changed, *item)
+		}
+	}
+
+	if len(changed) > 0 {
+		if err := s.batchRepo.UpdateItems(ctx, changed); err != nil {
+			return nil, fmt.Errorf("failed to update batch items: %w", err)
+		}
+	}
+	s.finalize(ctx, batch)
+
+	return batch, nil
+}
+
+// validateItem checks a single item before anything is executed
+func (s *BatchService) validateItem(ctx context.Context, userID uuid.UUID, item *models.BatchItem) error {
+	if item.Amount <= 0 {
+		return fmt.Errorf("transfer amount must be positive")
+	}
+	if item.ToUserID == userID {
+		return fmt.Errorf("cannot transfer to same account")
+	}
+
+	recipient, err := s.userRepo.GetByID(ctx, item.ToUserID)
+	if err != nil {
+		return fmt.Errorf("recipient not found")
+	}
+	if !recipient.IsActive() {
+		return fmt.Errorf("recipient account is not active")
+	}
+	return nil
+}
+
+// executeAllOrNothing applies every accepted item in one database transaction
+func (s *BatchService) executeAllOrNothing(ctx context.Context, userID uuid.UUID, items []*models.BatchItem) {
+	transactions, err := s.transactionService.TransferBatch(ctx, userID, items)
+	for i, item := range items {
+		if err != nil {
+			item.Status = models.BatchItemStatusFailed
+			item.Error = err.Error()
+			continue
+		}
+		item.Status = models.BatchItemStatusCompleted
+		item.TransactionID = &transactions[i].ID
+	}
+}
+
+// submitBestEffort queues every accepted item as a batch-priority transfer job
+func (s *BatchService) submitBestEffort(ctx context.Context, userID uuid.UUID, items []*models.BatchItem) {
+	jobs := transferJobs(userID, items)
+	for i, err := range s.jobService.SubmitJobs(ctx, jobs) {
+		item := items[i]
+		if err != nil {
+			item.Status = models.BatchItemStatusFailed
+			item.Error = err.Error()
+			continue
+		}
+		item.Status = models.BatchItemStatusQueued
+		item.JobID = &jobs[i].ID
+	}
+}
+
+// transferJobs builds one transfer job per batch item, carrying the item's reference
+func transferJobs(userID uuid.UUID, items []*models.BatchItem) []*models.Job {
+	jobs := make([]*models.Job, len(items))
+	for i, item := range items {
+		fromAccountID := userID
+		toAccountID := item.ToUserID
+		jobs[i] = &models.Job{
+			ID:            uuid.New(),
+			UserID:        userID,
+			Type:          models.JobTypeTransfer,
+			FromAccountID: &fromAccountID,
+			ToAccountID:   &toAccountID,
+			Amount:        item.Amount,
+			Reference:     item.Reference,
+			Priority:      models.JobPriorityBatch,
+		}
+	}
+	return jobs
+}
+
+// finalize derives and persists the final batch status once every item is finished
+func (s *BatchService) finalize(ctx context.Context, batch *models.Batch) {
+	completed := 0
+	for i := range batch.Items {
+		if !batch.Items[i].IsFinished() {
+			return
+		}
+		if batch.Items[i].Status == models.BatchItemStatusCompleted {
+			completed++
+		}
+	}
+
+	switch {
+	case completed == len(batch.Items):
+		batch.Status = models.BatchStatusCompleted
+	case completed == 0:
+		batch.Status = models.BatchStatusFailed
+	default:
+		batch.Status = models.BatchStatusPartiallyCompleted
+	}
+	now := time.Now()
+	batch.CompletedAt = &now
+
+	if err := s.batchRepo.UpdateStatus(ctx, batch.ID, batch.Status, batch.CompletedAt); err != nil {
+		s.logger.Error("Failed to update batch status",
+			zap.String("batch_id", batch.ID.String()),
+			zap.Error(err))
+	}
+}
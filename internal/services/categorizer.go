@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// categoryKeywords are the built-in reference keywords, matched case-insensitively after
+// the user's own rules
+var categoryKeywords = map[models.TransactionCategory][]string{
+	models.CategorySalary:        {"maaş", "maas", "salary", "payroll", "bordro"},
+	models.CategoryGroceries:     {"market", "migros", "carrefour", "a101", "bim", "şok", "grocery", "supermarket"},
+	models.CategoryDining:        {"restoran", "restaurant", "cafe", "kafe", "kahve", "coffee", "yemeksepeti", "lokanta"},
+	models.CategoryTransport:     {"taksi", "taxi", "uber", "istanbulkart", "metro", "otobüs", "akaryakıt", "benzin", "fuel", "otopark", "parking"},
+	models.CategoryUtilities:     {"fatura", "elektrik", "electricity", "doğalgaz", "dogalgaz", "water bill", "internet", "turkcell", "vodafone", "telekom"},
+	models.CategoryHousing:       {"kira", "rent", "aidat"},
+	models.CategoryShopping:      {"trendyol", "hepsiburada", "amazon", "n11", "alışveriş", "shopping"},
+	models.CategoryEntertainment: {"netflix", "spotify", "sinema", "cinema", "konser", "concert", "steam"},
+	models.CategoryHealth:        {"eczane", "pharmacy", "hastane", "hospital", "doktor", "doctor", "klinik"},
+	models.CategoryEducation:     {"okul", "school", "kurs", "course", "üniversite", "university", "tuition"},
+}
+
+// Categorizer assigns categories to transactions from the paying user's rules and the
+// built-in keywords. Precedence: counterparty rules, the user's keyword rules, built-in
+// keywords, then a default derived from the transaction type.
+type Categorizer struct {
+	ruleRepo interfaces.CategoryRuleRepository
+	logger   *zap.Logger
+}
+
+// NewCategorizer creates a new Categorizer
+func NewCategorizer(ruleRepo interfaces.CategoryRuleRepository, logger *zap.Logger) *Categorizer {
+	return &Categorizer{
+		ruleRepo: ruleRepo,
+		logger:   logger,
+	}
+}
+
+// Categorize returns the category of a transaction using the owner's current rules
+func (c *Categorizer) Categorize(ctx context.Context, transaction *models.Transaction) models.TransactionCategory {
+	if transaction.Type == models.TransactionTypeDeposit || transaction.Type == models.TransactionTypeWithdraw {
+		return c.Apply(transaction, nil)
+	}
+	return c.Apply(transaction, c.RulesFor(ctx, categoryOwner(transaction)))
+}
+
+// RulesFor loads a user's rules; lookup failures fall back to the built-in keywords only
+func (c *Categorizer) RulesFor(ctx context.Context, userID *uuid.UUID) []*models.CategoryRule {
+	if userID == nil || c.ruleRepo == nil {
+		return nil
+	}
+	rules, err := c.ruleRepo.ListByUser(ctx, *userID)
+	if err != nil {
+		c.logger.Warn("Failed to load category rules, using built-in keywords",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		return nil
+	}
+	return rules
+}
+
+// Apply categorizes a transaction with the given rules of its owner
+func (c *Categorizer) Apply(transaction *models.Transaction, rules []*models.CategoryRule) models.TransactionCategory {
+	reference := strings.ToLower(transaction.Reference)
+
+	switch transaction.Type {
+	case models.TransactionTypeDeposit:
+		if matchesAny(reference, categoryKeywords[models.CategorySalary]) {
+			return models.CategorySalary
+		}
+		return models.CategoryIncome
+	case models.TransactionTypeWithdraw:
+		return models.CategoryCash
+	}
+
+	// 1. Counterparty rules
+	if transaction.ToUserID != nil {
+		for _, rule := range rules {
+			if rule.CounterpartyID != nil && *rule.CounterpartyID == *transaction.ToUserID {
+				return rule.Category
+			}
+		}
+	}
+
+	// 2. The user's keyword rules, longest keyword first
+	var best *models.CategoryRule
+	for _, rule := range rules {
+		keyword := strings.ToLower(rule.Keyword)
+		if rule.CounterpartyID != nil || keyword == "" || !strings.Contains(reference, keyword) {
+			continue
+		}
+		if best == nil || len(rule.Keyword) > len(best.Keyword) {
+			best = rule
+		}
+	}
+	if best != nil {
+		return best.Category
+	}
+
+	// 3. Built-in keywords, in display order so overlapping keywords resolve deterministically
+	for _, category := range models.TransactionCategories {
+		if matchesAny(reference, categoryKeywords[category]) {
+			return category
+		}
+	}
+
+	if transaction.Type == models.TransactionTypeTransfer {
+		return models.CategoryTransfer
+	}
+	return models.CategoryOther
+}
+
+// categoryOwner returns the user whose rules categorize the transaction: the payer, or
+// the receiver when there is no payer
+func categoryOwner(transaction *models.Transaction) *uuid.UUID {
+	if transaction.FromUserID != nil {
+		return transaction.FromUserID
+	}
+	return transaction.ToUserID
+}
+
+// matchesAny reports whether text contains any of the keywords as a whole word
+func matchesAny(text string, keywords []string) bool {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !(r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+	for _, keyword := range keywords {
+		if strings.Contains(keyword, " ") {
+			if strings.Contains(text, keyword) {
+				return true
+			}
+			continue
+		}
+		for _, word := range words {
+			if word == keyword {
+				return true
+			}
+		}
+	}
+	return false
+}
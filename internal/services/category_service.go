@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCategoryRuleNotFound is returned when a categorization rule does not exist or belongs to another user
+	ErrCategoryRuleNotFound = errors.New("category rule not found")
+	// ErrInvalidCategoryRule is returned when a rule has an unknown category or no match condition
+	ErrInvalidCategoryRule = errors.New("invalid category rule")
+	// ErrTransactionNotFound is returned when a transaction does not exist
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrNotTransactionOwner is returned when a user changes the category of someone else's transaction
+	ErrNotTransactionOwner = errors.New("transaction belongs to another user")
+)
+
+// recategorizeBatchSize is the number of transactions recategorized per query
+const recategorizeBatchSize = 500
+
+// CategoryService manages user categorization rules and per-transaction category overrides
+type CategoryService struct {
+	ruleRepo     interfaces.CategoryRuleRepository
+	userRepo     interfaces.UserRepository
+	categorizer  *Categorizer
+	auditService interfaces.AuditService
+	logger       *zap.Logger
+}
+
+// NewCategoryService creates a new CategoryService
+func NewCategoryService(
+	ruleRepo interfaces.CategoryRuleRepository,
+	userRepo interfaces.UserRepository,
+	categorizer *Categorizer,
+	auditService interfaces.AuditService,
+	logger *zap.Logger,
+) *CategoryService {
+	return &CategoryService{
+		ruleRepo:     ruleRepo,
+		userRepo:     userRepo,
+		categorizer:  categorizer,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// ListRules returns the categorization rules of a user
+func (s *CategoryService) ListRules(ctx context.Context, userID uuid.UUID) ([]*models.CategoryRule, error) {
+	rules, err := s.ruleRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list category rules: %w", err)
+	}
+	return rules, nil
+}
+
+// CreateRule adds a rule and recategorizes the user's transactions with it
+func (s *CategoryService) CreateRule(ctx context.Context, userID uuid.UUID, req *models.CategoryRuleRequest) (*models.CategoryRule, int64, error) {
+	rule := &models.CategoryRule{UserID: userID}
+	if err := s.applyRuleRequest(ctx, rule, req); err != nil {
+		return nil, 0, err
+	}
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, 0, fmt.Errorf("failed to create category rule: %w", err)
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "CATEGORY_RULE_CREATED", "category_rule", rule.ID.String(),
+			fmt.Sprintf("Category rule created (Category: %s)", rule.Category))
+	}
+
+	updated, err := s.Recategorize(ctx, userID)
+	return rule, updated, err
+}
+
+// UpdateRule changes a rule of the user and recategorizes the user's transactions
+func (s *CategoryService) UpdateRule(ctx context.Context, userID, ruleID uuid.UUID, req *models.CategoryRuleRequest) (*models.CategoryRule, int64, error) {
+	rule, err := s.ownedRule(ctx, userID, ruleID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.applyRuleRequest(ctx, rule, req); err != nil {
+		return nil, 0, err
+	}
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, 0, fmt.Errorf("failed to update category rule: %w", err)
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "CATEGORY_RULE_UPDATED", "category_rule", rule.ID.String(),
+			fmt.Sprintf("Category rule updated (Category: %s)", rule.Category))
+	}
+
+	updated, err := s.Recategorize(ctx, userID)
+	return rule, updated, err
+}
+
+// DeleteRule removes a rule of the user and recategorizes the user's transactions without it
+func (s *CategoryService) DeleteRule(ctx context.Context, userID, ruleID uuid.UUID) (int64, error) {
+	rule, err := s.ownedRule(ctx, userID, ruleID)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.ruleRepo.Delete(ctx, rule.ID); err != nil {
+		return 0, fmt.Errorf("failed to delete category rule: %w", err)
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "CATEGORY_RULE_DELETED", "category_rule", rule.ID.String(), "Category rule deleted")
+	}
+
+	return s.Recategorize(ctx, userID)
+}
+
+// SetTransactionCategory overrides the category of a transaction owned by the user. The
+// override survives later recategorization.
+func (s *CategoryService) SetTransactionCategory(ctx context.Context, userID, transactionID uuid.UUID, category models.TransactionCategory) (*models.Transaction, error) {
+	if !models.IsValidTransactionCategory(category) {
+		return nil, fmt.Errorf("%w: unknown category %q", ErrInvalidCategoryRule, category)
+	}
+
+	var transaction models.Transaction
+	if err := database.GetDB().WithContext(ctx).Where("id = ?", transactionID).First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if owner := categoryOwner(&transaction); owner == nil || *owner != userID {
+		return nil, ErrNotTransactionOwner
+	}
+
+	err := database.GetDB().WithContext(ctx).Model(&models.Transaction{}).
+		Where("id = ?", transactionID).
+		Updates(map[string]interface{}{"category": category, "category_overridden": true}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to update transaction category: %w", err)
+	}
+	transaction.Category = category
+	transaction.CategoryOverridden = true
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "TRANSACTION_CATEGORY_OVERRIDDEN", "transaction", transactionID.String(),
+			fmt.Sprintf("Transaction category set to %s", category))
+	}
+	return &transaction, nil
+}
+
+// Recategorize re-applies the user's rules to every transaction the user pays for, keeping
+// manual overrides, and returns the number of transactions whose category changed
+func (s *CategoryService) Recategorize(ctx context.Context, userID uuid.UUID) (int64, error) {
+	rules := s.categorizer.RulesFor(ctx, &userID)
+
+	var updated int64
+	var transactions []*models.Transaction
+	err := database.GetDB().WithContext(ctx).
+		Where("category_overridden = ? AND (from_user_id = ? OR (from_user_id IS NULL AND to_user_id = ?))", false, userID, userID).
+		FindInBatches(&transactions, recategorizeBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, transaction := range transactions {
+				category := s.categorizer.Apply(transaction, rules)
+				if category == transaction.Category {
+					continue
+				}
+				if err := tx.Model(&models.Transaction{}).Where("id = ?", transaction.ID).
+					Update("category", category).Error; err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return updated, fmt.Errorf("failed to recategorize transactions: %w", err)
+	}
+
+	s.logger.Info("Transactions recategorized",
+		zap.String("user_id", userID.String()),
+		zap.Int64("updated", updated))
+	return updated, nil
+}
+
+// Backfill categorizes the transactions recorded without a category, such as those
+// created before categorization existed, and returns the number it updated
+func (s *CategoryService) Backfill(ctx context.Context) (int64, error) {
+	rulesByOwner := make(map[uuid.UUID][]*models.CategoryRule)
+
+	var updated int64
+	var transactions []*models.Transaction
+	err := database.GetDB().WithContext(ctx).
+		Where("category_overridden = ? AND (category IS NULL OR category = '')", false).
+		FindInBatches(&transactions, recategorizeBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, transaction := range transactions {
+				// Like Categorize, deposits and withdrawals ignore the owner's rules
+				var rules []*models.CategoryRule
+				owner := categoryOwner(transaction)
+				if owner != nil && transaction.Type != models.TransactionTypeDeposit && transaction.Type != models.TransactionTypeWithdraw {
+					cached, ok := rulesByOwner[*owner]
+					if !ok {
+						cached = s.categorizer.RulesFor(ctx, owner)
+						rulesByOwner[*owner] = cached
+					}
+					rules = cached
+				}
+
+				// A category set meanwhile, e.g. by a user override, is kept
+				result := tx.Model(&models.Transaction{}).
+					Where("id = ? AND category_overridden = ? AND (category IS NULL OR category = '')", transaction.ID, false).
+					Update("category", s.categorizer.Apply(transaction, rules))
+				if result.Error != nil {
+					return result.Error
+				}
+				updated += result.RowsAffected
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return updated, fmt.Errorf("failed to backfill transaction categories: %w", err)
+	}
+
+	s.logger.Info("Transaction categories backfilled",
+		zap.Int64("updated", updated))
+	return updated, nil
+}
+
+// ownedRule loads a rule and hides rules of other users as not found
+func (s *CategoryService) ownedRule(ctx context.Context, userID, ruleID uuid.UUID) (*models.CategoryRule, error) {
+	rule, err := s.ruleRepo.FindByID(ctx, ruleID)
+	if err != nil || rule.UserID != userID {
+		return nil, ErrCategoryRuleNotFound
+	}
+	return rule, nil
+}
+
+// applyRuleRequest validates a rule request and copies it onto the rule
+func (s *CategoryService) applyRuleRequest(ctx context.Context, rule *models.CategoryRule, req *models.CategoryRuleRequest) error {
+	keyword := strings.TrimSpace(req.Keyword)
+	if !models.IsValidTransactionCategory(req.Category) {
+		return fmt.Errorf("%w: unknown category %q", ErrInvalidCategoryRule, req.Category)
+	}
+	if (req.CounterpartyID == nil) == (keyword == "") {
+		return fmt.Errorf("%w: exactly one of counterparty_id and keyword is required", ErrInvalidCategoryRule)
+	}
+	if req.CounterpartyID != nil {
+		if *req.CounterpartyID == rule.UserID {
+			return fmt.Errorf("%w: counterparty cannot be yourself", ErrInvalidCategoryRule)
+		}
+		if _, err := s.userRepo.GetByID(ctx, *req.CounterpartyID); err != nil {
+			return fmt.Errorf("%w: counterparty not found", ErrInvalidCategoryRule)
+		}
+	}
+
+	rule.CounterpartyID = req.CounterpartyID
+	rule.Keyword = keyword
+	rule.Category = req.Category
+	return nil
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/processing"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrDeadLetterNotFound is returned when a dead-lettered job does not exist
+	ErrDeadLetterNotFound = errors.New("dead-letter job not found")
+	// ErrDeadLetterResolved is returned when a dead-lettered job was already replayed or discarded
+	ErrDeadLetterResolved = errors.New("dead-letter job already resolved")
+)
+
+// DeadLetterService lets administrators inspect, edit, replay and discard jobs that exhausted their retries
+type DeadLetterService struct {
+	deadLetterRepo interfaces.DeadLetterRepository
+	jobRepo        interfaces.JobRepository
+	auditService   interfaces.AuditService
+	jobServices    processing.JobServices
+	workerPool     *processing.WorkerPool
+	approvals      *ApprovalService
+	logger         *zap.Logger
+}
+
+// NewDeadLetterService creates a new DeadLetterService
+func NewDeadLetterService(
+	deadLetterRepo interfaces.DeadLetterRepository,
+	jobRepo interfaces.JobRepository,
+	auditService interfaces.AuditService,
+	jobServices processing.JobServices,
+	workerPool *processing.WorkerPool,
+	logger *zap.Logger,
+) *DeadLetterService {
+	return &DeadLetterService{
+		deadLetterRepo: deadLetterRepo,
+		jobRepo:        jobRepo,
+		auditService:   auditService,
+		jobServices:    jobServices,
+		workerPool:     workerPool,
+		logger:         logger,
+	}
+}
+
+// ListDeadLetters returns dead-lettered jobs with the total count; an empty status matches all
+func (s *DeadLetterService) ListDeadLetters(ctx context.Context, status models.DeadLetterStatus, limit, offset int) ([]*models.DeadLetterJob, int64, error) {
+	deadLetters, err := s.deadLetterRepo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list dead-letter jobs: %w", err)
+	}
+	total, err := s.deadLetterRepo.Count(ctx, status)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count dead-letter jobs: %w", err)
+	}
+	return deadLetters, total, nil
+}
+
+// GetDeadLetter retrieves a dead-lettered job and audits the inspection
+func (s *DeadLetterService) GetDeadLetter(ctx context.Context, id, adminID uuid.UUID) (*models.DeadLetterJob, error) {
+	deadLetter, err := s.deadLetterRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDeadLetterNotFound, err)
+	}
+
+	s.audit(ctx, adminID, "DEAD_LETTER_INSPECTED", deadLetter, "Dead-letter job inspected")
+	return deadLetter, nil
+}
+
+// SetApprovalService sets the approvals that edits of accounts or amounts are submitted to.
+// The approval service runs DeadLetterEditAction, so it is created after this service.
+func (s *DeadLetterService) SetApprovalService(approvals *ApprovalService) {
+	s.approvals = approvals
+}
+
+// UpdateDeadLetter edits a pending dead-lettered job before it is replayed. Only the retry
+// budget is changed at once: edits of the accounts or the amount decide where money moves,
+// so they are submitted for a second user's approval and the approval request is returned
+// instead. Once approved, DeadLetterEditAction applies the edit and replays the job.
+func (s *DeadLetterService) UpdateDeadLetter(ctx context.Context, id, adminID uuid.UUID, req *models.UpdateDeadLetterJobRequest) (*models.DeadLetterJob, *models.ApprovalRequest, error) {
+	deadLetter, err := s.deadLetterRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrDeadLetterNotFound, err)
+	}
+	if !deadLetter.IsPending() {
+		return nil, nil, ErrDeadLetterResolved
+	}
+
+	edited := *deadLetter
+	if req.FromAccountID != nil {
+		edited.FromAccountID = req.FromAccountID
+	}
+	if req.ToAccountID != nil {
+		edited.ToAccountID = req.ToAccountID
+	}
+	if req.Amount != nil {
+		edited.Amount = *req.Amount
+	}
+	if req.MaxRetries != nil {
+		edited.MaxRetries = *req.MaxRetries
+	}
+
+	if err := validateDeadLetterAccounts(&edited); err != nil {
+		return nil, nil, err
+	}
+
+	if !movesMoneyDifferently(deadLetter, &edited) {
+		updated, err := s.applyEdit(ctx, adminID, deadLetter, &edited)
+		return updated, nil, err
+	}
+
+	if s.approvals == nil {
+		return nil, nil, ErrUnknownApprovalType
+	}
+	reason := req.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("Dead-letter %s işinin hesap veya tutar düzenlemesi", deadLetter.TransactionType)
+	}
+	request, err := s.approvals.Submit(ctx, adminID, models.ApprovalTypeDeadLetterEdit, deadLetterSubject(&edited),
+		models.DeadLetterEditPayload{
+			DeadLetterID:    edited.ID,
+			TransactionType: edited.TransactionType,
+			FromAccountID:   edited.FromAccountID,
+			ToAccountID:     edited.ToAccountID,
+			Amount:          edited.Amount,
+			MaxRetries:      edited.MaxRetries,
+		}, reason)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.audit(ctx, adminID, "DEAD_LETTER_EDIT_REQUESTED", deadLetter,
+		fmt.Sprintf("Dead-letter job edit awaiting approval %s (%s -> %s)", request.ID, describeDeadLetter(deadLetter), describeDeadLetter(&edited)))
+
+	return deadLetter, request, nil
+}
+
+// ReplayApprovedEdit applies an approved edit of a dead-lettered job and replays it,
+// returning the replayed job. A job already replayed for the request is returned as is.
+func (s *DeadLetterService) ReplayApprovedEdit(ctx context.Context, request *models.ApprovalRequest, payload *models.DeadLetterEditPayload) (*uuid.UUID, error) {
+	deadLetter, err := s.deadLetterRepo.FindByID(ctx, payload.DeadLetterID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDeadLetterNotFound, err)
+	}
+	if deadLetter.Status == models.DeadLetterStatusReplayed {
+		return &deadLetter.JobID, nil
+	}
+	if !deadLetter.IsPending() {
+		return nil, ErrDeadLetterResolved
+	}
+
+	edited := *deadLetter
+	edited.FromAccountID = payload.FromAccountID
+	edited.ToAccountID = payload.ToAccountID
+	edited.Amount = payload.Amount
+	edited.MaxRetries = payload.MaxRetries
+	if err := validateDeadLetterAccounts(&edited); err != nil {
+		return nil, err
+	}
+	if _, err := s.applyEdit(ctx, request.MakerID, deadLetter, &edited); err != nil {
+		return nil, err
+	}
+
+	replayedBy := request.MakerID
+	if request.CheckerID != nil {
+		replayedBy = *request.CheckerID
+	}
+	replayed, err := s.ReplayDeadLetter(ctx, deadLetter.ID, replayedBy)
+	if err != nil {
+		return nil, err
+	}
+	return &replayed.JobID, nil
+}
+
+// applyEdit stores the edited job and records the change
+func (s *DeadLetterService) applyEdit(ctx context.Context, adminID uuid.UUID, deadLetter, edited *models.DeadLetterJob) (*models.DeadLetterJob, error) {
+	if err := s.deadLetterRepo.Update(ctx, edited); err != nil {
+		return nil, ErrDeadLetterResolved
+	}
+	s.audit(ctx, adminID, "DEAD_LETTER_EDITED", edited,
+		fmt.Sprintf("Dead-letter job edited (%s -> %s)", describeDeadLetter(deadLetter), describeDeadLetter(edited)))
+	return edited, nil
+}
+
+// ReplayDeadLetter resubmits a pending dead-lettered job to the worker pool with a fresh retry budget.
+// The original job ID is kept so a transaction already recorded for it is not applied twice.
+func (s *DeadLetterService) ReplayDeadLetter(ctx context.Context, id, adminID uuid.UUID) (*models.DeadLetterJob, error) {
+	deadLetter, err := s.deadLetterRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDeadLetterNotFound, err)
+	}
+	if !deadLetter.IsPending() {
+		return nil, ErrDeadLetterResolved
+	}
+
+	// Claim the record first so concurrent replays cannot submit it twice
+	if err := s.deadLetterRepo.MarkResolved(ctx, id, models.DeadLetterStatusReplayed, adminID); err != nil {
+		return nil, ErrDeadLetterResolved
+	}
+
+	if err := s.jobRepo.Requeue(ctx, deadLetter.JobID, deadLetter.FromAccountID, deadLetter.ToAccountID, deadLetter.Amount); err != nil {
+		s.logger.Warn("Failed to requeue job record for replay",
+			zap.String("job_id", deadLetter.JobID.String()),
+			zap.Error(err))
+	}
+
+	if err := s.workerPool.SubmitJob(processing.JobFromDeadLetter(deadLetter, s.jobServices)); err != nil {
+		s.deadLetterRepo.Reopen(ctx, id)
+		s.jobRepo.MarkFailed(ctx, deadLetter.JobID, models.JobStatusFailed, deadLetter.Reason)
+		return nil, fmt.Errorf("failed to submit replay: %w", err)
+	}
+
+	s.audit(ctx, adminID, "DEAD_LETTER_REPLAYED", deadLetter,
+		fmt.Sprintf("Dead-letter job replayed as job %s", deadLetter.JobID))
+
+	return s.deadLetterRepo.FindByID(ctx, id)
+}
+
+// DiscardDeadLetter marks a pending dead-lettered job as discarded; the record is kept for auditing
+func (s *DeadLetterService) DiscardDeadLetter(ctx context.Context, id, adminID uuid.UUID) (*models.DeadLetterJob, error) {
+	deadLetter, err := s.deadLetterRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDeadLetterNotFound, err)
+	}
+	if !deadLetter.IsPending() {
+		return nil, ErrDeadLetterResolved
+	}
+
+	if err := s.deadLetterRepo.MarkResolved(ctx, id, models.DeadLetterStatusDiscarded, adminID); err != nil {
+		return nil, ErrDeadLetterResolved
+	}
+
+	s.audit(ctx, adminID, "DEAD_LETTER_DISCARDED", deadLetter,
+		fmt.Sprintf("Dead-letter job discarded (Reason: %s)", deadLetter.Reason))
+
+	return s.deadLetterRepo.FindByID(ctx, id)
+}
+
+// audit records an administrator action on a dead-lettered job
+func (s *DeadLetterService) audit(ctx context.Context, adminID uuid.UUID, action string, deadLetter *models.DeadLetterJob, details string) {
+	if s.auditService == nil {
+		return
+	}
+	s.auditService.LogUserActivity(ctx, adminID, action, "dead_letter_job", deadLetter.ID.String(),
+		fmt.Sprintf("%s (Job: %s, Type: %s)", details, deadLetter.JobID, deadLetter.TransactionType))
+}
+
+// validateDeadLetterAccounts checks that the accounts required by the transaction type are set
+func validateDeadLetterAccounts(deadLetter *models.DeadLetterJob) error {
+	switch models.JobType(deadLetter.TransactionType) {
+	case models.JobTypeCredit:
+		if deadLetter.ToAccountID == nil {
+			return fmt.Errorf("credit job requires to_account_id")
+		}
+	case models.JobTypeDebit:
+		if deadLetter.FromAccountID == nil {
+			return fmt.Errorf("debit job requires from_account_id")
+		}
+	case models.JobTypeTransfer:
+		if deadLetter.FromAccountID == nil || deadLetter.ToAccountID == nil {
+			return fmt.Errorf("transfer job requires from_account_id and to_account_id")
+		}
+		if *deadLetter.FromAccountID == *deadLetter.ToAccountID {
+			return fmt.Errorf("cannot transfer to the same account")
+		}
+	case models.JobTypeRefund:
+		if deadLetter.SourceTransactionID == nil {
+			return fmt.Errorf("refund job requires source_transaction_id")
+		}
+	}
+	return nil
+}
+
+// movesMoneyDifferently reports whether an edit changes an account or the amount of the job
+func movesMoneyDifferently(deadLetter, edited *models.DeadLetterJob) bool {
+	return formatOptionalUUID(deadLetter.FromAccountID) != formatOptionalUUID(edited.FromAccountID) ||
+		formatOptionalUUID(deadLetter.ToAccountID) != formatOptionalUUID(edited.ToAccountID) ||
+		deadLetter.Amount != edited.Amount
+}
+
+// deadLetterSubject returns the account an edited job takes money from, or credits
+func deadLetterSubject(deadLetter *models.DeadLetterJob) uuid.UUID {
+	if deadLetter.FromAccountID != nil {
+		return *deadLetter.FromAccountID
+	}
+	if deadLetter.ToAccountID != nil {
+		return *deadLetter.ToAccountID
+	}
+	return uuid.Nil
+}
+
+// describeDeadLetter formats the editable fields of a job for audit details
+func describeDeadLetter(deadLetter *models.DeadLetterJob) string {
+	return fmt.Sprintf("from=%s to=%s amount=%f max_retries=%d",
+		formatOptionalUUID(deadLetter.FromAccountID), formatOptionalUUID(deadLetter.ToAccountID), deadLetter.Amount, deadLetter.MaxRetries)
+}
+
+// formatOptionalUUID formats an optional UUID for audit details
+func formatOptionalUUID(id *uuid.UUID) string {
+	if id == nil {
+		return "-"
+	}
+	return id.String()
+}
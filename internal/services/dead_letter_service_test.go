@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestDeadLetterEditsOfAccountsOrAmountNeedApproval(t *testing.T) {
+	from, to, other := uuid.New(), uuid.New(), uuid.New()
+	deadLetter := &models.DeadLetterJob{TransactionType: "transfer", FromAccountID: &from, ToAccountID: &to, Amount: 100, MaxRetries: 3}
+
+	cases := []struct {
+		name string
+		edit func(job *models.DeadLetterJob)
+		want bool
+	}{
+		{"retry budget only", func(job *models.DeadLetterJob) { job.MaxRetries = 5 }, false},
+		{"same values", func(job *models.DeadLetterJob) { same := from; job.FromAccountID = &same }, false},
+		{"source account", func(job *models.DeadLetterJob) { job.FromAccountID = &other }, true},
+		{"destination account", func(job *models.DeadLetterJob) { job.ToAccountID = &other }, true},
+		{"amount", func(job *models.DeadLetterJob) { job.Amount = 100000 }, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			edited := *deadLetter
+			tc.edit(&edited)
+			if got := movesMoneyDifferently(deadLetter, &edited); got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/processing"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// JobService persists asynchronous transaction jobs and tracks their progress through the worker pool
+type JobService struct {
+	jobRepo            interfaces.JobRepository
+	transactionService interfaces.TransactionService
+	balanceService     interfaces.BalanceService
+	auditService       interfaces.AuditService
+	workerPool         *processing.WorkerPool
+	logger             *zap.Logger
+}
+
+// NewJobService creates a new JobService and registers it as a worker pool listener
+func NewJobService(
+	jobRepo interfaces.JobRepository,
+	transactionService interfaces.TransactionService,
+	balanceService interfaces.BalanceService,
+	auditService interfaces.AuditService,
+	workerPool *processing.WorkerPool,
+	logger *zap.Logger,
+) *JobService {
+	js := &JobService{
+		jobRepo:            jobRepo,
+		transactionService: transactionService,
+		balanceService:     balanceService,
+		auditService:       auditService,
+		workerPool:         workerPool,
+		logger:             logger,
+	}
+	workerPool.AddListener(js)
+	return js
+}
+
+// SubmitJob persists the job and hands it to the worker pool. With a queue in the
+// application database, the job and its queue entry are written in one transaction.
+func (js *JobService) SubmitJob(ctx context.Context, job *models.Job) error {
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	if job.MaxRetries == 0 {
+		job.MaxRetries = 3
+	}
+	if job.Priority == "" {
+		job.Priority = models.JobPriorityNormal
+	}
+	job.Status = models.JobStatusQueued
+	job.CreatedAt = time.Now()
+
+	// Reject before persisting so saturated submissions leave no failed job behind
+	if js.workerPool.IsSaturated() {
+		return processing.ErrQueueFull
+	}
+
+	transactionJob := js.toTransactionJob(job)
+	enqueued, err := js.persist(ctx, job, transactionJob)
+	if err != nil {
+		return err
+	}
+
+	if !enqueued {
+		if err := js.workerPool.SubmitJob(transactionJob); err != nil {
+			// The job never reached a worker, record it as failed so pollers see the outcome
+			js.jobRepo.MarkFailed(ctx, job.ID, models.JobStatusFailed, err.Error())
+			job.Status = models.JobStatusFailed
+			job.Error = err.Error()
+			return fmt.Errorf("failed to submit job: %w", err)
+		}
+	}
+
+	if js.auditService != nil {
+		js.auditService.LogUserActivity(ctx, job.UserID, "JOB_SUBMITTED", "job", job.ID.String(),
+			fmt.Sprintf("Async %s job submitted (Amount: %f)", job.Type, job.Amount))
+	}
+
+	return nil
+}
+
+// SubmitJobs persists the jobs and hands them to the worker pool one by one, each
+// in its own transaction like SubmitJob.
+// A failing job does not stop the rest; the returned slice holds the error of
+// each job at the same index, or nil.
+func (js *JobService) SubmitJobs(ctx context.Context, jobs []*models.Job) []error {
+	errs := make([]error, len(jobs))
+	transactionJobs := make([]*processing.TransactionJob, 0, len(jobs))
+	positions := make([]int, 0, len(jobs))
+
+	for i, job := range jobs {
+		if job.ID == uuid.Nil {
+			job.ID = uuid.New()
+		}
+		if job.MaxRetries == 0 {
+			job.MaxRetries = 3
+		}
+		if job.Priority == "" {
+			job.Priority = models.JobPriorityNormal
+		}
+		job.Status = models.JobStatusQueued
+		job.CreatedAt = time.Now()
+
+		transactionJob := js.toTransactionJob(job)
+		enqueued, err := js.persist(ctx, job, transactionJob)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if !enqueued {
+			transactionJobs = append(transactionJobs, transactionJob)
+			positions = append(positions, i)
+		}
+	}
+
+	for k, err := range js.workerPool.SubmitBatch(transactionJobs) {
+		if err == nil {
+			continue
+		}
+		job := jobs[positions[k]]
+		js.jobRepo.MarkFailed(ctx, job.ID, models.JobStatusFailed, err.Error())
+		job.Status = models.JobStatusFailed
+		job.Error = err.Error()
+		errs[positions[k]] = fmt.Errorf("failed to submit job: %w", err)
+	}
+
+	return errs
+}
+
+// persist writes the job and, if the queue is stored in the database, its queue entry
+// in the same transaction. It reports whether the job was enqueued.
+func (js *JobService) persist(ctx context.Context, job *models.Job, transactionJob *processing.TransactionJob) (bool, error) {
+	enqueued := false
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return fmt.Errorf("failed to persist job: %w", err)
+		}
+
+		var err error
+		if enqueued, err = js.workerPool.SubmitJobTx(tx, transactionJob); err != nil {
+			return fmt.Errorf("failed to submit job: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return enqueued, nil
+}
+
+// GetJob retrieves a job with its linked transaction
+func (js *JobService) GetJob(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	job, err := js.jobRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("job not found: %w", err)
+	}
+	return job, nil
+}
+
+// RecoverJobs restores job state after a restart. A durable queue still holds
+// every unsettled job, so nothing needs to be done. With an in-memory queue the
+// unfinished jobs are resubmitted; replays are safe because transactions are
+// recorded once per job ID.
+func (js *JobService) RecoverJobs(ctx context.Context) error {
+	if js.workerPool.IsDurable() {
+		return nil
+	}
+
+	for _, status := range []models.JobStatus{models.JobStatusQueued, models.JobStatusRetrying, models.JobStatusProcessing} {
+		jobs, err := js.jobRepo.FindByStatus(ctx, status, 0)
+		if err != nil {
+			return fmt.Errorf("failed to load %s jobs: %w", status, err)
+		}
+		for _, job := range jobs {
+			if err := js.workerPool.SubmitJob(js.toTransactionJob(job)); err != nil {
+				js.jobRepo.MarkFailed(ctx, job.ID, models.JobStatusFailed, err.Error())
+				continue
+			}
+			js.logger.Info("Job resubmitted after restart",
+				zap.String("job_id", job.ID.String()),
+				zap.String("status", string(status)))
+		}
+	}
+
+	return nil
+}
+
+// OnJobStarted implements processing.JobListener
+func (js *JobService) OnJobStarted(job *processing.TransactionJob) {
+	if err := js.jobRepo.MarkProcessing(context.Background(), job.ID, job.RetryCount); err != nil {
+		js.logger.Error("Failed to mark job as processing",
+			zap.String("job_id", job.ID.String()),
+			zap.Error(err))
+	}
+}
+
+// OnJobCompleted implements processing.JobListener
+func (js *JobService) OnJobCompleted(result *processing.TransactionResult) {
+	ctx := context.Background()
+
+	var err error
+	switch {
+	case result.Success:
+		var transactionID *uuid.UUID
+		if result.Transaction != nil {
+			transactionID = &result.Transaction.ID
+		}
+		err = js.jobRepo.MarkCompleted(ctx, result.JobID, transactionID)
+	case result.WillRetry:
+		err = js.jobRepo.MarkFailed(ctx, result.JobID, models.JobStatusRetrying, result.Error.Error())
+	default:
+		err = js.jobRepo.MarkFailed(ctx, result.JobID, models.JobStatusFailed, result.Error.Error())
+	}
+
+	if err != nil {
+		js.logger.Error("Failed to record job result",
+			zap.String("job_id", result.JobID.String()),
+			zap.Bool("success", result.Success),
+			zap.Error(err))
+	}
+}
+
+// toTransactionJob converts a persisted job into a worker pool job
+func (js *JobService) toTransactionJob(job *models.Job) *processing.TransactionJob {
+	transactionJob := &processing.TransactionJob{
+		ID:                 job.ID,
+		TransactionType:    string(job.Type),
+		UserID:             job.UserID,
+		Priority:           job.Priority,
+		Amount:             job.Amount,
+		Reference:          job.Reference,
+		TransactionService: js.transactionService,
+		BalanceService:     js.balanceService,
+		AuditService:       js.auditService,
+		RetryCount:         job.RetryCount,
+		MaxRetries:         job.MaxRetries,
+		CreatedAt:          job.CreatedAt,
+	}
+	if job.FromAccountID != nil {
+		transactionJob.FromAccountID = *job.FromAccountID
+	}
+	if job.ToAccountID != nil {
+		transactionJob.ToAccountID = *job.ToAccountID
+	}
+	if job.SourceTransactionID != nil {
+		transactionJob.SourceTransactionID = *job.SourceTransactionID
+	}
+	return transactionJob
+}
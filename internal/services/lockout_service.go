@@ -0,0 +1,400 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginThrottledError is returned while logins are delayed or locked for an account or client IP
+type LoginThrottledError struct {
+	Scope      models.LockoutScope
+	Locked     bool // false for a progressive delay between attempts
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("login locked for %s, retry after %s", e.Scope, e.RetryAfter)
+	}
+	return fmt.Sprintf("login delayed for %s, retry after %s", e.Scope, e.RetryAfter)
+}
+
+// LockoutPolicy configures failed-login throttling
+type LockoutPolicy struct {
+	MaxUserAttempts int
+	MaxIPAttempts   int
+	Duration        time.Duration // first lockout; each further lockout doubles it up to MaxDuration
+	MaxDuration     time.Duration
+	DelayBase       time.Duration // wait enforced after the second failure, doubling per failure up to MaxDelay
+	MaxDelay        time.Duration
+	ResetAfter      time.Duration // failures older than this are forgotten
+}
+
+// LockoutNotifier is told when an account gets locked, e.g. to email its owner or alert
+// security. It is called once per lockout, by the replica that recorded it.
+type LockoutNotifier interface {
+	AccountLocked(ctx context.Context, user *models.User, lockedUntil time.Time)
+}
+
+// loggingLockoutNotifier reports lockouts to the log only
+type loggingLockoutNotifier struct {
+	logger *zap.Logger
+}
+
+// NewLoggingLockoutNotifier returns a LockoutNotifier that writes lockouts to the log
+func NewLoggingLockoutNotifier(logger *zap.Logger) LockoutNotifier {
+	return &loggingLockoutNotifier{logger: logger}
+}
+
+func (n *loggingLockoutNotifier) AccountLocked(ctx context.Context, user *models.User, lockedUntil time.Time) {
+	n.logger.Warn("Account locked after failed logins",
+		zap.String("user_id", user.ID.String()),
+		zap.String("username", user.Username),
+		zap.Time("locked_until", lockedUntil),
+		zap.String("type", "account_locked"),
+	)
+}
+
+// LockoutService counts failed logins per account and per client IP, enforcing progressive
+// delays and temporary lockouts. State is kept in the database so replicas share it.
+type LockoutService struct {
+	userRepo     interfaces.UserRepository
+	auditService interfaces.AuditService
+	notifier     LockoutNotifier
+	policy       LockoutPolicy
+	logger       *zap.Logger
+}
+
+// NewLockoutService creates a new LockoutService
+func NewLockoutService(
+	userRepo interfaces.UserRepository,
+	auditService interfaces.AuditService,
+	notifier LockoutNotifier,
+	policy LockoutPolicy,
+	logger *zap.Logger,
+) *LockoutService {
+	return &LockoutService{
+		userRepo:     userRepo,
+		auditService: auditService,
+		notifier:     notifier,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+// ResolveUserID returns the ID of the account a login identifier (username or email) names,
+// or nil if there is none; unknown identifiers are throttled by IP only
+func (s *LockoutService) ResolveUserID(ctx context.Context, usernameOrEmail string) *uuid.UUID {
+	query := strings.TrimSpace(usernameOrEmail)
+	user, err := s.userRepo.GetByUsername(ctx, query)
+	if err != nil {
+		if user, err = s.userRepo.GetByEmail(ctx, query); err != nil {
+			return nil
+		}
+	}
+	return &user.ID
+}
+
+// Attempt checks and counts a login attempt for the account (if known) and the client IP in
+// one transaction, before the credentials are verified. It returns a *LoginThrottledError,
+// counting nothing, if either may not attempt a login now, so a locked account stays locked
+// even for the right password. The throttle rows are locked while they are checked, so
+// parallel attempts are counted one after another and cannot all pass the limit. Attempts
+// whose credentials turn out to be right are handed back with Refund.
+func (s *LockoutService) Attempt(ctx context.Context, userID *uuid.UUID, ipAddress string) error {
+	keys := s.throttleKeys(userID, ipAddress)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var throttled *LoginThrottledError
+	var locked []models.LoginThrottle
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		throttles := make([]models.LoginThrottle, 0, len(keys))
+		for _, key := range keys {
+			throttle, err := s.lockThrottle(tx, key)
+			if err != nil {
+				return err
+			}
+			throttles = append(throttles, *throttle)
+		}
+
+		// Every throttle is checked before any is counted, so a rejected attempt counts against none
+		for i := range throttles {
+			throttle := &throttles[i]
+			if s.reachedLimit(throttle, now) {
+				s.lock(throttle, now)
+				if err := s.saveThrottle(tx, throttle); err != nil {
+					return err
+				}
+				locked = append(locked, *throttle)
+			}
+			err := s.evaluate(throttle, now)
+			if err != nil && (throttled == nil || err.RetryAfter > throttled.RetryAfter) {
+				throttled = err
+			}
+		}
+		if throttled != nil {
+			return nil
+		}
+
+		for i := range throttles {
+			throttle := &throttles[i]
+			if !s.isFresh(throttle, now) || throttle.LockedUntil != nil {
+				throttle.FailedCount = 0
+				throttle.LockedUntil = nil
+			}
+			throttle.FailedCount++
+			throttle.LastFailedAt = &now
+			if err := s.saveThrottle(tx, throttle); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check login throttles: %w", err)
+	}
+
+	// Only the transaction that set the lock gets here, so each lockout is reported once
+	for _, throttle := range locked {
+		s.reportLock(ctx, &throttle)
+	}
+	if throttled != nil {
+		return throttled
+	}
+	return nil
+}
+
+// Refund hands back an attempt counted by Attempt whose credentials were right, or that
+// failed for a reason other than wrong credentials. Throttles locked in the meantime are
+// left locked.
+func (s *LockoutService) Refund(ctx context.Context, userID *uuid.UUID, ipAddress string) error {
+	db := database.GetDB().WithContext(ctx)
+	for _, key := range s.throttleKeys(userID, ipAddress) {
+		err := db.Model(&models.LoginThrottle{}).
+			Where("scope = ? AND key = ? AND failed_count > 0 AND locked_until IS NULL", key.Scope, key.Key).
+			Update("failed_count", gorm.Expr("failed_count - 1")).Error
+		if err != nil {
+			return fmt.Errorf("failed to refund login attempt: %w", err)
+		}
+	}
+	return nil
+}
+
+// RecordSuccess clears the failed-login state of an account after a successful login. The IP
+// counter is left to expire, so one valid account cannot reset an attacker's IP.
+func (s *LockoutService) RecordSuccess(ctx context.Context, userID uuid.UUID) error {
+	err := database.GetDB().WithContext(ctx).
+		Where("scope = ? AND key = ?", models.LockoutScopeUser, userID.String()).
+		Delete(&models.LoginThrottle{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to reset login throttle: %w", err)
+	}
+	return nil
+}
+
+// Status returns the lockout state of an account
+func (s *LockoutService) Status(ctx context.Context, userID uuid.UUID) (*models.LockoutStatus, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	status := &models.LockoutStatus{UserID: userID}
+	var throttle models.LoginThrottle
+	err := database.GetDB().WithContext(ctx).
+		Where("scope = ? AND key = ?", models.LockoutScopeUser, userID.String()).
+		First(&throttle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login throttle: %w", err)
+	}
+
+	now := time.Now()
+	status.Locked = throttle.IsLocked(now) || s.reachedLimit(&throttle, now)
+	if status.Locked {
+		status.LockedUntil = throttle.LockedUntil
+	}
+	if s.isFresh(&throttle, now) {
+		status.FailedAttempts = throttle.FailedCount
+	}
+	status.LockCount = throttle.LockCount
+	status.LastFailedAt = throttle.LastFailedAt
+	return status, nil
+}
+
+// Unlock clears the lockout and failed attempts of an account
+func (s *LockoutService) Unlock(ctx context.Context, adminID, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if err := s.RecordSuccess(ctx, userID); err != nil {
+		return err
+	}
+
+	s.logger.Info("Account unlocked by admin",
+		zap.String("admin_id", adminID.String()),
+		zap.String("user_id", userID.String()))
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, adminID, "ADMIN_ACCOUNT_UNLOCKED", "user", userID.String(),
+			fmt.Sprintf("Account of user %s unlocked by admin", user.Username))
+	}
+	return nil
+}
+
+// lockThrottle returns a throttle, creating it if needed, locked for update until the
+// transaction ends
+func (s *LockoutService) lockThrottle(tx *gorm.DB, key models.LoginThrottle) (*models.LoginThrottle, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&key).Error; err != nil {
+		return nil, fmt.Errorf("failed to create login throttle: %w", err)
+	}
+	var throttle models.LoginThrottle
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("scope = ? AND key = ?", key.Scope, key.Key).First(&throttle).Error; err != nil {
+		return nil, fmt.Errorf("failed to lock login throttle: %w", err)
+	}
+	return &throttle, nil
+}
+
+// reachedLimit reports whether a throttle used up its attempts and must be locked now
+func (s *LockoutService) reachedLimit(throttle *models.LoginThrottle, now time.Time) bool {
+	return throttle.LockedUntil == nil && s.isFresh(throttle, now) && throttle.FailedCount >= s.maxAttempts(throttle.Scope)
+}
+
+// lock starts the next, longer lockout of a throttle
+func (s *LockoutService) lock(throttle *models.LoginThrottle, now time.Time) {
+	lockedUntil := now.Add(s.lockoutDuration(throttle.LockCount))
+	throttle.LockedUntil = &lockedUntil
+	throttle.LockCount++
+	throttle.FailedCount = 0
+}
+
+// saveThrottle writes the counters of a throttle
+func (s *LockoutService) saveThrottle(tx *gorm.DB, throttle *models.LoginThrottle) error {
+	err := tx.Model(&models.LoginThrottle{}).
+		Where("scope = ? AND key = ?", throttle.Scope, throttle.Key).
+		Updates(map[string]interface{}{
+			"failed_count":   throttle.FailedCount,
+			"lock_count":     throttle.LockCount,
+			"last_failed_at": throttle.LastFailedAt,
+			"locked_until":   throttle.LockedUntil,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update login throttle: %w", err)
+	}
+	return nil
+}
+
+// evaluate returns the lockout or progressive delay a throttle imposes at now, if any
+func (s *LockoutService) evaluate(throttle *models.LoginThrottle, now time.Time) *LoginThrottledError {
+	if throttle.IsLocked(now) {
+		return &LoginThrottledError{Scope: throttle.Scope, Locked: true, RetryAfter: throttle.LockedUntil.Sub(now)}
+	}
+	if !s.isFresh(throttle, now) || throttle.LockedUntil != nil {
+		return nil
+	}
+	next := throttle.LastFailedAt.Add(s.delay(throttle.FailedCount))
+	if next.After(now) {
+		return &LoginThrottledError{Scope: throttle.Scope, RetryAfter: next.Sub(now)}
+	}
+	return nil
+}
+
+// reportLock audits a new lockout and notifies about locked accounts
+func (s *LockoutService) reportLock(ctx context.Context, throttle *models.LoginThrottle) {
+	if throttle.Scope == models.LockoutScopeIP {
+		s.logger.Warn("Client IP locked after failed logins",
+			zap.String("ip", throttle.Key),
+			zap.Time("locked_until", *throttle.LockedUntil),
+			zap.String("type", "ip_locked"))
+		if s.auditService != nil {
+			s.auditService.LogSystemActivity(ctx, "LOGIN_IP_LOCKED",
+				fmt.Sprintf("Logins from %s locked until %s", throttle.Key, throttle.LockedUntil.UTC().Format(time.RFC3339)))
+		}
+		return
+	}
+
+	userID, err := uuid.Parse(throttle.Key)
+	if err != nil {
+		return
+	}
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "ACCOUNT_LOCKED", "user", userID.String(),
+			fmt.Sprintf("Account locked after failed logins until %s (Lockout: %d)", throttle.LockedUntil.UTC().Format(time.RFC3339), throttle.LockCount))
+	}
+	if s.notifier == nil {
+		return
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to load locked user for notification", zap.String("user_id", userID.String()), zap.Error(err))
+		return
+	}
+	s.notifier.AccountLocked(ctx, user, *throttle.LockedUntil)
+}
+
+// throttleKeys returns the throttles a login attempt counts against
+func (s *LockoutService) throttleKeys(userID *uuid.UUID, ipAddress string) []models.LoginThrottle {
+	var keys []models.LoginThrottle
+	if userID != nil {
+		keys = append(keys, models.LoginThrottle{Scope: models.LockoutScopeUser, Key: userID.String()})
+	}
+	if ipAddress != "" {
+		keys = append(keys, models.LoginThrottle{Scope: models.LockoutScopeIP, Key: truncate(ipAddress, 64)})
+	}
+	return keys
+}
+
+// isFresh reports whether a throttle's failures still count
+func (s *LockoutService) isFresh(throttle *models.LoginThrottle, now time.Time) bool {
+	return throttle.LastFailedAt != nil && now.Sub(*throttle.LastFailedAt) < s.policy.ResetAfter
+}
+
+// maxAttempts returns the failure limit of a scope
+func (s *LockoutService) maxAttempts(scope models.LockoutScope) int {
+	if scope == models.LockoutScopeIP {
+		return s.policy.MaxIPAttempts
+	}
+	return s.policy.MaxUserAttempts
+}
+
+// delay returns the wait required after the given number of consecutive failures: none
+// after the first, then DelayBase doubling per failure up to MaxDelay
+func (s *LockoutService) delay(failures int) time.Duration {
+	if failures < 2 || s.policy.DelayBase <= 0 {
+		return 0
+	}
+	return doubled(s.policy.DelayBase, failures-2, s.policy.MaxDelay)
+}
+
+// lockoutDuration returns the length of the next lockout after previous lockouts
+func (s *LockoutService) lockoutDuration(previous int) time.Duration {
+	return doubled(s.policy.Duration, previous, s.policy.MaxDuration)
+}
+
+// doubled returns base doubled n times, capped at max
+func doubled(base time.Duration, n int, max time.Duration) time.Duration {
+	value := base
+	for i := 0; i < n && value < max; i++ {
+		value *= 2
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,437 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/pkg/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrMFAAlreadyEnabled is returned when enrolling a user whose MFA is already active
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	// ErrMFANotEnrolled is returned when confirming without a pending enrollment
+	ErrMFANotEnrolled = errors.New("two-factor authentication enrollment not started")
+	// ErrMFANotEnabled is returned for operations that need active MFA
+	ErrMFANotEnabled = errors.New("two-factor authentication not enabled")
+	// ErrInvalidMFACode is returned for a wrong, expired or replayed TOTP or recovery code
+	ErrInvalidMFACode = errors.New("invalid two-factor authentication code")
+	// ErrInvalidMFAChallenge is returned for an invalid, expired or already used MFA challenge token
+	ErrInvalidMFAChallenge = errors.New("invalid MFA challenge")
+)
+
+// totpSkew is the number of 30-second steps accepted either side of the current one
+const totpSkew = 1
+
+// recoveryCodeEncoding renders recovery codes without ambiguous padding
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFAService manages TOTP two-factor authentication: enrollment, verification, recovery
+// codes and the second step of the login flow
+type MFAService struct {
+	userRepo     interfaces.UserRepository
+	auditService interfaces.AuditService
+	jwtConfig    *utils.JWTConfig
+	secretKey    []byte
+	issuer       string
+	challengeTTL time.Duration
+	logger       *zap.Logger
+}
+
+// NewMFAService creates a new MFAService. secretKey is the AES-256 key for TOTP secrets at rest.
+func NewMFAService(
+	userRepo interfaces.UserRepository,
+	auditService interfaces.AuditService,
+	jwtConfig *utils.JWTConfig,
+	secretKey []byte,
+	issuer string,
+	challengeTTL time.Duration,
+	logger *zap.Logger,
+) *MFAService {
+	return &MFAService{
+		userRepo:     userRepo,
+		auditService: auditService,
+		jwtConfig:    jwtConfig,
+		secretKey:    secretKey,
+		issuer:       issuer,
+		challengeTTL: challengeTTL,
+		logger:       logger,
+	}
+}
+
+// Enroll starts TOTP enrollment with a new secret. Until ConfirmEnrollment succeeds the
+// secret is pending and login stays password-only; enrolling again replaces it.
+func (s *MFAService) Enroll(ctx context.Context, userID uuid.UUID) (*models.MFAEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := utils.EncryptSecret(s.secretKey, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt TOTP secret: %w", err)
+	}
+
+	enrollment := &models.UserMFA{UserID: userID, SecretEncrypted: encrypted}
+	err = database.GetDB().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"secret_encrypted": encrypted, "confirmed_at": nil, "last_used_step": 0, "updated_at": time.Now()}),
+	}).Create(enrollment).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to save MFA enrollment: %w", err)
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "MFA_ENROLLMENT_STARTED", "user", userID.String(), "TOTP enrollment started")
+	}
+
+	return &models.MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(secret, s.issuer, user.Email),
+	}, nil
+}
+
+// ConfirmEnrollment enables MFA once the user proves the authenticator works, and returns
+// freshly generated recovery codes. The codes are shown once; only their hashes are kept.
+func (s *MFAService) ConfirmEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	var codes []string
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var enrollment models.UserMFA
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", userID).First(&enrollment).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMFANotEnrolled
+			}
+			return fmt.Errorf("failed to get MFA enrollment: %w", err)
+		}
+		if enrollment.ConfirmedAt != nil {
+			return ErrMFAAlreadyEnabled
+		}
+
+		step, err := s.matchTOTP(&enrollment, code)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := tx.Model(&models.UserMFA{}).Where("user_id = ?", userID).
+			Updates(map[string]interface{}{"confirmed_at": now, "last_used_step": step}).Error; err != nil {
+			return fmt.Errorf("failed to confirm MFA enrollment: %w", err)
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("mfa_enabled", true).Error; err != nil {
+			return fmt.Errorf("failed to enable MFA: %w", err)
+		}
+
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		s.auditFailure(ctx, userID, err)
+		return nil, err
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "MFA_ENABLED", "user", userID.String(), "TOTP two-factor authentication enabled")
+	}
+	return codes, nil
+}
+
+// Verify checks a TOTP code or a recovery code of a user with MFA enabled and returns the
+// method that succeeded. Accepted TOTP codes and recovery codes cannot be used again.
+func (s *MFAService) Verify(ctx context.Context, userID uuid.UUID, code string) (models.AuthMethod, error) {
+	var method models.AuthMethod
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		method, err = s.verify(tx, userID, code)
+		return err
+	})
+	if err != nil {
+		s.auditFailure(ctx, userID, err)
+		return "", err
+	}
+	s.auditRecoveryCodeUse(ctx, userID, method)
+	return method, nil
+}
+
+// Disable turns MFA off after verifying a current code and removes the secret and recovery codes
+func (s *MFAService) Disable(ctx context.Context, userID uuid.UUID, code string) error {
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := s.verify(tx, userID, code); err != nil {
+			return err
+		}
+		return removeMFA(tx, userID)
+	})
+	if err != nil {
+		s.auditFailure(ctx, userID, err)
+		return err
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "MFA_DISABLED", "user", userID.String(), "TOTP two-factor authentication disabled")
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after verifying a current code
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	var codes []string
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := s.verify(tx, userID, code); err != nil {
+			return err
+		}
+		var err error
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		s.auditFailure(ctx, userID, err)
+		return nil, err
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "MFA_RECOVERY_CODES_REGENERATED", "user", userID.String(), "Recovery codes regenerated")
+	}
+	return codes, nil
+}
+
+// AdminReset removes a user's MFA so they can enroll again, e.g. after losing their device
+func (s *MFAService) AdminReset(ctx context.Context, adminID, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+
+	if err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return removeMFA(tx, userID)
+	}); err != nil {
+		return err
+	}
+
+	s.logger.Info("MFA reset by admin",
+		zap.String("admin_id", adminID.String()),
+		zap.String("user_id", userID.String()))
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, adminID, "ADMIN_MFA_RESET", "user", userID.String(),
+			fmt.Sprintf("Two-factor authentication of user %s reset by admin", user.Username))
+	}
+	return nil
+}
+
+// CreateChallenge issues the MFA challenge token returned by the password step of a login
+func (s *MFAService) CreateChallenge(user *models.User) (string, int64, error) {
+	token, err := utils.GenerateMFAChallengeToken(user, uuid.New(), s.challengeTTL, s.jwtConfig)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to generate MFA challenge: %w", err)
+	}
+	return token, int64(s.challengeTTL.Seconds()), nil
+}
+
+// CompleteChallenge verifies the second login step and returns the user. A challenge can
+// complete only one login; a wrong code leaves it usable until it expires.
+func (s *MFAService) CompleteChallenge(ctx context.Context, challengeToken, code string) (*models.User, models.AuthMethod, error) {
+	claims, err := utils.ValidateTokenOfType(challengeToken, utils.TokenTypeMFAChallenge, s.jwtConfig)
+	if err != nil || claims.ExpiresAt == nil {
+		return nil, "", ErrInvalidMFAChallenge
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, "", ErrInvalidMFAChallenge
+	}
+	challengeID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, "", ErrInvalidMFAChallenge
+	}
+
+	var method models.AuthMethod
+	err = database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		used, err := tokenIDUsed(tx, challengeID)
+		if err != nil {
+			return err
+		}
+		if used {
+			return ErrInvalidMFAChallenge
+		}
+
+		if method, err = s.verify(tx, userID, code); err != nil {
+			return err
+		}
+
+		consumed, err := consumeTokenID(tx, userID, challengeID, claims.ExpiresAt.Time)
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			return ErrInvalidMFAChallenge
+		}
+		return nil
+	})
+	if err != nil {
+		s.auditFailure(ctx, userID, err)
+		return nil, "", err
+	}
+	s.auditRecoveryCodeUse(ctx, userID, method)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, "", ErrInvalidMFAChallenge
+	}
+	return user, method, nil
+}
+
+// ChallengeUserID returns the user a valid MFA challenge token was issued to, without
+// consuming it, so failed second steps can be throttled per account
+func (s *MFAService) ChallengeUserID(challengeToken string) *uuid.UUID {
+	claims, err := utils.ValidateTokenOfType(challengeToken, utils.TokenTypeMFAChallenge, s.jwtConfig)
+	if err != nil {
+		return nil
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil
+	}
+	return &userID
+}
+
+// verify checks a TOTP or recovery code inside tx and records its use
+func (s *MFAService) verify(tx *gorm.DB, userID uuid.UUID, code string) (models.AuthMethod, error) {
+	var enrollment models.UserMFA
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND confirmed_at IS NOT NULL", userID).First(&enrollment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrMFANotEnabled
+		}
+		return "", fmt.Errorf("failed to get MFA settings: %w", err)
+	}
+
+	// Recovery codes are longer than TOTP codes and contain letters
+	if normalized := normalizeRecoveryCode(code); len(normalized) > utils.TOTPDigits {
+		result := tx.Model(&models.MFARecoveryCode{}).
+			Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashRecoveryCode(normalized)).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return "", fmt.Errorf("failed to use recovery code: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return "", ErrInvalidMFACode
+		}
+		return models.AuthMethodRecoveryCode, nil
+	}
+
+	step, err := s.matchTOTP(&enrollment, code)
+	if err != nil {
+		return "", err
+	}
+	if step <= enrollment.LastUsedStep {
+		return "", ErrInvalidMFACode // replay of an accepted code
+	}
+	if err := tx.Model(&models.UserMFA{}).Where("user_id = ?", userID).
+		Update("last_used_step", step).Error; err != nil {
+		return "", fmt.Errorf("failed to record TOTP use: %w", err)
+	}
+	return models.AuthMethodOTP, nil
+}
+
+// matchTOTP decrypts the secret and validates a TOTP code, returning its time step
+func (s *MFAService) matchTOTP(enrollment *models.UserMFA, code string) (int64, error) {
+	secret, err := utils.DecryptSecret(s.secretKey, enrollment.SecretEncrypted)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt TOTP secret: %w", err)
+	}
+	step, ok := utils.ValidateTOTP(secret, code, time.Now(), totpSkew)
+	if !ok {
+		return 0, ErrInvalidMFACode
+	}
+	return step, nil
+}
+
+// auditFailure records rejected codes; other errors are left to the caller
+func (s *MFAService) auditFailure(ctx context.Context, userID uuid.UUID, err error) {
+	if !errors.Is(err, ErrInvalidMFACode) || s.auditService == nil {
+		return
+	}
+	s.auditService.LogUserActivity(ctx, userID, "MFA_VERIFICATION_FAILED", "user", userID.String(), "Invalid two-factor authentication code")
+}
+
+// auditRecoveryCodeUse records a login or verification with a recovery code and the codes left
+func (s *MFAService) auditRecoveryCodeUse(ctx context.Context, userID uuid.UUID, method models.AuthMethod) {
+	if method != models.AuthMethodRecoveryCode || s.auditService == nil {
+		return
+	}
+	var remaining int64
+	database.GetDB().WithContext(ctx).Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).Count(&remaining)
+	s.auditService.LogUserActivity(ctx, userID, "MFA_RECOVERY_CODE_USED", "user", userID.String(),
+		fmt.Sprintf("Recovery code used (Remaining: %d)", remaining))
+}
+
+// removeMFA deletes a user's TOTP secret and recovery codes and disables MFA
+func removeMFA(tx *gorm.DB, userID uuid.UUID) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.UserMFA{}).Error; err != nil {
+		return fmt.Errorf("failed to delete MFA settings: %w", err)
+	}
+	if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("mfa_enabled", false).Error; err != nil {
+		return fmt.Errorf("failed to disable MFA: %w", err)
+	}
+	return nil
+}
+
+// replaceRecoveryCodes deletes a user's recovery codes and stores new ones, returning them in clear
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	codes := make([]string, models.MFARecoveryCodeCount)
+	records := make([]*models.MFARecoveryCode, models.MFARecoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		code := recoveryCodeEncoding.EncodeToString(raw)[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+		records[i] = &models.MFARecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)}
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode removes separators and case differences from a typed recovery code
+func normalizeRecoveryCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
+
+// hashRecoveryCode hashes a normalized recovery code. Codes carry 50 random bits, so a
+// fast hash is enough; there is no low-entropy input to protect with a slow KDF.
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LogNotificationService implements NotificationService by writing notifications to the log.
+// It stands in until an email/SMS provider is integrated; tokens are logged at debug level
+// only, so they appear in development logs but not in production.
+type LogNotificationService struct {
+	logger *zap.Logger
+}
+
+// NewLogNotificationService creates a new LogNotificationService
+func NewLogNotificationService(logger *zap.Logger) interfaces.NotificationService {
+	return &LogNotificationService{logger: logger}
+}
+
+// SendWelcomeEmail logs a welcome email
+func (s *LogNotificationService) SendWelcomeEmail(ctx context.Context, user *models.User) error {
+	s.logger.Info("Welcome email", zap.String("user_id", user.ID.String()), zap.String("type", "notification_email"))
+	return nil
+}
+
+// SendTransactionNotification logs a transaction notification
+func (s *LogNotificationService) SendTransactionNotification(ctx context.Context, user *models.User, transaction *models.Transaction) error {
+	s.logger.Info("Transaction notification",
+		zap.String("user_id", user.ID.String()),
+		zap.String("transaction_id", transaction.ID.String()),
+		zap.String("type", "notification_email"))
+	return nil
+}
+
+// SendPasswordResetEmail logs a password reset email
+func (s *LogNotificationService) SendPasswordResetEmail(ctx context.Context, user *models.User, resetToken string) error {
+	s.logger.Info("Password reset email", zap.String("user_id", user.ID.String()), zap.String("type", "notification_email"))
+	s.logger.Debug("Password reset token", zap.String("user_id", user.ID.String()), zap.String("token", resetToken))
+	return nil
+}
+
+// SendEmailVerification logs an email verification email
+func (s *LogNotificationService) SendEmailVerification(ctx context.Context, user *models.User, verificationToken string) error {
+	s.logger.Info("Email verification email", zap.String("user_id", user.ID.String()), zap.String("type", "notification_email"))
+	s.logger.Debug("Email verification token", zap.String("user_id", user.ID.String()), zap.String("token", verificationToken))
+	return nil
+}
+
+// SendBalanceAlert logs a balance alert
+func (s *LogNotificationService) SendBalanceAlert(ctx context.Context, user *models.User, balance *models.Balance) error {
+	s.logger.Info("Balance alert", zap.String("user_id", user.ID.String()), zap.Float64("amount", balance.Amount), zap.String("type", "notification_email"))
+	return nil
+}
+
+// SendSMSNotification logs an SMS
+func (s *LogNotificationService) SendSMSNotification(ctx context.Context, phoneNumber, message string) error {
+	s.logger.Info("SMS notification", zap.String("type", "notification_sms"))
+	return nil
+}
+
+// SendPushNotification logs a push notification
+func (s *LogNotificationService) SendPushNotification(ctx context.Context, userID uuid.UUID, title, message string) error {
+	s.logger.Info("Push notification", zap.String("user_id", userID.String()), zap.String("title", title), zap.String("type", "notification_push"))
+	return nil
+}
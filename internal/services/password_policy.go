@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/barannkoca/banking-backend/pkg/utils"
+)
+
+// PasswordPolicy configures the rules new passwords must meet
+type PasswordPolicy struct {
+	MinLength      int
+	MaxLength      int // in bytes; bcrypt rejects passwords longer than 72 bytes
+	RequireUpper   bool
+	RequireLower   bool
+	RequireNumber  bool
+	RequireSpecial bool
+	RejectCommon   bool          // reject passwords on the embedded common/breached list
+	HistorySize    int           // recent passwords, including the current one, that cannot be reused; 0 allows reuse
+	MaxAge         time.Duration // age after which the password must be changed, 0 to disable
+}
+
+// Validate checks a password against the length, character class and common password
+// rules. History is checked separately since it needs the user's stored hashes.
+func (p PasswordPolicy) Validate(password string) error {
+	if len([]rune(password)) < p.MinLength {
+		return &PasswordPolicyError{Reason: fmt.Sprintf("şifre en az %d karakter olmalıdır", p.MinLength)}
+	}
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		return &PasswordPolicyError{Reason: fmt.Sprintf("şifre en fazla %d karakter olabilir", p.MaxLength)}
+	}
+
+	var hasUpper, hasLower, hasNumber, hasSpecial bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsNumber(char):
+			hasNumber = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char):
+			hasSpecial = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		return &PasswordPolicyError{Reason: "şifre en az bir büyük harf içermelidir"}
+	}
+	if p.RequireLower && !hasLower {
+		return &PasswordPolicyError{Reason: "şifre en az bir küçük harf içermelidir"}
+	}
+	if p.RequireNumber && !hasNumber {
+		return &PasswordPolicyError{Reason: "şifre en az bir rakam içermelidir"}
+	}
+	if p.RequireSpecial && !hasSpecial {
+		return &PasswordPolicyError{Reason: "şifre en az bir özel karakter içermelidir"}
+	}
+	if p.RejectCommon && utils.IsCommonPassword(password) {
+		return &PasswordPolicyError{Reason: "bu şifre çok yaygın veya daha önce sızdırılmış, farklı bir şifre seçin"}
+	}
+
+	return nil
+}
+
+// Expired reports whether a password set at changedAt has passed the maximum age
+func (p PasswordPolicy) Expired(changedAt *time.Time, now time.Time) bool {
+	if p.MaxAge <= 0 || changedAt == nil {
+		return false
+	}
+	return now.Sub(*changedAt) > p.MaxAge
+}
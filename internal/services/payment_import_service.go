@@ -0,0 +1,541 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/iso20022"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PaymentImportPreviewTTL is how long an uploaded file can wait for confirmation
+const PaymentImportPreviewTTL = 24 * time.Hour
+
+var (
+	// ErrImportNotFound is returned when a payment import does not exist
+	ErrImportNotFound = errors.New("payment import not found")
+	// ErrInvalidPaymentFile is returned when an uploaded file cannot be parsed
+	ErrInvalidPaymentFile = errors.New("invalid payment file")
+	// ErrDuplicateImport is returned when the same file was already uploaded
+	ErrDuplicateImport = errors.New("payment file already imported")
+	// ErrImportNotPending is returned when an import is no longer awaiting confirmation
+	ErrImportNotPending = errors.New("payment import is not awaiting confirmation")
+	// ErrImportExpired is returned when an import was not confirmed in time
+	ErrImportExpired = errors.New("payment import preview expired")
+	// ErrImportNoValidRows is returned when confirming an import without valid rows
+	ErrImportNoValidRows = errors.New("payment import has no valid rows")
+	// ErrImportNotFinished is returned when a status report is requested before execution finished
+	ErrImportNotFinished = errors.New("payment import has not finished")
+)
+
+// importedPayment is a payment as read from the file, before validation
+type importedPayment struct {
+	line                 int
+	paymentInformationID string
+	endToEndID           string
+	recipient            string
+	recipientIBAN        string
+	recipientName        string
+	amount               string
+	currency             string
+	reference            string
+	parseError           string
+}
+
+// PaymentImportService parses bulk payment files, previews them and executes confirmed
+// imports as best-effort batches on the worker pool
+type PaymentImportService struct {
+	importRepo         interfaces.PaymentImportRepository
+	userRepo           interfaces.UserRepository
+	batchService       *BatchService
+	transactionService *TransactionService
+	auditService       interfaces.AuditService
+	currency           string
+	logger             *zap.Logger
+}
+
+// NewPaymentImportService creates a new PaymentImportService; currency is the only currency accepted in files
+func NewPaymentImportService(
+	importRepo interfaces.PaymentImportRepository,
+	userRepo interfaces.UserRepository,
+	batchService *BatchService,
+	transactionService *TransactionService,
+	auditService interfaces.AuditService,
+	currency string,
+	logger *zap.Logger,
+) *PaymentImportService {
+	return &PaymentImportService{
+		importRepo:         importRepo,
+		userRepo:           userRepo,
+		batchService:       batchService,
+		transactionService: transactionService,
+		auditService:       auditService,
+		currency:           currency,
+		logger:             logger,
+	}
+}
+
+// Upload parses and validates a payment file and stores it as a preview awaiting confirmation
+func (s *PaymentImportService) Upload(ctx context.Context, userID uuid.UUID, format models.PaymentFileFormat, fileName string, data []byte) (*models.PaymentImport, error) {
+	sum := sha256.Sum256(data)
+	fileHash := hex.EncodeToString(sum[:])
+
+	exists, err := s.importRepo.ExistsByHash(ctx, userID, fileHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check duplicate import: %w", err)
+	}
+	if exists {
+		return nil, ErrDuplicateImport
+	}
+
+	importID := uuid.New()
+	messageID := strings.ReplaceAll(importID.String(), "-", "")
+
+	var payments []importedPayment
+	switch format {
+	case models.PaymentFileFormatCSV:
+		payments, err = parseCSVPayments(data)
+	case models.PaymentFileFormatPain001:
+		var doc *iso20022.Pain001Document
+		doc, err = iso20022.ParsePain001(bytes.NewReader(data))
+		if err == nil {
+			messageID = doc.MessageID()
+			payments = pain001Payments(doc)
+		}
+	default:
+		err = fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPaymentFile, err)
+	}
+	if len(payments) == 0 {
+		return nil, fmt.Errorf("%w: file contains no payments", ErrInvalidPaymentFile)
+	}
+	if len(payments) > s.batchService.MaxItems() {
+		return nil, fmt.Errorf("%w: %d > %d", ErrBatchTooLarge, len(payments), s.batchService.MaxItems())
+	}
+
+	paymentImport := &models.PaymentImport{
+		ID:        importID,
+		UserID:    userID,
+		Format:    format,
+		FileName:  fileName,
+		FileHash:  fileHash,
+		MessageID: messageID,
+		Status:    models.PaymentImportStatusPreview,
+		RowCount:  len(payments),
+		ExpiresAt: time.Now().Add(PaymentImportPreviewTTL),
+		Rows:      s.validatePayments(ctx, userID, importID, payments),
+	}
+	for _, row := range paymentImport.Rows {
+		if row.Valid {
+			paymentImport.ValidCount++
+			paymentImport.ValidTotal += row.Amount
+		}
+	}
+
+	if err := s.importRepo.Create(ctx, paymentImport); err != nil {
+		return nil, fmt.Errorf("failed to persist payment import: %w", err)
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "PAYMENT_IMPORT_UPLOADED", "payment_import", paymentImport.ID.String(),
+			fmt.Sprintf("%s file %q uploaded (Rows: %d, Valid: %d, Total: %f)", format, fileName, paymentImport.RowCount, paymentImport.ValidCount, paymentImport.ValidTotal))
+	}
+
+	s.logger.Info("Payment file imported",
+		zap.String("import_id", paymentImport.ID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("format", string(format)),
+		zap.Int("row_count", paymentImport.RowCount),
+		zap.Int("valid_count", paymentImport.ValidCount))
+
+	return paymentImport, nil
+}
+
+// GetImport retrieves an import, expiring it if it was not confirmed in time, together with its batch once confirmed
+func (s *PaymentImportService) GetImport(ctx context.Context, id uuid.UUID) (*models.PaymentImport, *models.Batch, error) {
+	paymentImport, err := s.importRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrImportNotFound, err)
+	}
+
+	if paymentImport.IsExpired(time.Now()) {
+		if err := s.importRepo.TransitionStatus(ctx, id, models.PaymentImportStatusPreview, models.PaymentImportStatusExpired); err == nil {
+			paymentImport.Status = models.PaymentImportStatusExpired
+		}
+	}
+
+	if paymentImport.BatchID == nil {
+		return paymentImport, nil, nil
+	}
+	batch, err := s.batchService.GetBatch(ctx, *paymentImport.BatchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return paymentImport, batch, nil
+}
+
+// CheckFunds reports whether the sender's balance covers the valid rows of an import
+func (s *PaymentImportService) CheckFunds(ctx context.Context, paymentImport *models.PaymentImport) (bool, error) {
+	return s.transactionService.CanPerformTransaction(ctx, paymentImport.UserID, paymentImport.ValidTotal)
+}
+
+// ConfirmImport executes the valid rows of a previewed import as a best-effort batch
+func (s *PaymentImportService) ConfirmImport(ctx context.Context, paymentImport *models.PaymentImport) (*models.Batch, error) {
+	if paymentImport.Status == models.PaymentImportStatusExpired || paymentImport.IsExpired(time.Now()) {
+		return nil, ErrImportExpired
+	}
+	if paymentImport.ValidCount == 0 {
+		return nil, ErrImportNoValidRows
+	}
+
+	// Claim the import first so a double submit cannot execute it twice
+	if err := s.importRepo.TransitionStatus(ctx, paymentImport.ID, models.PaymentImportStatusPreview, models.PaymentImportStatusConfirmed); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImportNotPending, err)
+	}
+
+	req := importBatchRequest(paymentImport)
+	batch, err := s.batchService.SubmitBatch(ctx, paymentImport.UserID, req)
+	if err != nil {
+		// Nothing was executed, let the user confirm again later
+		s.importRepo.TransitionStatus(ctx, paymentImport.ID, models.PaymentImportStatusConfirmed, models.PaymentImportStatusPreview)
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.importRepo.SetBatch(ctx, paymentImport.ID, batch.ID, now); err != nil {
+		s.logger.Error("Failed to link payment import to batch",
+			zap.String("import_id", paymentImport.ID.String()),
+			zap.String("batch_id", batch.ID.String()),
+			zap.Error(err))
+	}
+	paymentImport.Status = models.PaymentImportStatusConfirmed
+	paymentImport.BatchID = &batch.ID
+	paymentImport.ConfirmedAt = &now
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, paymentImport.UserID, "PAYMENT_IMPORT_CONFIRMED", "payment_import", paymentImport.ID.String(),
+			fmt.Sprintf("Payment import confirmed as batch %s (Items: %d, Total: %f)", batch.ID, len(req.Items), paymentImport.ValidTotal))
+	}
+
+	return batch, nil
+}
+
+// importBatchRequest builds the best-effort batch for the valid rows of an import.
+// Each row keeps its reference, the remittance information of the payment.
+func importBatchRequest(paymentImport *models.PaymentImport) *models.BatchTransferRequest {
+	req := &models.BatchTransferRequest{
+		Mode:      models.BatchModeBestEffort,
+		Reference: truncateRunes("Import "+paymentImport.MessageID, 100),
+		Items:     make([]models.BatchTransferItem, 0, paymentImport.ValidCount),
+	}
+	for _, row := range paymentImport.Rows {
+		if !row.Valid {
+			continue
+		}
+		req.Items = append(req.Items, models.BatchTransferItem{
+			ToUserID:  *row.ToUserID,
+			Amount:    row.Amount,
+			Reference: truncateRunes(row.Reference, 100),
+		})
+	}
+	return req
+}
+
+// CancelImport discards a previewed import
+func (s *PaymentImportService) CancelImport(ctx context.Context, paymentImport *models.PaymentImport) error {
+	if err := s.importRepo.TransitionStatus(ctx, paymentImport.ID, models.PaymentImportStatusPreview, models.PaymentImportStatusCancelled); err != nil {
+		return fmt.Errorf("%w: %v", ErrImportNotPending, err)
+	}
+	paymentImport.Status = models.PaymentImportStatusCancelled
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, paymentImport.UserID, "PAYMENT_IMPORT_CANCELLED", "payment_import", paymentImport.ID.String(),
+			"Payment import cancelled")
+	}
+	return nil
+}
+
+// StatusReport renders a pain.002 status report for an executed import. Rows rejected
+// during preview are reported as RJCT next to the outcome of the executed batch items.
+func (s *PaymentImportService) StatusReport(paymentImport *models.PaymentImport, batch *models.Batch) ([]byte, error) {
+	if batch == nil || !batch.IsFinished() {
+		return nil, ErrImportNotFinished
+	}
+
+	itemsByPosition := make(map[int]*models.BatchItem, len(batch.Items))
+	for i := range batch.Items {
+		itemsByPosition[batch.Items[i].Position] = &batch.Items[i]
+	}
+
+	originalMessageName := "pain.001.001.03"
+	if paymentImport.Format == models.PaymentFileFormatCSV {
+		originalMessageName = "CSV"
+	}
+
+	report := &iso20022.Pain002Report{
+		MessageID:             strings.ReplaceAll(batch.ID.String(), "-", ""),
+		CreatedAt:             time.Now(),
+		OriginalMessageID:     paymentImport.MessageID,
+		OriginalMessageNameID: originalMessageName,
+		Transactions:          make([]iso20022.Pain002Transaction, 0, len(paymentImport.Rows)),
+	}
+
+	for _, row := range paymentImport.Rows {
+		tx := iso20022.Pain002Transaction{
+			PaymentInformationID: row.PaymentInformationID,
+			EndToEndID:           row.EndToEndID,
+			Status:               iso20022.StatusRejected,
+			Reason:               row.Error,
+			Amount:               row.Amount,
+			Currency:             row.Currency,
+		}
+		if tx.PaymentInformationID == "" {
+			// CSV files have no payment information blocks, report them as one
+			tx.PaymentInformationID = paymentImport.MessageID
+		}
+		if tx.EndToEndID == "" {
+			tx.EndToEndID = "NOTPROVIDED"
+		}
+
+		if row.Valid && row.BatchPosition != nil {
+			if item, ok := itemsByPosition[*row.BatchPosition]; ok {
+				switch item.Status {
+				case models.BatchItemStatusCompleted:
+					tx.Status = iso20022.StatusSettled
+				case models.BatchItemStatusRejected, models.BatchItemStatusFailed:
+					tx.Status = iso20022.StatusRejected
+				default:
+					tx.Status = iso20022.StatusPending
+				}
+				tx.Reason = item.Error
+			}
+		}
+		report.Transactions = append(report.Transactions, tx)
+	}
+
+	return iso20022.NewPain002Document(report).Marshal()
+}
+
+// validatePayments validates every payment and flags duplicates within the file
+func (s *PaymentImportService) validatePayments(ctx context.Context, userID, importID uuid.UUID, payments []importedPayment) []models.PaymentImportRow {
+	rows := make([]models.PaymentImportRow, len(payments))
+	endToEndLines := make(map[string]int)
+	paymentLines := make(map[string]int)
+	batchPosition := 0
+
+	for i, payment := range payments {
+		row := &rows[i]
+		*row = models.PaymentImportRow{
+			ID:                   uuid.New(),
+			ImportID:             importID,
+			Line:                 payment.line,
+			PaymentInformationID: truncateRunes(payment.paymentInformationID, 35),
+			EndToEndID:           truncateRunes(payment.endToEndID, 35),
+			Recipient:            truncateRunes(firstNonEmpty(payment.recipient, payment.recipientIBAN), 100),
+			RecipientName:        truncateRunes(payment.recipientName, 140),
+			Currency:             truncateRunes(strings.ToUpper(firstNonEmpty(payment.currency, s.currency)), 3),
+			Reference:            truncateRunes(payment.reference, 140),
+		}
+
+		err := s.validatePayment(ctx, userID, payment, row)
+		if err == nil && row.EndToEndID != "" {
+			if line, ok := endToEndLines[row.EndToEndID]; ok {
+				err = fmt.Errorf("duplicate end-to-end id (line %d)", line)
+			} else {
+				endToEndLines[row.EndToEndID] = row.Line
+			}
+		}
+		if err == nil {
+			key := fmt.Sprintf("%s|%.2f|%s", row.ToUserID, row.Amount, row.Reference)
+			if line, ok := paymentLines[key]; ok {
+				err = fmt.Errorf("possible duplicate of line %d", line)
+			} else {
+				paymentLines[key] = row.Line
+			}
+		}
+
+		if err != nil {
+			row.Error = err.Error()
+			continue
+		}
+		batchPosition++
+		position := batchPosition
+		row.Valid = true
+		row.BatchPosition = &position
+	}
+
+	return rows
+}
+
+// validatePayment checks a single payment and resolves its recipient
+func (s *PaymentImportService) validatePayment(ctx context.Context, userID uuid.UUID, payment importedPayment, row *models.PaymentImportRow) error {
+	if payment.parseError != "" {
+		return errors.New(payment.parseError)
+	}
+	if utf8.RuneCountInString(payment.endToEndID) > 35 {
+		return fmt.Errorf("end-to-end id exceeds 35 characters")
+	}
+
+	amount, err := strconv.ParseFloat(payment.amount, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q", payment.amount)
+	}
+	row.Amount = amount
+	if amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	if math.Abs(amount*100-math.Round(amount*100)) > 1e-6 {
+		return fmt.Errorf("amount must have at most two decimals")
+	}
+	if row.Currency != s.currency {
+		return fmt.Errorf("unsupported currency %s, expected %s", row.Currency, s.currency)
+	}
+
+	if payment.recipient == "" {
+		if payment.recipientIBAN != "" {
+			return fmt.Errorf("IBAN creditor accounts are not supported")
+		}
+		return fmt.Errorf("recipient is required")
+	}
+
+	var recipient *models.User
+	if recipientID, parseErr := uuid.Parse(payment.recipient); parseErr == nil {
+		recipient, err = s.userRepo.GetByID(ctx, recipientID)
+	} else {
+		recipient, err = s.userRepo.GetByUsername(ctx, payment.recipient)
+	}
+	if err != nil || recipient == nil {
+		return fmt.Errorf("recipient not found")
+	}
+	row.ToUserID = &recipient.ID
+
+	if recipient.ID == userID {
+		return fmt.Errorf("cannot transfer to same account")
+	}
+	if !recipient.IsActive() {
+		return fmt.Errorf("recipient account is not active")
+	}
+	return nil
+}
+
+// parseCSVPayments reads a CSV payment file. The header row names the columns:
+// recipient (user ID or username) and amount are required, reference, end_to_end_id,
+// currency and name are optional. Comma and semicolon separated files are accepted.
+func parseCSVPayments(data []byte) ([]importedPayment, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if firstLine, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["to_user_id"]; ok {
+		if _, ok := columns["recipient"]; !ok {
+			columns["recipient"] = columns["to_user_id"]
+		}
+	}
+	for _, required := range []string{"recipient", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %q column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	payments := make([]importedPayment, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row %d: %w", len(payments)+1, err)
+		}
+
+		payment := importedPayment{
+			line:       len(payments) + 1,
+			endToEndID: field(record, "end_to_end_id"),
+			recipient:  field(record, "recipient"),
+			amount:     field(record, "amount"),
+			currency:   field(record, "currency"),
+			reference:  field(record, "reference"),
+		}
+		payment.recipientName = field(record, "name")
+		if len(record) != len(header) {
+			payment.parseError = fmt.Sprintf("expected %d columns, got %d", len(header), len(record))
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, nil
+}
+
+// pain001Payments converts the credit transfers of a pain.001 document
+func pain001Payments(doc *iso20022.Pain001Document) []importedPayment {
+	transfers := doc.Payments()
+	payments := make([]importedPayment, len(transfers))
+	for i, transfer := range transfers {
+		payments[i] = importedPayment{
+			line:                 transfer.Line,
+			paymentInformationID: transfer.PaymentInformationID,
+			endToEndID:           transfer.EndToEndID,
+			recipient:            transfer.CreditorAccountID,
+			recipientIBAN:        transfer.CreditorIBAN,
+			recipientName:        transfer.CreditorName,
+			amount:               transfer.Amount,
+			currency:             transfer.Currency,
+			reference:            transfer.RemittanceInfo,
+		}
+	}
+	return payments
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// truncateRunes cuts s to at most max characters
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
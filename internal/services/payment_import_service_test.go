@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/processing"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// usernameRepository resolves recipients by username; other methods are not used
+type usernameRepository struct {
+	interfaces.UserRepository
+	users map[string]*models.User
+}
+
+func (r *usernameRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	if user, ok := r.users[username]; ok {
+		return user, nil
+	}
+	return nil, fmt.Errorf("user %s not found", username)
+}
+
+// resultListener hands out the transactions of settled jobs
+type resultListener struct {
+	results chan *processing.TransactionResult
+}
+
+func (l *resultListener) OnJobStarted(job *processing.TransactionJob) {}
+
+func (l *resultListener) OnJobCompleted(result *processing.TransactionResult) {
+	if !result.WillRetry {
+		l.results <- result
+	}
+}
+
+func TestImportedPaymentReferenceReachesTransaction(t *testing.T) {
+	sender := &models.User{ID: uuid.New(), Username: "sender"}
+	recipient := &models.User{ID: uuid.New(), Username: "supplier"}
+	importService := &PaymentImportService{
+		userRepo: &usernameRepository{users: map[string]*models.User{recipient.Username: recipient}},
+		currency: "TRY",
+		logger:   zap.NewNop(),
+	}
+
+	payments, err := parseCSVPayments([]byte("recipient,amount,reference\nsupplier,125.50,invoice 4471\n"))
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	paymentImport := &models.PaymentImport{
+		ID:         uuid.New(),
+		UserID:     sender.ID,
+		MessageID:  "MSG1",
+		ValidCount: 1,
+		Rows:       importService.validatePayments(context.Background(), sender.ID, uuid.New(), payments),
+	}
+	if !paymentImport.Rows[0].Valid {
+		t.Fatalf("row rejected: %s", paymentImport.Rows[0].Error)
+	}
+
+	// The batch item as created by SubmitBatch and queued as a best-effort transfer job
+	req := importBatchRequest(paymentImport)
+	item := &models.BatchItem{Position: 1, ToUserID: req.Items[0].ToUserID, Amount: req.Items[0].Amount, Reference: req.Items[0].Reference}
+	job := transferJobs(sender.ID, []*models.BatchItem{item})[0]
+
+	pool := processing.NewWorkerPool(1, 10, zap.NewNop())
+	defer pool.Shutdown(5 * time.Second)
+	listener := &resultListener{results: make(chan *processing.TransactionResult, 1)}
+	pool.AddListener(listener)
+
+	jobService := &JobService{
+		transactionService: &processing.MockTransactionService{},
+		auditService:       &processing.MockAuditService{},
+		workerPool:         pool,
+		logger:             zap.NewNop(),
+	}
+	if err := pool.SubmitJob(jobService.toTransactionJob(job)); err != nil {
+		t.Fatalf("submit job: %v", err)
+	}
+
+	select {
+	case result := <-listener.results:
+		if !result.Success {
+			t.Fatalf("transfer failed: %v", result.Error)
+		}
+		if result.Transaction.Reference != "invoice 4471" {
+			t.Errorf("expected reference %q on the transaction, got %q", "invoice 4471", result.Transaction.Reference)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("transfer job did not complete")
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidSearchQuery is returned when the search text or its options are invalid
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// Highlight markers are control characters that cannot appear in escaped output, so the
+// highlighted text can be HTML-escaped before the markers become <mark> tags
+const (
+	highlightStart   = "\x02"
+	highlightStop    = "\x03"
+	headlineSettings = "StartSel=\x02, StopSel=\x03, MaxWords=25, MinWords=8, MaxFragments=2, FragmentDelimiter=\" … \""
+)
+
+// SearchService runs ranked full-text searches over transactions and audit logs
+type SearchService struct {
+	logger *zap.Logger
+}
+
+// NewSearchService creates a new SearchService
+func NewSearchService(logger *zap.Logger) *SearchService {
+	return &SearchService{
+		logger: logger,
+	}
+}
+
+// searchHit is a ranked match before the matching record is loaded
+type searchHit struct {
+	ID        uuid.UUID
+	Rank      float64
+	Highlight string
+	CreatedAt time.Time
+}
+
+// Search returns the best matches across the requested record types, ordered by rank.
+// Queries use websearch syntax: quoted phrases, "or" and a leading "-" to exclude terms.
+func (s *SearchService) Search(ctx context.Context, query *models.SearchQuery) ([]*models.SearchResult, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return nil, fmt.Errorf("%w: q is required", ErrInvalidSearchQuery)
+	}
+	if utf8.RuneCountInString(query.Text) > models.MaxSearchTextLength {
+		return nil, fmt.Errorf("%w: q must be at most %d characters", ErrInvalidSearchQuery, models.MaxSearchTextLength)
+	}
+	for _, resultType := range query.Types {
+		if resultType != models.SearchResultTransaction && resultType != models.SearchResultAuditLog {
+			return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidSearchQuery, resultType)
+		}
+	}
+	if query.From != nil && query.To != nil && query.From.After(*query.To) {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidSearchQuery)
+	}
+	if query.Limit <= 0 {
+		query.Limit = models.DefaultSearchLimit
+	}
+	if query.Limit > models.MaxSearchLimit {
+		query.Limit = models.MaxSearchLimit
+	}
+
+	results := make([]*models.SearchResult, 0, query.Limit)
+
+	if query.Includes(models.SearchResultTransaction) {
+		transactionResults, err := s.searchTransactions(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, transactionResults...)
+	}
+
+	if query.Includes(models.SearchResultAuditLog) {
+		auditResults, err := s.searchAuditLogs(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, auditResults...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].CreatedAt.After(results[j].CreatedAt)
+	})
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+
+	s.logger.Debug("Search completed",
+		zap.Int("result_count", len(results)),
+		zap.Bool("scoped", query.OwnerID != nil))
+
+	return results, nil
+}
+
+// searchTransactions matches references and counterparty usernames
+func (s *SearchService) searchTransactions(ctx context.Context, query *models.SearchQuery) ([]*models.SearchResult, error) {
+	document := "concat_ws(' ', reference, " +
+		"(SELECT username FROM users WHERE users.id = transactions.from_user_id), " +
+		"(SELECT username FROM users WHERE users.id = transactions.to_user_id))"
+
+	hits, err := s.rank(ctx, "transactions", document, query, repository.TransactionFilterScope(models.TransactionFilter{
+		AccountID: query.OwnerID,
+		From:      query.From,
+		To:        query.To,
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transactions: %w", err)
+	}
+	if len(hits) == 0 {
+		return nil, nil
+	}
+
+	var transactions []*models.Transaction
+	if err := database.GetDB().WithContext(ctx).Where("id IN ?", hitIDs(hits)).Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load matching transactions: %w", err)
+	}
+	byID := make(map[uuid.UUID]*models.Transaction, len(transactions))
+	for _, transaction := range transactions {
+		byID[transaction.ID] = transaction
+	}
+
+	results := make([]*models.SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		if transaction, ok := byID[hit.ID]; ok {
+			result := newSearchResult(models.SearchResultTransaction, hit)
+			result.Transaction = transaction.ToResponse()
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// searchAuditLogs matches audit log details and actions
+func (s *SearchService) searchAuditLogs(ctx context.Context, query *models.SearchQuery) ([]*models.SearchResult, error) {
+	hits, err := s.rank(ctx, "audit_logs", "details", query, func(db *gorm.DB) *gorm.DB {
+		if query.OwnerID != nil {
+			db = db.Where("user_id = ?", *query.OwnerID)
+		}
+		if query.From != nil {
+			db = db.Where("created_at >= ?", *query.From)
+		}
+		if query.To != nil {
+			db = db.Where("created_at <= ?", *query.To)
+		}
+		return db
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search audit logs: %w", err)
+	}
+	if len(hits) == 0 {
+		return nil, nil
+	}
+
+	var auditLogs []*models.AuditLog
+	if err := database.GetDB().WithContext(ctx).Where("id IN ?", hitIDs(hits)).Find(&auditLogs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load matching audit logs: %w", err)
+	}
+	byID := make(map[uuid.UUID]*models.AuditLog, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		byID[auditLog.ID] = auditLog
+	}
+
+	results := make([]*models.SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		if auditLog, ok := byID[hit.ID]; ok {
+			result := newSearchResult(models.SearchResultAuditLog, hit)
+			result.AuditLog = auditLog.ToResponse()
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// rank runs the full-text match against a table's search_vector and returns the top hits
+// with their rank and a highlighted excerpt of document
+func (s *SearchService) rank(ctx context.Context, table, document string, query *models.SearchQuery, scope func(*gorm.DB) *gorm.DB) ([]searchHit, error) {
+	config := database.SearchConfiguration
+
+	var hits []searchHit
+	err := database.GetDB().WithContext(ctx).
+		Table(table).
+		Select(fmt.Sprintf("%s.id, %s.created_at, ts_rank_cd(search_vector, q.query, 32) AS rank, ts_headline('%s', %s, q.query, ?) AS highlight",
+			table, table, config, document), headlineSettings).
+		Joins(fmt.Sprintf("CROSS JOIN websearch_to_tsquery('%s', ?) AS q(query)", config), query.Text).
+		Where("search_vector @@ q.query").
+		Scopes(scope).
+		Order("rank DESC, created_at DESC").
+		Limit(query.Limit).
+		Scan(&hits).Error
+	return hits, err
+}
+
+// newSearchResult converts a hit into a result with a safe highlight
+func newSearchResult(resultType models.SearchResultType, hit searchHit) *models.SearchResult {
+	highlight := html.EscapeString(hit.Highlight)
+	highlight = strings.ReplaceAll(highlight, highlightStart, "<mark>")
+	highlight = strings.ReplaceAll(highlight, highlightStop, "</mark>")
+
+	return &models.SearchResult{
+		Type:      resultType,
+		ID:        hit.ID,
+		Rank:      hit.Rank,
+		Highlight: highlight,
+		CreatedAt: hit.CreatedAt,
+	}
+}
+
+func hitIDs(hits []searchHit) []uuid.UUID {
+	ids := make([]uuid.UUID, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrSessionNotFound is returned when a session does not exist, is no longer active or
+	// belongs to another user
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrUserNotFound is returned when an admin operation targets an unknown user
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// SessionService lists and revokes login sessions (token families) for their users and admins
+type SessionService struct {
+	userRepo     interfaces.UserRepository
+	auditService interfaces.AuditService
+	logger       *zap.Logger
+}
+
+// NewSessionService creates a new SessionService
+func NewSessionService(userRepo interfaces.UserRepository, auditService interfaces.AuditService, logger *zap.Logger) *SessionService {
+	return &SessionService{
+		userRepo:     userRepo,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// ListSessions returns the active sessions of a user, most recently used first
+func (s *SessionService) ListSessions(ctx context.Context, userID uuid.UUID) ([]*models.TokenFamily, error) {
+	var sessions []*models.TokenFamily
+	err := database.GetDB().WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// ListUserSessions returns the active sessions of a user for an admin
+func (s *SessionService) ListUserSessions(ctx context.Context, userID uuid.UUID) ([]*models.TokenFamily, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+	return s.ListSessions(ctx, userID)
+}
+
+// RevokeSession ends one session of the user; its refresh and access tokens stop working
+func (s *SessionService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if err := s.revoke(ctx, userID, sessionID, models.TokenRevokedSession); err != nil {
+		return err
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "SESSION_REVOKED", "token_family", sessionID.String(), "Session revoked by user")
+	}
+	return nil
+}
+
+// AdminRevokeSession ends one session of a user on behalf of an admin
+func (s *SessionService) AdminRevokeSession(ctx context.Context, adminID, userID, sessionID uuid.UUID) error {
+	if err := s.revoke(ctx, userID, sessionID, models.TokenRevokedByAdmin); err != nil {
+		return err
+	}
+
+	s.logger.Info("Session revoked by admin",
+		zap.String("admin_id", adminID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("session_id", sessionID.String()))
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, adminID, "ADMIN_SESSION_REVOKED", "token_family", sessionID.String(),
+			fmt.Sprintf("Session of user %s revoked by admin", userID))
+	}
+	return nil
+}
+
+// AdminRevokeAllSessions ends every session of a user on behalf of an admin and returns
+// the number of sessions revoked
+func (s *SessionService) AdminRevokeAllSessions(ctx context.Context, adminID, userID uuid.UUID) (int64, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return 0, ErrUserNotFound
+	}
+
+	revoked, err := revokeFamilies(database.GetDB().WithContext(ctx), models.TokenRevokedByAdmin, time.Now(), "user_id = ?", userID)
+	if err != nil {
+		return 0, err
+	}
+
+	s.logger.Info("All sessions revoked by admin",
+		zap.String("admin_id", adminID.String()),
+		zap.String("user_id", userID.String()),
+		zap.Int64("sessions_revoked", revoked))
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, adminID, "ADMIN_SESSIONS_REVOKED", "user", userID.String(),
+			fmt.Sprintf("All sessions revoked by admin (Sessions: %d)", revoked))
+	}
+	return revoked, nil
+}
+
+// revoke revokes an active session owned by the user
+func (s *SessionService) revoke(ctx context.Context, userID, sessionID uuid.UUID, reason models.TokenRevocationReason) error {
+	now := time.Now()
+	revoked, err := revokeFamilies(database.GetDB().WithContext(ctx), reason, now, "id = ? AND user_id = ? AND expires_at > ?", sessionID, userID, now)
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/pkg/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// keyPublishLead is how long a new key is published in the JWKS before it signs, so
+	// verifiers caching the JWKS learn it before they see tokens signed with it
+	keyPublishLead = time.Hour
+	// keyRefreshInterval is how often instances reload keys and check for a due rotation
+	keyRefreshInterval = time.Minute
+)
+
+// SigningKeyPolicy configures JWT signing keys
+type SigningKeyPolicy struct {
+	Algorithm        string        // utils.SigningAlgorithmRS256 or utils.SigningAlgorithmEdDSA
+	RotationInterval time.Duration // how long each key signs
+	Overlap          time.Duration // how long a key still verifies after it stops signing
+}
+
+// SigningKeyService keeps the JWT key set in the database, rotates keys on schedule and
+// reloads them into the in-memory key set shared with token signing and validation. All
+// instances share the stored keys, so tokens verify regardless of which instance issued them.
+type SigningKeyService struct {
+	keys          *utils.KeySet
+	encryptionKey []byte
+	policy        SigningKeyPolicy
+	logger        *zap.Logger
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewSigningKeyService creates a new SigningKeyService
+func NewSigningKeyService(keys *utils.KeySet, encryptionKey []byte, policy SigningKeyPolicy, logger *zap.Logger) *SigningKeyService {
+	return &SigningKeyService{
+		keys:          keys,
+		encryptionKey: encryptionKey,
+		policy:        policy,
+		logger:        logger,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Load creates a key if none is active and loads the key set. It must succeed before
+// tokens can be issued.
+func (s *SigningKeyService) Load(ctx context.Context) error {
+	if err := s.rotateIfDue(ctx, time.Now()); err != nil {
+		return err
+	}
+	return s.reload(ctx, time.Now())
+}
+
+// Start periodically rotates and reloads keys until Stop is called
+func (s *SigningKeyService) Start() {
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(keyRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if err := s.Load(context.Background()); err != nil {
+					// Keep the loaded keys; they stay valid well past one refresh interval
+					s.logger.Error("Signing key refresh failed",
+						zap.Error(err),
+						zap.String("type", "signing_key_error"),
+					)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the refresh loop started by Start
+func (s *SigningKeyService) Stop() error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+	return nil
+}
+
+// JWKS returns the public keys for /.well-known/jwks.json
+func (s *SigningKeyService) JWKS() utils.JWKSet {
+	return s.keys.JWKS(time.Now())
+}
+
+// rotateIfDue adds the next key when the newest one is about to finish its signing period,
+// activating at the end of that period, or an immediately active key when there is no
+// usable key or the configured algorithm changed. Expired keys are deleted first.
+func (s *SigningKeyService) rotateIfDue(ctx context.Context, now time.Time) error {
+	return database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialize rotation across instances; readers are not blocked
+		if err := tx.Exec("LOCK TABLE jwt_signing_keys IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return fmt.Errorf("failed to lock signing keys: %w", err)
+		}
+
+		if err := tx.Where("expires_at <= ?", now).Delete(&models.JWTSigningKey{}).Error; err != nil {
+			return fmt.Errorf("failed to delete expired signing keys: %w", err)
+		}
+		// Published keys that never signed are dropped when the algorithm changes
+		if err := tx.Where("activates_at > ? AND algorithm <> ?", now, s.policy.Algorithm).Delete(&models.JWTSigningKey{}).Error; err != nil {
+			return fmt.Errorf("failed to delete pending signing keys: %w", err)
+		}
+
+		var newest models.JWTSigningKey
+		err := tx.Order("activates_at DESC").First(&newest).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to load signing keys: %w", err)
+		}
+
+		var activatesAt time.Time
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound),
+			newest.Algorithm != s.policy.Algorithm,
+			!now.Before(newest.ActivatesAt.Add(s.policy.RotationInterval)):
+			activatesAt = now
+		case newest.ActivatesAt.After(now):
+			// The next key is already published
+		case !now.Before(newest.ActivatesAt.Add(s.policy.RotationInterval - keyPublishLead)):
+			activatesAt = newest.ActivatesAt.Add(s.policy.RotationInterval)
+		}
+
+		if activatesAt.IsZero() {
+			return nil
+		}
+		return s.createKey(tx, activatesAt)
+	})
+}
+
+// createKey generates and stores a key signing from activatesAt
+func (s *SigningKeyService) createKey(tx *gorm.DB, activatesAt time.Time) error {
+	private, err := utils.GenerateSigningKeyPair(s.policy.Algorithm)
+	if err != nil {
+		return fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	encrypted, err := utils.EncryptSecret(s.encryptionKey, string(der))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt signing key: %w", err)
+	}
+
+	key := &models.JWTSigningKey{
+		ID:          uuid.New().String(),
+		Algorithm:   s.policy.Algorithm,
+		PrivateKey:  encrypted,
+		ActivatesAt: activatesAt,
+		ExpiresAt:   activatesAt.Add(s.policy.RotationInterval + s.policy.Overlap),
+	}
+	if err := tx.Create(key).Error; err != nil {
+		return fmt.Errorf("failed to store signing key: %w", err)
+	}
+
+	s.logger.Info("Signing key created",
+		zap.String("kid", key.ID),
+		zap.String("alg", key.Algorithm),
+		zap.Time("activates_at", key.ActivatesAt),
+		zap.Time("expires_at", key.ExpiresAt),
+		zap.String("type", "signing_key_created"),
+	)
+	return nil
+}
+
+// reload replaces the in-memory key set with the unexpired stored keys
+func (s *SigningKeyService) reload(ctx context.Context, now time.Time) error {
+	var stored []models.JWTSigningKey
+	if err := database.GetDB().WithContext(ctx).Where("expires_at > ?", now).Find(&stored).Error; err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	keys := make([]*utils.SigningKey, 0, len(stored))
+	for _, key := range stored {
+		der, err := utils.DecryptSecret(s.encryptionKey, key.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt signing key %s: %w", key.ID, err)
+		}
+		parsed, err := x509.ParsePKCS8PrivateKey([]byte(der))
+		if err != nil {
+			return fmt.Errorf("failed to parse signing key %s: %w", key.ID, err)
+		}
+		private, ok := parsed.(crypto.Signer)
+		if !ok {
+			return fmt.Errorf("signing key %s is not a signer", key.ID)
+		}
+
+		keys = append(keys, &utils.SigningKey{
+			ID:          key.ID,
+			Algorithm:   key.Algorithm,
+			PrivateKey:  private,
+			ActivatesAt: key.ActivatesAt,
+			ExpiresAt:   key.ExpiresAt,
+		})
+	}
+
+	s.keys.Replace(keys)
+	return nil
+}
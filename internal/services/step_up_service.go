@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/pkg/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidStepUpChallenge is returned for an invalid, expired, foreign or already used challenge
+	ErrInvalidStepUpChallenge = errors.New("invalid step-up challenge")
+	// ErrInvalidStepUpCredentials is returned when the re-authentication fails
+	ErrInvalidStepUpCredentials = errors.New("invalid step-up credentials")
+	// ErrStepUpMethodNotAllowed is returned when the credentials sent do not match the user's
+	// required method, e.g. a password from a user with MFA enabled
+	ErrStepUpMethodNotAllowed = errors.New("step-up method not allowed")
+	// ErrInvalidStepUpToken is returned for a missing, invalid or expired elevated token
+	ErrInvalidStepUpToken = errors.New("invalid step-up token")
+)
+
+// StepUpService issues step-up challenges and short-lived elevated tokens proving that the
+// user re-authenticated within the current session
+type StepUpService struct {
+	userService  *UserService
+	userRepo     interfaces.UserRepository
+	mfaService   *MFAService
+	tokenService *TokenService
+	auditService interfaces.AuditService
+	jwtConfig    *utils.JWTConfig
+	tokenTTL     time.Duration
+	challengeTTL time.Duration
+	maxAttempts  int // wrong credentials before a challenge is used up
+	logger       *zap.Logger
+}
+
+// NewStepUpService creates a new StepUpService
+func NewStepUpService(
+	userService *UserService,
+	userRepo interfaces.UserRepository,
+	mfaService *MFAService,
+	tokenService *TokenService,
+	auditService interfaces.AuditService,
+	jwtConfig *utils.JWTConfig,
+	tokenTTL time.Duration,
+	challengeTTL time.Duration,
+	maxAttempts int,
+	logger *zap.Logger,
+) *StepUpService {
+	return &StepUpService{
+		userService:  userService,
+		userRepo:     userRepo,
+		mfaService:   mfaService,
+		tokenService: tokenService,
+		auditService: auditService,
+		jwtConfig:    jwtConfig,
+		tokenTTL:     tokenTTL,
+		challengeTTL: challengeTTL,
+		maxAttempts:  maxAttempts,
+		logger:       logger,
+	}
+}
+
+// CreateStepUpChallenge issues a challenge bound to the user's session. Users with MFA
+// enabled must answer with a second factor; a password alone is accepted only without MFA.
+func (s *StepUpService) CreateStepUpChallenge(ctx context.Context, userID, sessionID uuid.UUID) (*models.StepUpChallenge, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	token, err := utils.GenerateStepUpChallengeToken(user, sessionID, uuid.New(), s.challengeTTL, s.jwtConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate step-up challenge: %w", err)
+	}
+
+	methods, acr := stepUpPolicy(user)
+	return &models.StepUpChallenge{
+		StepUpRequired: true,
+		ChallengeToken: token,
+		Methods:        methods,
+		ACR:            acr,
+		ExpiresIn:      int64(s.challengeTTL.Seconds()),
+	}, nil
+}
+
+// CompleteStepUp verifies the re-authentication for a challenge of the same session and
+// returns an elevated token. Each challenge can be completed once and is used up after
+// maxAttempts wrong credentials, so a stolen access token cannot guess without limit.
+func (s *StepUpService) CompleteStepUp(ctx context.Context, userID, sessionID uuid.UUID, req *models.StepUpRequest) (*models.StepUpResponse, error) {
+	claims, err := utils.ValidateTokenOfType(req.ChallengeToken, utils.TokenTypeStepUpChallenge, s.jwtConfig)
+	if err != nil || claims.ExpiresAt == nil || claims.UserID != userID.String() || claims.SessionID != sessionID.String() {
+		return nil, ErrInvalidStepUpChallenge
+	}
+	challengeID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, ErrInvalidStepUpChallenge
+	}
+
+	db := database.GetDB().WithContext(ctx)
+	used, err := tokenIDUsed(db, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		return nil, ErrInvalidStepUpChallenge
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	amr, err := s.authenticate(ctx, user, req)
+	if err != nil {
+		if !errors.Is(err, ErrInvalidStepUpCredentials) {
+			return nil, err
+		}
+		if s.auditService != nil {
+			s.auditService.LogUserActivity(ctx, userID, "STEP_UP_FAILED", "session", sessionID.String(), "Step-up re-authentication failed")
+		}
+		if recordErr := s.recordChallengeFailure(db, userID, challengeID, claims.ExpiresAt.Time); recordErr != nil {
+			return nil, recordErr
+		}
+		return nil, err
+	}
+
+	consumed, err := consumeTokenID(db, userID, challengeID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidStepUpChallenge
+	}
+
+	_, acr := stepUpPolicy(user)
+	amrValues := make([]string, len(amr))
+	for i, method := range amr {
+		amrValues[i] = string(method)
+	}
+	token, err := utils.GenerateStepUpToken(user, sessionID, amrValues, acr, s.tokenTTL, s.jwtConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate step-up token: %w", err)
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "STEP_UP_SUCCEEDED", "session", sessionID.String(),
+			fmt.Sprintf("Step-up re-authentication succeeded (ACR: %s)", acr))
+	}
+
+	return &models.StepUpResponse{
+		StepUpToken: token,
+		ExpiresIn:   int64(s.tokenTTL.Seconds()),
+		AMR:         amr,
+		ACR:         acr,
+	}, nil
+}
+
+// VerifyStepUpToken validates an elevated token for the user's current session. Logging
+// out or revoking the session also invalidates its elevated tokens.
+func (s *StepUpService) VerifyStepUpToken(ctx context.Context, token string, userID, sessionID uuid.UUID) (*utils.JWTClaims, error) {
+	claims, err := utils.ValidateTokenOfType(token, utils.TokenTypeStepUp, s.jwtConfig)
+	if err != nil || claims.UserID != userID.String() || claims.SessionID != sessionID.String() {
+		return nil, ErrInvalidStepUpToken
+	}
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, ErrInvalidStepUpToken
+	}
+
+	revoked, err := s.tokenService.IsRevoked(ctx, tokenID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrInvalidStepUpToken
+	}
+	return claims, nil
+}
+
+// recordChallengeFailure counts wrong credentials for a challenge and uses the challenge up
+// once the limit is reached. The count is incremented atomically so parallel guesses
+// cannot exceed it.
+func (s *StepUpService) recordChallengeFailure(db *gorm.DB, userID, challengeID uuid.UUID, expiresAt time.Time) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var failures int
+		err := tx.Raw(`INSERT INTO step_up_challenge_failures (challenge_id, user_id, failures, expires_at)
+			VALUES (?, ?, 1, ?)
+			ON CONFLICT (challenge_id) DO UPDATE SET failures = step_up_challenge_failures.failures + 1
+			RETURNING failures`, challengeID, userID, expiresAt).Scan(&failures).Error
+		if err != nil {
+			return fmt.Errorf("failed to record step-up failure: %w", err)
+		}
+		if err := tx.Where("expires_at < ?", time.Now()).Delete(&models.StepUpChallengeFailure{}).Error; err != nil {
+			return fmt.Errorf("failed to purge step-up failures: %w", err)
+		}
+		if failures < s.maxAttempts {
+			return nil
+		}
+		if _, err := consumeTokenID(tx, userID, challengeID, expiresAt); err != nil {
+			return err
+		}
+		s.logger.Warn("Step-up challenge used up after failed attempts",
+			zap.String("user_id", userID.String()),
+			zap.Int("failures", failures),
+			zap.String("type", "step_up_challenge_exhausted"))
+		return nil
+	})
+}
+
+// authenticate checks the credentials of a step-up request against the user's policy and
+// returns the methods used
+func (s *StepUpService) authenticate(ctx context.Context, user *models.User, req *models.StepUpRequest) ([]models.AuthMethod, error) {
+	var amr []models.AuthMethod
+	if req.Password != "" {
+		if _, err := s.userService.VerifyUserPassword(ctx, user.ID, req.Password); err != nil {
+			return nil, ErrInvalidStepUpCredentials
+		}
+		amr = append(amr, models.AuthMethodPassword)
+	}
+
+	if !user.MFAEnabled {
+		if req.Password == "" {
+			return nil, ErrStepUpMethodNotAllowed
+		}
+		return amr, nil
+	}
+
+	if req.Code == "" {
+		return nil, ErrStepUpMethodNotAllowed
+	}
+	method, err := s.mfaService.Verify(ctx, user.ID, req.Code)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMFACode) || errors.Is(err, ErrMFANotEnabled) {
+			return nil, ErrInvalidStepUpCredentials
+		}
+		return nil, err
+	}
+	return append(amr, method), nil
+}
+
+// stepUpPolicy returns the methods a user may re-authenticate with and the resulting assurance level
+func stepUpPolicy(user *models.User) ([]models.AuthMethod, string) {
+	if user.MFAEnabled {
+		return []models.AuthMethod{models.AuthMethodOTP, models.AuthMethodRecoveryCode}, models.ACRMultiFactor
+	}
+	return []models.AuthMethod{models.AuthMethodPassword}, models.ACRPassword
+}
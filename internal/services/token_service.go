@@ -0,0 +1,373 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/interfaces"
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/pkg/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sessionTouchInterval is the resolution of a session's last seen time
+const sessionTouchInterval = time.Minute
+
+var (
+	// ErrInvalidRefreshToken is returned for a malformed, expired, unknown or revoked refresh token
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused is returned when an already rotated refresh token is presented
+	// again; the whole token family has been revoked
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
+// TokenService issues access and refresh tokens, rotates refresh tokens within their
+// family and maintains the access token revocation list
+type TokenService struct {
+	userRepo     interfaces.UserRepository
+	auditService interfaces.AuditService
+	jwtConfig    *utils.JWTConfig
+	logger       *zap.Logger
+}
+
+// NewTokenService creates a new TokenService
+func NewTokenService(userRepo interfaces.UserRepository, auditService interfaces.AuditService, jwtConfig *utils.JWTConfig, logger *zap.Logger) *TokenService {
+	return &TokenService{
+		userRepo:     userRepo,
+		auditService: auditService,
+		jwtConfig:    jwtConfig,
+		logger:       logger,
+	}
+}
+
+// IssueTokens starts a new session (token family) for a successful login and returns its
+// first token pair
+func (s *TokenService) IssueTokens(ctx context.Context, user *models.User, client *models.SessionClient) (*models.TokenPair, error) {
+	now := time.Now()
+	device := client.Device
+	if device == "" {
+		device = deviceFromUserAgent(client.UserAgent)
+	}
+	family := &models.TokenFamily{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		Device:     truncate(device, 100),
+		UserAgent:  truncate(client.UserAgent, 512),
+		IPAddress:  client.IPAddress,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.jwtConfig.RefreshTokenExpiry),
+	}
+	refresh := &models.RefreshToken{
+		ID:        uuid.New(),
+		FamilyID:  family.ID,
+		UserID:    user.ID,
+		ExpiresAt: family.ExpiresAt,
+	}
+
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(family).Error; err != nil {
+			return fmt.Errorf("failed to create token family: %w", err)
+		}
+		if err := tx.Create(refresh).Error; err != nil {
+			return fmt.Errorf("failed to create refresh token: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signPair(user, family.ID, refresh)
+}
+
+// Refresh exchanges a refresh token for a new pair. The presented token is marked used;
+// presenting a used token again revokes its whole family, since either the legitimate
+// client or an attacker holds a stolen copy.
+func (s *TokenService) Refresh(ctx context.Context, refreshToken, ipAddress string) (*models.TokenPair, error) {
+	claims, err := utils.ValidateTokenOfType(refreshToken, utils.TokenTypeRefresh, s.jwtConfig)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	now := time.Now()
+	var reused bool
+	var current models.RefreshToken
+	next := &models.RefreshToken{ID: uuid.New()}
+
+	err = database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the token so concurrent refreshes with the same token are serialized
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", tokenID).First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return fmt.Errorf("failed to get refresh token: %w", err)
+		}
+
+		var family models.TokenFamily
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", current.FamilyID).First(&family).Error; err != nil {
+			return fmt.Errorf("failed to get token family: %w", err)
+		}
+		if !family.IsActive(now) {
+			return ErrInvalidRefreshToken
+		}
+
+		if current.UsedAt != nil {
+			reused = true
+			_, err := revokeFamilies(tx, models.TokenRevokedReuseDetected, now, "id = ?", family.ID)
+			return err
+		}
+
+		if err := tx.Model(&models.RefreshToken{}).Where("id = ?", current.ID).
+			Update("used_at", now).Error; err != nil {
+			return fmt.Errorf("failed to mark refresh token used: %w", err)
+		}
+
+		next.FamilyID = family.ID
+		next.UserID = current.UserID
+		next.ParentID = &current.ID
+		next.ExpiresAt = now.Add(s.jwtConfig.RefreshTokenExpiry)
+		if err := tx.Create(next).Error; err != nil {
+			return fmt.Errorf("failed to create refresh token: %w", err)
+		}
+		if err := tx.Model(&models.TokenFamily{}).Where("id = ?", family.ID).
+			Updates(map[string]interface{}{"expires_at": next.ExpiresAt, "last_seen_at": now, "ip_address": ipAddress}).Error; err != nil {
+			return fmt.Errorf("failed to extend token family: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if reused {
+		s.logger.Warn("Refresh token reuse detected, token family revoked",
+			zap.String("user_id", current.UserID.String()),
+			zap.String("family_id", current.FamilyID.String()),
+			zap.String("token_id", current.ID.String()),
+			zap.String("type", "refresh_token_reuse"))
+		if s.auditService != nil {
+			s.auditService.LogUserActivity(ctx, current.UserID, "REFRESH_TOKEN_REUSE_DETECTED", "token_family", current.FamilyID.String(),
+				"Rotated refresh token presented again; all tokens of the session revoked")
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	// Claims are reissued from the stored user so role changes apply on the next refresh
+	user, err := s.userRepo.GetByID(ctx, current.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	return s.signPair(user, current.FamilyID, next)
+}
+
+// Logout revokes the session of the presented access token and the token itself
+func (s *TokenService) Logout(ctx context.Context, userID, sessionID, accessTokenID uuid.UUID, accessExpiresAt time.Time) error {
+	now := time.Now()
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := revokeFamilies(tx, models.TokenRevokedLogout, now, "id = ? AND user_id = ?", sessionID, userID); err != nil {
+			return err
+		}
+		return revokeTokenID(tx, userID, accessTokenID, accessExpiresAt, models.TokenRevokedLogout)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "LOGOUT", "token_family", sessionID.String(), "User logged out")
+	}
+	return nil
+}
+
+// LogoutAll revokes every active session of the user together with the presented access
+// token, and returns the number of sessions revoked
+func (s *TokenService) LogoutAll(ctx context.Context, userID, accessTokenID uuid.UUID, accessExpiresAt time.Time) (int64, error) {
+	now := time.Now()
+	var revoked int64
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		revoked, err = revokeFamilies(tx, models.TokenRevokedLogoutAll, now, "user_id = ?", userID)
+		if err != nil {
+			return err
+		}
+		return revokeTokenID(tx, userID, accessTokenID, accessExpiresAt, models.TokenRevokedLogoutAll)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserActivity(ctx, userID, "LOGOUT_ALL", "user", userID.String(),
+			fmt.Sprintf("All sessions logged out (Sessions: %d)", revoked))
+	}
+	return revoked, nil
+}
+
+// ValidateAccessToken validates the signature, expiry and type of an access token
+func (s *TokenService) ValidateAccessToken(token string) (*utils.JWTClaims, error) {
+	return utils.ValidateTokenOfType(token, utils.TokenTypeAccess, s.jwtConfig)
+}
+
+// IsRevoked reports whether an access token is on the revocation list or belongs to a
+// revoked session
+func (s *TokenService) IsRevoked(ctx context.Context, tokenID, sessionID uuid.UUID) (bool, error) {
+	var revoked bool
+	err := database.GetDB().WithContext(ctx).Raw(
+		`SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = ?)
+			OR EXISTS (SELECT 1 FROM token_families WHERE id = ? AND revoked_at IS NOT NULL)`,
+		tokenID, sessionID).Scan(&revoked).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	return revoked, nil
+}
+
+// TouchSession records that a session was used. Writes are limited to one per
+// sessionTouchInterval so authenticated requests do not each update the row.
+func (s *TokenService) TouchSession(ctx context.Context, sessionID uuid.UUID, ipAddress string) {
+	now := time.Now()
+	err := database.GetDB().WithContext(ctx).Model(&models.TokenFamily{}).
+		Where("id = ? AND (last_seen_at < ? OR ip_address <> ?)", sessionID, now.Add(-sessionTouchInterval), ipAddress).
+		Updates(map[string]interface{}{"last_seen_at": now, "ip_address": ipAddress}).Error
+	if err != nil {
+		s.logger.Warn("Failed to update session last seen",
+			zap.String("session_id", sessionID.String()),
+			zap.Error(err))
+	}
+}
+
+// revokeTokenID puts a token jti on the revocation list and drops entries whose tokens
+// have expired on their own
+func revokeTokenID(tx *gorm.DB, userID, tokenID uuid.UUID, expiresAt time.Time, reason models.TokenRevocationReason) error {
+	entry := &models.RevokedToken{JTI: tokenID, UserID: userID, Reason: reason, ExpiresAt: expiresAt}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	if err := tx.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{}).Error; err != nil {
+		return fmt.Errorf("failed to purge revoked tokens: %w", err)
+	}
+	return nil
+}
+
+// consumeTokenID marks a single-use token (a login or step-up challenge) as used. It
+// reports false if the token was used before; the primary key decides concurrent attempts.
+func consumeTokenID(tx *gorm.DB, userID, tokenID uuid.UUID, expiresAt time.Time) (bool, error) {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.RevokedToken{
+		JTI:       tokenID,
+		UserID:    userID,
+		Reason:    models.TokenRevokedChallengeUsed,
+		ExpiresAt: expiresAt,
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to consume token: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// tokenIDUsed reports whether a token ID is on the revocation list
+func tokenIDUsed(tx *gorm.DB, tokenID uuid.UUID) (bool, error) {
+	var count int64
+	if err := tx.Model(&models.RevokedToken{}).Where("jti = ?", tokenID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check token: %w", err)
+	}
+	return count > 0, nil
+}
+
+// signPair signs an access token and the given refresh token for a session
+func (s *TokenService) signPair(user *models.User, sessionID uuid.UUID, refresh *models.RefreshToken) (*models.TokenPair, error) {
+	accessToken, err := utils.GenerateAccessToken(user, sessionID, s.jwtConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+	refreshToken, err := utils.GenerateRefreshToken(user, sessionID, refresh.ID, refresh.ExpiresAt, s.jwtConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return &models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		SessionID:    sessionID,
+		ExpiresIn:    int64(s.jwtConfig.AccessTokenExpiry.Seconds()),
+	}, nil
+}
+
+// revokeFamilies marks the active token families matching the condition as revoked and
+// returns how many were revoked
+func revokeFamilies(tx *gorm.DB, reason models.TokenRevocationReason, now time.Time, query string, args ...interface{}) (int64, error) {
+	result := tx.Model(&models.TokenFamily{}).Where(query, args...).Where("revoked_at IS NULL").
+		Updates(map[string]interface{}{"revoked_at": now, "revoked_reason": reason})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to revoke token families: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// deviceFromUserAgent derives a readable device name such as "Chrome on Windows"
+func deviceFromUserAgent(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	platform := "Unknown OS"
+	for _, candidate := range []struct{ token, name string }{
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, candidate.token) {
+			platform = candidate.name
+			break
+		}
+	}
+
+	// Order matters: Edge and Opera user agents also contain "Chrome", Chrome contains "Safari"
+	client := ""
+	for _, candidate := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"okhttp", "Android app"},
+		{"CFNetwork", "iOS app"},
+		{"curl/", "curl"},
+	} {
+		if strings.Contains(userAgent, candidate.token) {
+			client = candidate.name
+			break
+		}
+	}
+	if client == "" {
+		return platform
+	}
+	return client + " on " + platform
+}
+
+// truncate cuts a string to at most max bytes without splitting a UTF-8 sequence
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	for max > 0 && !utf8.RuneStart(value[max]) {
+		max--
+	}
+	return value[:max]
+}
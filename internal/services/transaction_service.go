@@ -2,15 +2,28 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/barannkoca/banking-backend/internal/authz"
 	"github.com/barannkoca/banking-backend/internal/database"
+	"github.com/barannkoca/banking-backend/internal/events"
 	"github.com/barannkoca/banking-backend/internal/interfaces"
 	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/barannkoca/banking-backend/internal/processing"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrAccountFrozen is returned when money would leave a frozen account
+	ErrAccountFrozen = errors.New("account is frozen")
+	// ErrTransactionNotRefundable is returned for transactions that are not completed
+	// deposits, withdrawals or transfers, including ones already refunded
+	ErrTransactionNotRefundable = errors.New("transaction cannot be refunded")
 )
 
 type TransactionService struct {
@@ -18,6 +31,9 @@ type TransactionService struct {
 	balanceRepo     interfaces.BalanceRepository
 	auditService    interfaces.AuditService
 	cache           interfaces.CacheService
+	categorizer     *Categorizer
+	broker          *events.Broker
+	policy          *authz.Policy
 	logger          *zap.Logger
 }
 
@@ -26,6 +42,9 @@ func NewTransactionService(
 	balanceRepo interfaces.BalanceRepository,
 	auditService interfaces.AuditService,
 	cache interfaces.CacheService,
+	categorizer *Categorizer,
+	broker *events.Broker,
+	policy *authz.Policy,
 	logger *zap.Logger,
 ) *TransactionService {
 	return &TransactionService{
@@ -33,15 +52,23 @@ func NewTransactionService(
 		balanceRepo:     balanceRepo,
 		auditService:    auditService,
 		cache:           cache,
+		categorizer:     categorizer,
+		broker:          broker,
+		policy:          policy,
 		logger:          logger,
 	}
 }
 
 // Credit adds money to account with database transaction and rollback support
-func (ts *TransactionService) Credit(ctx context.Context, accountID uuid.UUID, amount float64) error {
+func (ts *TransactionService) Credit(ctx context.Context, accountID uuid.UUID, amount float64, reference string) (*models.Transaction, error) {
 	// Validate credit amount
 	if amount <= 0 {
-		return fmt.Errorf("credit amount must be positive")
+		return nil, fmt.Errorf("credit amount must be positive")
+	}
+
+	// Replayed jobs must not move money twice
+	if existing, err := ts.findJobTransaction(ctx); err != nil || existing != nil {
+		return existing, err
 	}
 
 	// Create transaction record
@@ -51,9 +78,11 @@ func (ts *TransactionService) Credit(ctx context.Context, accountID uuid.UUID, a
 		Amount:    amount,
 		Type:      models.TransactionTypeDeposit,
 		Status:    models.TransactionStatusPending,
-		Reference: "Credit transaction",
+		Reference: referenceOr(reference, "Credit transaction"),
+		JobID:     jobIDFromContext(ctx),
 		CreatedAt: time.Now(),
 	}
+	transaction.Category = ts.categorize(ctx, transaction)
 
 	// Execute within database transaction
 	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -62,17 +91,17 @@ func (ts *TransactionService) Credit(ctx context.Context, accountID uuid.UUID, a
 			return fmt.Errorf("failed to create transaction record: %w", err)
 		}
 
-		// 2. Get current balance
+		// 2. Lock current balance, so batches and other jobs touching it wait
 		var balance models.Balance
-		if err := tx.Where("user_id = ?", accountID).First(&balance).Error; err != nil {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", accountID).First(&balance).Error; err != nil {
 			return fmt.Errorf("failed to get current balance: %w", err)
 		}
 
-		// 3. Update balance
-		newBalance := balance.Amount + amount
+		// 3. Update balance relative to its stored value
 		if err := tx.Model(&models.Balance{}).
 			Where("user_id = ?", accountID).
-			Update("amount", newBalance).Error; err != nil {
+			Update("amount", gorm.Expr("amount + ?", amount)).Error; err != nil {
 			return fmt.Errorf("failed to update balance: %w", err)
 		}
 
@@ -97,7 +126,7 @@ func (ts *TransactionService) Credit(ctx context.Context, accountID uuid.UUID, a
 			zap.String("account_id", accountID.String()),
 			zap.Float64("amount", amount),
 			zap.Error(err))
-		return err
+		return nil, err
 	}
 
 	// Log successful transaction
@@ -110,14 +139,21 @@ func (ts *TransactionService) Credit(ctx context.Context, accountID uuid.UUID, a
 		zap.String("account_id", accountID.String()),
 		zap.Float64("amount", amount))
 
-	return nil
+	transaction.Status = models.TransactionStatusCompleted
+	ts.publish(ctx, transaction)
+	return transaction, nil
 }
 
 // Debit removes money from account with database transaction and rollback support
-func (ts *TransactionService) Debit(ctx context.Context, accountID uuid.UUID, amount float64) error {
+func (ts *TransactionService) Debit(ctx context.Context, accountID uuid.UUID, amount float64, reference string) (*models.Transaction, error) {
 	// Validate debit amount
 	if amount <= 0 {
-		return fmt.Errorf("debit amount must be positive")
+		return nil, fmt.Errorf("debit amount must be positive")
+	}
+
+	// Replayed jobs must not move money twice
+	if existing, err := ts.findJobTransaction(ctx); err != nil || existing != nil {
+		return existing, err
 	}
 
 	// Create transaction record
@@ -127,9 +163,11 @@ func (ts *TransactionService) Debit(ctx context.Context, accountID uuid.UUID, am
 		Amount:     amount,
 		Type:       models.TransactionTypeWithdraw,
 		Status:     models.TransactionStatusPending,
-		Reference:  "Debit transaction",
+		Reference:  referenceOr(reference, "Debit transaction"),
+		JobID:      jobIDFromContext(ctx),
 		CreatedAt:  time.Now(),
 	}
+	transaction.Category = ts.categorize(ctx, transaction)
 
 	// Execute within database transaction
 	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -138,21 +176,24 @@ func (ts *TransactionService) Debit(ctx context.Context, accountID uuid.UUID, am
 			return fmt.Errorf("failed to create transaction record: %w", err)
 		}
 
-		// 2. Get current balance and check sufficient funds
+		// 2. Lock current balance and check sufficient funds
 		var balance models.Balance
-		if err := tx.Where("user_id = ?", accountID).First(&balance).Error; err != nil {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", accountID).First(&balance).Error; err != nil {
 			return fmt.Errorf("failed to get current balance: %w", err)
 		}
 
+		if balance.Frozen {
+			return ErrAccountFrozen
+		}
 		if balance.Amount < amount {
 			return fmt.Errorf("insufficient balance: current=%f, required=%f", balance.Amount, amount)
 		}
 
-		// 3. Update balance
-		newBalance := balance.Amount - amount
+		// 3. Update balance relative to its stored value
 		if err := tx.Model(&models.Balance{}).
 			Where("user_id = ?", accountID).
-			Update("amount", newBalance).Error; err != nil {
+			Update("amount", gorm.Expr("amount - ?", amount)).Error; err != nil {
 			return fmt.Errorf("failed to update balance: %w", err)
 		}
 
@@ -177,7 +218,7 @@ func (ts *TransactionService) Debit(ctx context.Context, accountID uuid.UUID, am
 			zap.String("account_id", accountID.String()),
 			zap.Float64("amount", amount),
 			zap.Error(err))
-		return err
+		return nil, err
 	}
 
 	// Log successful transaction
@@ -190,17 +231,24 @@ func (ts *TransactionService) Debit(ctx context.Context, accountID uuid.UUID, am
 		zap.String("account_id", accountID.String()),
 		zap.Float64("amount", amount))
 
-	return nil
+	transaction.Status = models.TransactionStatusCompleted
+	ts.publish(ctx, transaction)
+	return transaction, nil
 }
 
 // Transfer transfers money between two accounts with database transaction and rollback support
-func (ts *TransactionService) Transfer(ctx context.Context, fromAccountID, toAccountID uuid.UUID, amount float64) error {
+func (ts *TransactionService) Transfer(ctx context.Context, fromAccountID, toAccountID uuid.UUID, amount float64, reference string) (*models.Transaction, error) {
 	// Validate transfer
 	if fromAccountID == toAccountID {
-		return fmt.Errorf("cannot transfer to same account")
+		return nil, fmt.Errorf("cannot transfer to same account")
 	}
 	if amount <= 0 {
-		return fmt.Errorf("transfer amount must be positive")
+		return nil, fmt.Errorf("transfer amount must be positive")
+	}
+
+	// Replayed jobs must not move money twice
+	if existing, err := ts.findJobTransaction(ctx); err != nil || existing != nil {
+		return existing, err
 	}
 
 	// Create transaction record
@@ -211,9 +259,11 @@ func (ts *TransactionService) Transfer(ctx context.Context, fromAccountID, toAcc
 		Amount:     amount,
 		Type:       models.TransactionTypeTransfer,
 		Status:     models.TransactionStatusPending,
-		Reference:  "Transfer transaction",
+		Reference:  referenceOr(reference, "Transfer transaction"),
+		JobID:      jobIDFromContext(ctx),
 		CreatedAt:  time.Now(),
 	}
+	transaction.Category = ts.categorize(ctx, transaction)
 
 	// Execute within database transaction
 	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -222,24 +272,36 @@ func (ts *TransactionService) Transfer(ctx context.Context, fromAccountID, toAcc
 			return fmt.Errorf("failed to create transaction record: %w", err)
 		}
 
-		// 2. Get current balances
-		var fromBalance, toBalance models.Balance
-
-		// Get from account balance
-		if err := tx.Where("user_id = ?", fromAccountID).First(&fromBalance).Error; err != nil {
-			return fmt.Errorf("failed to get from account balance: %w", err)
+		// 2. Lock both balances in user ID order, so opposite transfers cannot deadlock
+		var balances []models.Balance
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id IN ?", []uuid.UUID{fromAccountID, toAccountID}).
+			Order("user_id").Find(&balances).Error; err != nil {
+			return fmt.Errorf("failed to get account balances: %w", err)
+		}
+		var fromBalance, toBalance *models.Balance
+		for i := range balances {
+			if balances[i].UserID == fromAccountID {
+				fromBalance = &balances[i]
+			} else {
+				toBalance = &balances[i]
+			}
+		}
+		if fromBalance == nil {
+			return fmt.Errorf("failed to get from account balance: %w", gorm.ErrRecordNotFound)
+		}
+		if toBalance == nil {
+			return fmt.Errorf("failed to get to account balance: %w", gorm.ErrRecordNotFound)
 		}
 
-		// Check sufficient balance
+		// Check the sender may pay and has sufficient balance
+		if fromBalance.Frozen {
+			return ErrAccountFrozen
+		}
 		if fromBalance.Amount < amount {
 			return fmt.Errorf("insufficient balance in from account: current=%f, required=%f", fromBalance.Amount, amount)
 		}
 
-		// Get to account balance
-		if err := tx.Where("user_id = ?", toAccountID).First(&toBalance).Error; err != nil {
-			return fmt.Errorf("failed to get to account balance: %w", err)
-		}
-
 		// 3. Update balances atomically
 		// Subtract from sender
 		if err := tx.Model(&models.Balance{}).
@@ -277,7 +339,7 @@ func (ts *TransactionService) Transfer(ctx context.Context, fromAccountID, toAcc
 			zap.String("to_account", toAccountID.String()),
 			zap.Float64("amount", amount),
 			zap.Error(err))
-		return err
+		return nil, err
 	}
 
 	// Log successful transaction
@@ -291,6 +353,294 @@ func (ts *TransactionService) Transfer(ctx context.Context, fromAccountID, toAcc
 		zap.String("to_account", toAccountID.String()),
 		zap.Float64("amount", amount))
 
+	transaction.Status = models.TransactionStatusCompleted
+	ts.publish(ctx, transaction)
+	return transaction, nil
+}
+
+// TransferBatch moves money from one account to several recipients in a single
+// database transaction: either every transfer is applied or none is. The sender's and
+// receivers' balance rows are locked up front in user ID order, so the funds check
+// cannot race and concurrent batches or transfers cannot deadlock. It runs
+// outside the per-account sequencer; Credit, Debit and Transfer lock the same rows and
+// update balances relative to their stored value, so no update is lost.
+func (ts *TransactionService) TransferBatch(ctx context.Context, fromAccountID uuid.UUID, items []*models.BatchItem) ([]*models.Transaction, error) {
+	var total float64
+	for _, item := range items {
+		if item.ToUserID == fromAccountID {
+			return nil, fmt.Errorf("item %d: cannot transfer to same account", item.Position)
+		}
+		if item.Amount <= 0 {
+			return nil, fmt.Errorf("item %d: transfer amount must be positive", item.Position)
+		}
+		total += item.Amount
+	}
+
+	// The sender's category rules are loaded once for the whole batch
+	var rules []*models.CategoryRule
+	if ts.categorizer != nil {
+		rules = ts.categorizer.RulesFor(ctx, &fromAccountID)
+	}
+
+	transactions := make([]*models.Transaction, len(items))
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Lock the sender and every receiver in user ID order, like Transfer, so
+		// batches and transfers between the same accounts cannot deadlock
+		accountIDs := []uuid.UUID{fromAccountID}
+		for _, item := range items {
+			accountIDs = append(accountIDs, item.ToUserID)
+		}
+		var balances []models.Balance
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id IN ?", accountIDs).
+			Order("user_id").Find(&balances).Error; err != nil {
+			return fmt.Errorf("failed to get account balances: %w", err)
+		}
+		locked := make(map[uuid.UUID]*models.Balance, len(balances))
+		for i := range balances {
+			locked[balances[i].UserID] = &balances[i]
+		}
+
+		// 2. Check the sender's funds and that every receiver exists before writing anything
+		fromBalance, ok := locked[fromAccountID]
+		if !ok {
+			return fmt.Errorf("failed to get from account balance: %w", gorm.ErrRecordNotFound)
+		}
+		if fromBalance.Frozen {
+			return ErrAccountFrozen
+		}
+		if fromBalance.Amount < total {
+			return fmt.Errorf("insufficient balance in from account: current=%f, required=%f", fromBalance.Amount, total)
+		}
+		for _, item := range items {
+			if _, ok := locked[item.ToUserID]; !ok {
+				return fmt.Errorf("item %d: receiver account not found", item.Position)
+			}
+		}
+
+		// 3. Record and apply each transfer
+		for i, item := range items {
+			transaction := &models.Transaction{
+				ID:         uuid.New(),
+				FromUserID: &fromAccountID,
+				ToUserID:   &items[i].ToUserID,
+				Amount:     item.Amount,
+				Type:       models.TransactionTypeTransfer,
+				Status:     models.TransactionStatusCompleted,
+				Reference:  referenceOr(item.Reference, "Batch transfer"),
+				CreatedAt:  time.Now(),
+			}
+			if ts.categorizer != nil {
+				transaction.Category = ts.categorizer.Apply(transaction, rules)
+			}
+			if err := tx.Create(transaction).Error; err != nil {
+				return fmt.Errorf("item %d: failed to create transaction record: %w", item.Position, err)
+			}
+
+			if err := tx.Model(&models.Balance{}).
+				Where("user_id = ?", item.ToUserID).
+				Update("amount", gorm.Expr("amount + ?", item.Amount)).Error; err != nil {
+				return fmt.Errorf("item %d: failed to add to receiver balance: %w", item.Position, err)
+			}
+			transactions[i] = transaction
+		}
+
+		// 4. Subtract the total from the sender
+		if err := tx.Model(&models.Balance{}).
+			Where("user_id = ?", fromAccountID).
+			Update("amount", gorm.Expr("amount - ?", total)).Error; err != nil {
+			return fmt.Errorf("failed to subtract from sender balance: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		ts.logger.Error("Batch transfer failed",
+			zap.String("from_account", fromAccountID.String()),
+			zap.Int("item_count", len(items)),
+			zap.Float64("total_amount", total),
+			zap.Error(err))
+		return nil, err
+	}
+
+	for _, transaction := range transactions {
+		if ts.auditService != nil {
+			ts.auditService.LogTransactionActivity(ctx, transaction, "TRANSFER_COMPLETED", "Batch transfer successful")
+		}
+	}
+
+	ts.logger.Info("Batch transfer completed",
+		zap.String("from_account", fromAccountID.String()),
+		zap.Int("item_count", len(items)),
+		zap.Float64("total_amount", total))
+
+	for _, transaction := range transactions {
+		ts.publish(ctx, transaction)
+	}
+	return transactions, nil
+}
+
+// Refund reverses a completed deposit, withdrawal or transfer: a refund transaction moves
+// the amount back and the original is marked refunded, so it is refunded at most once.
+// Refunds are corrections and run even if the paying account is frozen, but never
+// overdraw it.
+func (ts *TransactionService) Refund(ctx context.Context, transactionID uuid.UUID) (*models.Transaction, error) {
+	// Replayed jobs must not move money twice
+	if existing, err := ts.findJobTransaction(ctx); err != nil || existing != nil {
+		return existing, err
+	}
+
+	var refund *models.Transaction
+	err := database.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Lock the original so concurrent refunds serialize on it
+		var original models.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", transactionID).First(&original).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTransactionNotFound
+			}
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+		if !original.IsRefundable() {
+			return ErrTransactionNotRefundable
+		}
+
+		// 2. Move the money back: whoever received it pays, whoever paid receives
+		refund = &models.Transaction{
+			ID:         uuid.New(),
+			FromUserID: original.ToUserID,
+			ToUserID:   original.FromUserID,
+			Amount:     original.Amount,
+			Type:       models.TransactionTypeRefund,
+			Status:     models.TransactionStatusCompleted,
+			Reference:  "Refund of " + original.ID.String(),
+			Category:   original.Category,
+			JobID:      jobIDFromContext(ctx),
+			CreatedAt:  time.Now(),
+		}
+		if refund.FromUserID != nil {
+			var payer models.Balance
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("user_id = ?", *refund.FromUserID).First(&payer).Error; err != nil {
+				return fmt.Errorf("failed to get refunding account balance: %w", err)
+			}
+			if payer.Amount < refund.Amount {
+				return fmt.Errorf("insufficient balance for refund: current=%f, required=%f", payer.Amount, refund.Amount)
+			}
+			if err := tx.Model(&models.Balance{}).
+				Where("user_id = ?", *refund.FromUserID).
+				Update("amount", gorm.Expr("amount - ?", refund.Amount)).Error; err != nil {
+				return fmt.Errorf("failed to subtract refund: %w", err)
+			}
+		}
+		if refund.ToUserID != nil {
+			result := tx.Model(&models.Balance{}).
+				Where("user_id = ?", *refund.ToUserID).
+				Update("amount", gorm.Expr("amount + ?", refund.Amount))
+			if result.Error != nil {
+				return fmt.Errorf("failed to add refund: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("refunded account not found")
+			}
+		}
+
+		// 3. Record the refund and mark the original
+		if err := tx.Create(refund).Error; err != nil {
+			return fmt.Errorf("failed to create refund record: %w", err)
+		}
+		if err := tx.Model(&models.Transaction{}).
+			Where("id = ?", original.ID).
+			Update("status", models.TransactionStatusRefund).Error; err != nil {
+			return fmt.Errorf("failed to update transaction status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		ts.logger.Error("Refund failed",
+			zap.String("transaction_id", transactionID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	if ts.cache != nil {
+		ts.cache.InvalidateTransactionCache(ctx, transactionID)
+	}
+	if ts.auditService != nil {
+		ts.auditService.LogTransactionActivity(ctx, refund, "REFUND_COMPLETED", "Refund of "+transactionID.String())
+	}
+
+	ts.logger.Info("Refund completed",
+		zap.String("transaction_id", refund.ID.String()),
+		zap.String("refunded_transaction_id", transactionID.String()),
+		zap.Float64("amount", refund.Amount))
+
+	ts.publish(ctx, refund)
+	return refund, nil
+}
+
+// findJobTransaction returns the transaction already recorded for the job carried
+// in ctx, if any. Durable queues deliver jobs at least once, so a redelivered job
+// resolves to its original transaction instead of executing again.
+func (ts *TransactionService) findJobTransaction(ctx context.Context) (*models.Transaction, error) {
+	jobID, ok := processing.JobIDFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	var existing models.Transaction
+	result := database.GetDB().WithContext(ctx).
+		Where("job_id = ? AND status = ?", jobID, models.TransactionStatusCompleted).
+		Limit(1).
+		Find(&existing)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to check job transaction: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	ts.logger.Info("Job already executed, returning recorded transaction",
+		zap.String("job_id", jobID.String()),
+		zap.String("transaction_id", existing.ID.String()))
+	return &existing, nil
+}
+
+// jobIDFromContext returns the job ID carried by ctx as a nullable column value
+func jobIDFromContext(ctx context.Context) *uuid.UUID {
+	if jobID, ok := processing.JobIDFromContext(ctx); ok {
+		return &jobID
+	}
+	return nil
+}
+
+// referenceOr returns the caller's reference, or fallback when none was given
+func referenceOr(reference, fallback string) string {
+	if reference == "" {
+		return fallback
+	}
+	return reference
+}
+
+// AuthorizeCredit checks that actor may credit amount to the account. Users credit their
+// own accounts freely; crediting another account needs authz.AccountsCredit within the
+// role's limit. Returns ErrUserNotFound if the account does not exist.
+func (ts *TransactionService) AuthorizeCredit(ctx context.Context, actor authz.Subject, accountID uuid.UUID, amount float64) error {
+	if err := ts.policy.Evaluate(actor, authz.AccountsCredit, authz.Resource{OwnerID: accountID, Amount: amount}); err != nil {
+		return err
+	}
+	if accountID == actor.UserID {
+		return nil
+	}
+
+	var count int64
+	if err := database.GetDB().WithContext(ctx).Model(&models.User{}).Where("id = ?", accountID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check account: %w", err)
+	}
+	if count == 0 {
+		return ErrUserNotFound
+	}
 	return nil
 }
 
@@ -303,10 +653,62 @@ func (ts *TransactionService) CanPerformTransaction(ctx context.Context, account
 	return balance >= amount, nil
 }
 
-// GetTransactionHistory retrieves transaction history for a user
-func (ts *TransactionService) GetTransactionHistory(ctx context.Context, userID uuid.UUID, limit, offset int, transactionType, status string) ([]*models.Transaction, error) {
+// publish notifies both parties of a completed transaction and of their new balances.
+// Balances are read after the commit, so a stream never shows uncommitted money.
+func (ts *TransactionService) publish(ctx context.Context, transaction *models.Transaction) {
+	if ts.broker == nil {
+		return
+	}
+
+	statusChanged := events.TransactionStatusChanged{
+		TransactionID: transaction.ID,
+		Type:          string(transaction.Type),
+		Status:        string(transaction.Status),
+		Amount:        transaction.Amount,
+		FromUserID:    transaction.FromUserID,
+		ToUserID:      transaction.ToUserID,
+		Category:      string(transaction.Category),
+	}
+	for _, userID := range []*uuid.UUID{transaction.FromUserID, transaction.ToUserID} {
+		if userID == nil {
+			continue
+		}
+		ts.broker.Publish(*userID, events.EventTransactionStatusChanged, statusChanged)
+
+		balance, err := ts.balanceRepo.GetBalance(ctx, *userID)
+		if err != nil {
+			ts.logger.Warn("Failed to read balance for stream event",
+				zap.String("user_id", userID.String()),
+				zap.String("transaction_id", transaction.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		ts.broker.Publish(*userID, events.EventBalanceChanged, events.BalanceChanged{
+			UserID:        *userID,
+			Balance:       balance,
+			TransactionID: &transaction.ID,
+		})
+	}
+}
+
+// categorize assigns a category to a new transaction; it is left empty without a categorizer
+func (ts *TransactionService) categorize(ctx context.Context, transaction *models.Transaction) models.TransactionCategory {
+	if ts.categorizer == nil {
+		return ""
+	}
+	return ts.categorizer.Categorize(ctx, transaction)
+}
+
+// GetTransactionHistory retrieves a page of a user's transaction history. Pages are cached
+// under the query's cache key, so filtered and unfiltered histories never collide.
+func (ts *TransactionService) GetTransactionHistory(ctx context.Context, userID uuid.UUID, query *models.TransactionQuery) (*models.TransactionPage, error) {
+	query.Filter.AccountID = &userID
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Create cache key
-	cacheKey := fmt.Sprintf("transactions:%s:%d:%d:%s:%s", userID.String(), limit, offset, transactionType, status)
+	cacheKey := query.CacheKey()
 
 	// Try to get from cache first
 	if ts.cache != nil {
@@ -315,30 +717,16 @@ func (ts *TransactionService) GetTransactionHistory(ctx context.Context, userID
 				zap.String("user_id", userID.String()),
 				zap.String("cache_key", cacheKey),
 				zap.Int("count", len(cachedTransactions)))
-			return cachedTransactions, nil
+			return query.NewPage(cachedTransactions), nil
 		}
 	}
 
-	// Build query
-	query := database.GetDB().WithContext(ctx).Where("(from_user_id = ? OR to_user_id = ?)", userID, userID)
-
-	// Add filters
-	if transactionType != "" {
-		query = query.Where("type = ?", transactionType)
-	}
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
-
-	// Add ordering and pagination
-	query = query.Order("created_at DESC").Limit(limit).Offset(offset)
-
-	var transactions []*models.Transaction
-	if err := query.Find(&transactions).Error; err != nil {
+	transactions, err := ts.transactionRepo.FindByAccount(ctx, userID, query)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get transaction history: %w", err)
 	}
 
-	// Cache the result
+	// Cache the result, including the look-ahead row that decides whether more pages exist
 	if ts.cache != nil {
 		ts.cache.SetTransactions(ctx, cacheKey, transactions, 2*time.Minute)
 		ts.logger.Debug("Transaction history cached",
@@ -347,7 +735,7 @@ func (ts *TransactionService) GetTransactionHistory(ctx context.Context, userID
 			zap.Int("count", len(transactions)))
 	}
 
-	return transactions, nil
+	return query.NewPage(transactions), nil
 }
 
 // GetTransactionByID retrieves a transaction by its ID
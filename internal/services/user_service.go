@@ -2,28 +2,46 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/barannkoca/banking-backend/internal/database"
 	"github.com/barannkoca/banking-backend/internal/interfaces"
 	"github.com/barannkoca/banking-backend/internal/models"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// PasswordPolicyError is returned when a new password does not meet the password rules
+type PasswordPolicyError struct {
+	Reason string // user-facing explanation, in Turkish like the validation messages
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "new password validation failed: " + e.Reason
+}
+
+// ErrInvalidCurrentPassword is returned when a password change is requested with a wrong current password
+var ErrInvalidCurrentPassword = errors.New("invalid old password")
+
 // UserService implements the UserService interface
 type UserService struct {
-	userRepo     interfaces.UserRepository
-	auditService interfaces.AuditService
+	userRepo       interfaces.UserRepository
+	auditService   interfaces.AuditService
+	passwordPolicy PasswordPolicy
 }
 
 // NewUserService creates a new UserService instance
-func NewUserService(userRepo interfaces.UserRepository, auditService interfaces.AuditService) *UserService {
+func NewUserService(userRepo interfaces.UserRepository, auditService interfaces.AuditService, passwordPolicy PasswordPolicy) *UserService {
 	return &UserService{
-		userRepo:     userRepo,
-		auditService: auditService,
+		userRepo:       userRepo,
+		auditService:   auditService,
+		passwordPolicy: passwordPolicy,
 	}
 }
 
@@ -52,21 +70,6 @@ func (us *UserService) CreateUser(ctx context.Context, req *models.UserCreateReq
 		return nil, fmt.Errorf("username already exists")
 	}
 
-	// Determine role
-	role := models.RoleCustomer
-	if req.Role != "" {
-		switch strings.ToLower(req.Role) {
-		case "admin":
-			role = models.RoleAdmin
-		case "teller":
-			role = models.RoleTeller
-		case "customer":
-			role = models.RoleCustomer
-		default:
-			return nil, fmt.Errorf("invalid role: %s", req.Role)
-		}
-	}
-
 	// Hash password
 	hashedPassword, err := us.hashPassword(req.Password)
 	if err != nil {
@@ -74,14 +77,16 @@ func (us *UserService) CreateUser(ctx context.Context, req *models.UserCreateReq
 	}
 
 	// Create user
+	now := time.Now()
 	user := &models.User{
-		ID:           uuid.New(),
-		Username:     strings.TrimSpace(req.Username),
-		Email:        strings.TrimSpace(strings.ToLower(req.Email)),
-		PasswordHash: hashedPassword,
-		Role:         role,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		ID:                uuid.New(),
+		Username:          strings.TrimSpace(req.Username),
+		Email:             strings.TrimSpace(strings.ToLower(req.Email)),
+		PasswordHash:      hashedPassword,
+		PasswordChangedAt: &now,
+		Role:              models.RoleCustomer,
+		CreatedAt:         now,
+		UpdatedAt:         now,
 	}
 
 	// Validate user data
@@ -143,6 +148,11 @@ func (us *UserService) UpdateUser(ctx context.Context, user *models.User) error
 		return fmt.Errorf("user not found: %w", err)
 	}
 
+	// A new email address has to be verified again
+	if !strings.EqualFold(existingUser.Email, user.Email) {
+		user.EmailVerifiedAt = nil
+	}
+
 	// Update timestamp
 	user.UpdatedAt = time.Now()
 
@@ -194,10 +204,7 @@ func (us *UserService) GetAllUsers(ctx context.Context, limit, offset int) ([]*m
 // UpdateUserRole updates user role (role-based authorization)
 func (us *UserService) UpdateUserRole(ctx context.Context, userID uuid.UUID, role models.UserRole) error {
 	// Validate role
-	switch role {
-	case models.RoleCustomer, models.RoleAdmin, models.RoleTeller:
-		// Valid role
-	default:
+	if !models.IsValidRole(string(role)) {
 		return fmt.Errorf("invalid role: %s", role)
 	}
 
@@ -281,6 +288,18 @@ func (us *UserService) AuthenticateUser(ctx context.Context, usernameOrEmail, pa
 	return user, nil
 }
 
+// VerifyUserPassword checks the password of a known user without logging a login, e.g. for re-authentication
+func (us *UserService) VerifyUserPassword(ctx context.Context, userID uuid.UUID, password string) (*models.User, error) {
+	user, err := us.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	if !us.verifyPassword(password, user.PasswordHash) {
+		return nil, fmt.Errorf("invalid credentials")
+	}
+	return user, nil
+}
+
 // ChangePassword changes user password
 func (us *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error {
 	// Get user
@@ -291,12 +310,56 @@ func (us *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, old
 
 	// Verify old password
 	if !us.verifyPassword(oldPassword, user.PasswordHash) {
-		return fmt.Errorf("invalid old password")
+		return ErrInvalidCurrentPassword
+	}
+
+	if err := us.setPassword(database.GetDB().WithContext(ctx), user, newPassword); err != nil {
+		return err
+	}
+
+	// Log password change
+	if us.auditService != nil {
+		us.auditService.LogUserActivity(ctx, userID, "PASSWORD_CHANGED", "user", userID.String(), "Password changed successfully")
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password without the old one, after the caller verified a
+// reset token. It writes through tx, so the password commits together with the caller's
+// token and session changes; the caller records the audit entry once tx commits.
+func (us *UserService) ResetPassword(tx *gorm.DB, userID uuid.UUID, newPassword string) error {
+	var user models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error; err != nil {
+		return ErrUserNotFound
+	}
+
+	return us.setPassword(tx, &user, newPassword)
+}
+
+// IsPasswordExpired reports whether the user's password passed the policy's maximum age
+func (us *UserService) IsPasswordExpired(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := us.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, ErrUserNotFound
 	}
+	return us.passwordPolicy.Expired(user.PasswordChangedAt, time.Now()), nil
+}
 
+// setPassword validates, hashes and stores a new password through db, moving the old
+// hash to the password history
+func (us *UserService) setPassword(db *gorm.DB, user *models.User, newPassword string) error {
 	// Validate new password
-	if err := models.ValidatePassword(newPassword); err != nil {
-		return fmt.Errorf("new password validation failed: %w", err)
+	if err := us.passwordPolicy.Validate(newPassword); err != nil {
+		return err
+	}
+
+	reused, err := us.passwordReused(db, user, newPassword)
+	if err != nil {
+		return err
+	}
+	if reused {
+		return &PasswordPolicyError{Reason: fmt.Sprintf("şifre son %d şifrenizden biri olamaz", us.passwordPolicy.HistorySize)}
 	}
 
 	// Hash new password
@@ -305,20 +368,58 @@ func (us *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, old
 		return fmt.Errorf("password hashing failed: %w", err)
 	}
 
-	// Update password
+	previousHash := user.PasswordHash
+	now := time.Now()
 	user.PasswordHash = hashedPassword
-	user.UpdatedAt = time.Now()
+	user.PasswordChangedAt = &now
+	user.UpdatedAt = now
 
-	if err := us.userRepo.Update(ctx, user); err != nil {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+		if us.passwordPolicy.HistorySize <= 1 {
+			return nil
+		}
+		if err := tx.Create(&models.PasswordHistory{UserID: user.ID, PasswordHash: previousHash}).Error; err != nil {
+			return err
+		}
+		// Keep only the entries the policy checks
+		return tx.Where("user_id = ? AND id NOT IN (?)", user.ID,
+			tx.Model(&models.PasswordHistory{}).Select("id").Where("user_id = ?", user.ID).
+				Order("created_at DESC").Limit(us.passwordPolicy.HistorySize-1)).
+			Delete(&models.PasswordHistory{}).Error
+	})
+	if err != nil {
 		return fmt.Errorf("database error: %w", err)
 	}
+	return nil
+}
 
-	// Log password change
-	if us.auditService != nil {
-		us.auditService.LogUserActivity(ctx, userID, "PASSWORD_CHANGED", "user", userID.String(), "Password changed successfully")
+// passwordReused checks a new password against the current one and the previous ones kept
+// in the history, HistorySize passwords in total
+func (us *UserService) passwordReused(db *gorm.DB, user *models.User, newPassword string) (bool, error) {
+	if us.passwordPolicy.HistorySize <= 0 {
+		return false, nil
+	}
+	if us.verifyPassword(newPassword, user.PasswordHash) {
+		return true, nil
+	}
+	if us.passwordPolicy.HistorySize == 1 {
+		return false, nil
 	}
 
-	return nil
+	var history []models.PasswordHistory
+	if err := db.Where("user_id = ?", user.ID).Order("created_at DESC").
+		Limit(us.passwordPolicy.HistorySize - 1).Find(&history).Error; err != nil {
+		return false, fmt.Errorf("failed to load password history: %w", err)
+	}
+	for _, entry := range history {
+		if us.verifyPassword(newPassword, entry.PasswordHash) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // Authorization methods
@@ -399,8 +500,8 @@ func (us *UserService) validateCreateRequest(req *models.UserCreateRequest) erro
 	}
 
 	// Validate password strength
-	if err := models.ValidatePassword(req.Password); err != nil {
-		return fmt.Errorf("password validation failed: %w", err)
+	if err := us.passwordPolicy.Validate(req.Password); err != nil {
+		return err
 	}
 
 	// Validate email format
@@ -3,6 +3,7 @@ package utils
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/barannkoca/banking-backend/internal/models"
@@ -10,71 +11,146 @@ import (
 	"github.com/google/uuid"
 )
 
+// Token types carried in the token_type claim, so a refresh token cannot be used as an access token
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	// TokenTypeMFAChallenge is issued after the password step of a login and only accepted
+	// by the second (TOTP) step
+	TokenTypeMFAChallenge = "mfa_challenge"
+	// TokenTypeStepUpChallenge is returned when an operation needs fresh authentication
+	TokenTypeStepUpChallenge = "step_up_challenge"
+	// TokenTypeStepUp is the short-lived elevated token proving fresh authentication
+	TokenTypeStepUp = "step_up"
+	// TokenTypeClientAccess is issued to API clients by the client credentials grant
+	TokenTypeClientAccess = "client_access"
+)
+
 // JWTClaims represents the claims in a JWT token
 type JWTClaims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
+	UserID    string `json:"user_id"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	TokenType string `json:"token_type"`
+	SessionID string `json:"sid"` // token family of the login that issued the token
+	// Step-up tokens only: authentication methods (RFC 8176), assurance level and time of the re-authentication
+	AMR      []string         `json:"amr,omitempty"`
+	ACR      string           `json:"acr,omitempty"`
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	// Client access tokens only: the API client and its space-separated granted scopes
+	ClientID string `json:"client_id,omitempty"`
+	Scope    string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// JWTConfig holds JWT configuration
+// JWTConfig holds JWT configuration. Tokens are signed with the active key of Keys and
+// carry its kid, so other services can verify them with the published JWKS.
 type JWTConfig struct {
-	SecretKey          string
+	Keys               *KeySet
 	AccessTokenExpiry  time.Duration
 	RefreshTokenExpiry time.Duration
 }
 
-// DefaultJWTConfig returns default JWT configuration
-func DefaultJWTConfig() *JWTConfig {
-	return &JWTConfig{
-		SecretKey:          "your-secret-key-change-in-production", // TODO: Load from environment
-		AccessTokenExpiry:  15 * time.Minute,
-		RefreshTokenExpiry: 7 * 24 * time.Hour, // 7 days
+// GenerateAccessToken generates a new access token for a user in the given session
+func GenerateAccessToken(user *models.User, sessionID uuid.UUID, config *JWTConfig) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		UserID:    user.ID.String(),
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      string(user.Role),
+		TokenType: TokenTypeAccess,
+		SessionID: sessionID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(config.AccessTokenExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "banking-backend",
+			Subject:   user.ID.String(),
+			ID:        uuid.New().String(),
+		},
 	}
+
+	return signClaims(claims, config)
 }
 
-// GenerateAccessToken generates a new access token for a user
-func GenerateAccessToken(user *models.User, config *JWTConfig) (string, error) {
-	if config == nil {
-		config = DefaultJWTConfig()
+// GenerateRefreshToken generates a refresh token with the given jti in a session. The
+// caller persists tokenID so the token can be rotated and checked for reuse.
+func GenerateRefreshToken(user *models.User, sessionID, tokenID uuid.UUID, expiresAt time.Time, config *JWTConfig) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		UserID:    user.ID.String(),
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      string(user.Role),
+		TokenType: TokenTypeRefresh,
+		SessionID: sessionID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "banking-backend",
+			Subject:   user.ID.String(),
+			ID:        tokenID.String(),
+		},
 	}
 
+	return signClaims(claims, config)
+}
+
+// GenerateMFAChallengeToken generates a short-lived token proving the password step of a login
+func GenerateMFAChallengeToken(user *models.User, tokenID uuid.UUID, ttl time.Duration, config *JWTConfig) (string, error) {
 	now := time.Now()
 	claims := JWTClaims{
-		UserID:   user.ID.String(),
-		Username: user.Username,
-		Email:    user.Email,
-		Role:     string(user.Role),
+		UserID:    user.ID.String(),
+		TokenType: TokenTypeMFAChallenge,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(config.AccessTokenExpiry)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    "banking-backend",
 			Subject:   user.ID.String(),
-			ID:        uuid.New().String(),
+			ID:        tokenID.String(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(config.SecretKey))
+	return signClaims(claims, config)
 }
 
-// GenerateRefreshToken generates a new refresh token for a user
-func GenerateRefreshToken(user *models.User, config *JWTConfig) (string, error) {
-	if config == nil {
-		config = DefaultJWTConfig()
+// GenerateStepUpChallengeToken generates a challenge for re-authentication within a session
+func GenerateStepUpChallengeToken(user *models.User, sessionID, tokenID uuid.UUID, ttl time.Duration, config *JWTConfig) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		UserID:    user.ID.String(),
+		TokenType: TokenTypeStepUpChallenge,
+		SessionID: sessionID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "banking-backend",
+			Subject:   user.ID.String(),
+			ID:        tokenID.String(),
+		},
 	}
 
+	return signClaims(claims, config)
+}
+
+// GenerateStepUpToken generates an elevated token recording how and when the user re-authenticated
+func GenerateStepUpToken(user *models.User, sessionID uuid.UUID, amr []string, acr string, ttl time.Duration, config *JWTConfig) (string, error) {
 	now := time.Now()
 	claims := JWTClaims{
-		UserID:   user.ID.String(),
-		Username: user.Username,
-		Email:    user.Email,
-		Role:     string(user.Role),
+		UserID:    user.ID.String(),
+		Role:      string(user.Role),
+		TokenType: TokenTypeStepUp,
+		SessionID: sessionID.String(),
+		AMR:       amr,
+		ACR:       acr,
+		AuthTime:  jwt.NewNumericDate(now),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(config.RefreshTokenExpiry)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    "banking-backend",
@@ -83,23 +159,57 @@ func GenerateRefreshToken(user *models.User, config *JWTConfig) (string, error)
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(config.SecretKey))
+	return signClaims(claims, config)
 }
 
-// ValidateToken validates a JWT token and returns the claims
-func ValidateToken(tokenString string, config *JWTConfig) (*JWTClaims, error) {
-	if config == nil {
-		config = DefaultJWTConfig()
+// GenerateClientAccessToken generates an access token for an API client with the granted scopes
+func GenerateClientAccessToken(clientID string, scopes []string, ttl time.Duration, config *JWTConfig) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		TokenType: TokenTypeClientAccess,
+		ClientID:  clientID,
+		Scope:     strings.Join(scopes, " "),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "banking-backend",
+			Subject:   clientID,
+			ID:        uuid.New().String(),
+		},
 	}
 
+	return signClaims(claims, config)
+}
+
+// signClaims signs claims with the active key of the config and sets the kid header
+func signClaims(claims JWTClaims, config *JWTConfig) (string, error) {
+	key, err := config.Keys.Signer(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.SigningMethod(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
+// ValidateToken validates a JWT token against the key named by its kid header and
+// returns the claims
+func ValidateToken(tokenString string, config *JWTConfig) (*JWTClaims, error) {
+	now := time.Now()
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := config.Keys.Verifier(kid, now)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %q", kid)
+		}
+		// The algorithm must be the key's own, never one chosen by the token
+		if token.Method.Alg() != key.Algorithm {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(config.SecretKey), nil
-	})
+		return key.PublicKey(), nil
+	}, jwt.WithValidMethods([]string{SigningAlgorithmRS256, SigningAlgorithmEdDSA}))
 
 	if err != nil {
 		return nil, fmt.Errorf("token parsing failed: %w", err)
@@ -112,39 +222,16 @@ func ValidateToken(tokenString string, config *JWTConfig) (*JWTClaims, error) {
 	return nil, errors.New("invalid token")
 }
 
-// RefreshTokenPair generates new access and refresh tokens from a refresh token
-func RefreshTokenPair(refreshToken string, config *JWTConfig) (string, string, error) {
-	// Validate refresh token
-	claims, err := ValidateToken(refreshToken, config)
-	if err != nil {
-		return "", "", fmt.Errorf("invalid refresh token: %w", err)
-	}
-
-	// Create user object from claims
-	userID, err := uuid.Parse(claims.UserID)
-	if err != nil {
-		return "", "", fmt.Errorf("invalid user ID in token: %w", err)
-	}
-
-	user := &models.User{
-		ID:       userID,
-		Username: claims.Username,
-		Email:    claims.Email,
-		Role:     models.UserRole(claims.Role),
-	}
-
-	// Generate new token pair
-	accessToken, err := GenerateAccessToken(user, config)
+// ValidateTokenOfType validates a JWT token and checks its token_type claim
+func ValidateTokenOfType(tokenString, tokenType string, config *JWTConfig) (*JWTClaims, error) {
+	claims, err := ValidateToken(tokenString, config)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to generate access token: %w", err)
+		return nil, err
 	}
-
-	newRefreshToken, err := GenerateRefreshToken(user, config)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	if claims.TokenType != tokenType {
+		return nil, fmt.Errorf("unexpected token type: %q", claims.TokenType)
 	}
-
-	return accessToken, newRefreshToken, nil
+	return claims, nil
 }
 
 // ExtractUserFromToken extracts user information from a JWT token
@@ -168,4 +255,3 @@ func ExtractUserFromToken(tokenString string, config *JWTConfig) (*models.User,
 
 	return user, nil
 }
-
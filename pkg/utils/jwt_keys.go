@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported JWT signing algorithms
+const (
+	SigningAlgorithmRS256 = "RS256"
+	SigningAlgorithmEdDSA = "EdDSA"
+)
+
+// rsaKeyBits is the modulus size of generated RS256 keys
+const rsaKeyBits = 2048
+
+// ErrNoSigningKey is returned when the key set has no key active for signing
+var ErrNoSigningKey = errors.New("no active signing key")
+
+// SigningKey is an asymmetric JWT key identified by its kid. A key signs from ActivatesAt
+// until a newer key activates and verifies until ExpiresAt.
+type SigningKey struct {
+	ID          string
+	Algorithm   string
+	PrivateKey  crypto.Signer
+	ActivatesAt time.Time
+	ExpiresAt   time.Time
+}
+
+// SigningMethod returns the jwt signing method of the key's algorithm
+func (k *SigningKey) SigningMethod() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// PublicKey returns the key used to verify signatures
+func (k *SigningKey) PublicKey() crypto.PublicKey {
+	return k.PrivateKey.Public()
+}
+
+// GenerateSigningKeyPair generates a private key for the algorithm
+func GenerateSigningKeyPair(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case SigningAlgorithmRS256:
+		return rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case SigningAlgorithmEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		return private, err
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+}
+
+// KeySet holds the signing keys of the service. It is safe for concurrent use and is
+// replaced wholesale when keys are reloaded or rotated.
+type KeySet struct {
+	mu   sync.RWMutex
+	keys []*SigningKey // newest activation first
+}
+
+// NewKeySet creates an empty KeySet
+func NewKeySet() *KeySet {
+	return &KeySet{}
+}
+
+// Replace swaps the keys of the set
+func (ks *KeySet) Replace(keys []*SigningKey) {
+	sorted := make([]*SigningKey, len(keys))
+	copy(sorted, keys)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ActivatesAt.After(sorted[j].ActivatesAt)
+	})
+
+	ks.mu.Lock()
+	ks.keys = sorted
+	ks.mu.Unlock()
+}
+
+// Signer returns the newest key active at now
+func (ks *KeySet) Signer(now time.Time) (*SigningKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	for _, key := range ks.keys {
+		if !key.ActivatesAt.After(now) && key.ExpiresAt.After(now) {
+			return key, nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+// Verifier returns the key with the given kid if it has not expired. Keys published ahead
+// of their activation are accepted so instances that activated them earlier are trusted.
+func (ks *KeySet) Verifier(kid string, now time.Time) (*SigningKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	for _, key := range ks.keys {
+		if key.ID == kid && key.ExpiresAt.After(now) {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// Algorithms returns the algorithms of the keys in the set
+func (ks *KeySet) Algorithms() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var algorithms []string
+	for _, key := range ks.keys {
+		if !seen[key.Algorithm] {
+			seen[key.Algorithm] = true
+			algorithms = append(algorithms, key.Algorithm)
+		}
+	}
+	return algorithms
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517, RFC 8037 for Ed25519)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys that verify tokens, including keys published ahead of
+// their activation
+func (ks *KeySet) JWKS(now time.Time) JWKSet {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range ks.keys {
+		if !key.ExpiresAt.After(now) {
+			continue
+		}
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Algorithm}
+		switch public := key.PublicKey().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/barannkoca/banking-backend/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func newTestKey(t *testing.T, id, algorithm string, activatesAt, expiresAt time.Time) *SigningKey {
+	t.Helper()
+	private, err := GenerateSigningKeyPair(algorithm)
+	if err != nil {
+		t.Fatalf("GenerateSigningKeyPair(%s): %v", algorithm, err)
+	}
+	return &SigningKey{ID: id, Algorithm: algorithm, PrivateKey: private, ActivatesAt: activatesAt, ExpiresAt: expiresAt}
+}
+
+func newTestConfig(keys ...*SigningKey) *JWTConfig {
+	set := NewKeySet()
+	set.Replace(keys)
+	return &JWTConfig{Keys: set, AccessTokenExpiry: time.Minute, RefreshTokenExpiry: time.Hour}
+}
+
+func TestAccessTokenRoundTripPerAlgorithm(t *testing.T) {
+	now := time.Now()
+	user := &models.User{ID: uuid.New(), Username: "jwks", Role: models.RoleCustomer}
+
+	for _, algorithm := range []string{SigningAlgorithmRS256, SigningAlgorithmEdDSA} {
+		config := newTestConfig(newTestKey(t, "k-"+algorithm, algorithm, now.Add(-time.Minute), now.Add(time.Hour)))
+
+		token, err := GenerateAccessToken(user, uuid.New(), config)
+		if err != nil {
+			t.Fatalf("%s: GenerateAccessToken: %v", algorithm, err)
+		}
+		claims, err := ValidateTokenOfType(token, TokenTypeAccess, config)
+		if err != nil {
+			t.Fatalf("%s: ValidateTokenOfType: %v", algorithm, err)
+		}
+		if claims.UserID != user.ID.String() {
+			t.Errorf("%s: user_id = %s, want %s", algorithm, claims.UserID, user.ID)
+		}
+		if jwks := config.Keys.JWKS(now); len(jwks.Keys) != 1 || jwks.Keys[0].KeyID != "k-"+algorithm {
+			t.Errorf("%s: JWKS = %+v, want the single key", algorithm, jwks)
+		}
+	}
+}
+
+func TestRotationKeepsPreviousKeyForVerification(t *testing.T) {
+	now := time.Now()
+	user := &models.User{ID: uuid.New()}
+	previous := newTestKey(t, "previous", SigningAlgorithmEdDSA, now.Add(-2*time.Hour), now.Add(time.Hour))
+	config := newTestConfig(previous)
+
+	token, err := GenerateAccessToken(user, uuid.New(), config)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	current := newTestKey(t, "current", SigningAlgorithmRS256, now.Add(-time.Minute), now.Add(3*time.Hour))
+	pending := newTestKey(t, "pending", SigningAlgorithmRS256, now.Add(time.Hour), now.Add(4*time.Hour))
+	config.Keys.Replace([]*SigningKey{previous, current, pending})
+
+	if signer, err := config.Keys.Signer(now); err != nil || signer.ID != "current" {
+		t.Fatalf("Signer = %v, %v; want current", signer, err)
+	}
+	if _, err := ValidateToken(token, config); err != nil {
+		t.Errorf("token of the previous key rejected during overlap: %v", err)
+	}
+	if jwks := config.Keys.JWKS(now); len(jwks.Keys) != 3 {
+		t.Errorf("JWKS has %d keys, want previous, current and pending", len(jwks.Keys))
+	}
+
+	config.Keys.Replace([]*SigningKey{
+		{ID: "previous", Algorithm: previous.Algorithm, PrivateKey: previous.PrivateKey, ActivatesAt: previous.ActivatesAt, ExpiresAt: now.Add(-time.Second)},
+		current,
+	})
+	if _, err := ValidateToken(token, config); err == nil {
+		t.Error("token of an expired key accepted")
+	}
+}
+
+func TestValidateTokenRejectsSymmetricAlgorithm(t *testing.T) {
+	now := time.Now()
+	config := newTestConfig(newTestKey(t, "rsa", SigningAlgorithmRS256, now.Add(-time.Minute), now.Add(time.Hour)))
+
+	// HS256 token keyed with the published RSA modulus, the classic algorithm confusion attack
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, JWTClaims{TokenType: TokenTypeAccess})
+	forged.Header["kid"] = "rsa"
+	token, err := forged.SignedString([]byte(config.Keys.JWKS(now).Keys[0].N))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	if _, err := ValidateToken(token, config); err == nil {
+		t.Error("HS256 token accepted")
+	}
+}
+
+func TestClientAccessTokenIsNotAUserAccessToken(t *testing.T) {
+	now := time.Now()
+	config := newTestConfig(newTestKey(t, "ed", SigningAlgorithmEdDSA, now.Add(-time.Minute), now.Add(time.Hour)))
+
+	token, err := GenerateClientAccessToken("cli_test", []string{"users:read", "audit:read"}, time.Minute, config)
+	if err != nil {
+		t.Fatalf("GenerateClientAccessToken: %v", err)
+	}
+
+	claims, err := ValidateTokenOfType(token, TokenTypeClientAccess, config)
+	if err != nil {
+		t.Fatalf("ValidateTokenOfType: %v", err)
+	}
+	if claims.ClientID != "cli_test" || claims.Scope != "users:read audit:read" {
+		t.Errorf("client_id = %q, scope = %q", claims.ClientID, claims.Scope)
+	}
+	if _, err := ValidateTokenOfType(token, TokenTypeAccess, config); err == nil {
+		t.Error("client token accepted as a user access token")
+	}
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+var (
+	commonPasswords     map[string]struct{}
+	commonPasswordsOnce sync.Once
+)
+
+// IsCommonPassword reports whether a password appears in the embedded list of common and
+// breached passwords. The comparison ignores case.
+func IsCommonPassword(password string) bool {
+	commonPasswordsOnce.Do(func() {
+		lines := strings.Split(commonPasswordList, "\n")
+		commonPasswords = make(map[string]struct{}, len(lines))
+		for _, line := range lines {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			commonPasswords[strings.ToLower(line)] = struct{}{}
+		}
+	})
+
+	_, found := commonPasswords[strings.ToLower(password)]
+	return found
+}
@@ -0,0 +1,19 @@
+package utils
+
+import "testing"
+
+func TestIsCommonPasswordIgnoresCase(t *testing.T) {
+	for _, password := range []string{"password", "P@ssw0rd1", "QWERTY123", "Galatasaray1905"} {
+		if !IsCommonPassword(password) {
+			t.Errorf("IsCommonPassword(%q) = false, want true", password)
+		}
+	}
+}
+
+func TestIsCommonPasswordSkipsComments(t *testing.T) {
+	for _, password := range []string{"", "#", "k9$Vq2!mZr7x"} {
+		if IsCommonPassword(password) {
+			t.Errorf("IsCommonPassword(%q) = true, want false", password)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B test vectors for HMAC-SHA1
+func TestHOTPMatchesRFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, vector := range vectors {
+		step := TOTPStep(time.Unix(vector.unix, 0))
+		if got := hotp(key, uint64(step), 8); got != vector.code {
+			t.Errorf("hotp at %d = %s, want %s", vector.unix, got, vector.code)
+		}
+	}
+}
+
+func TestValidateTOTPAcceptsSkewAndReturnsStep(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	previous := TOTPStep(now) - 1
+
+	code, err := TOTPCode(secret, previous)
+	if err != nil {
+		t.Fatalf("TOTPCode: %v", err)
+	}
+
+	step, ok := ValidateTOTP(secret, code, now, 1)
+	if !ok || step != previous {
+		t.Fatalf("ValidateTOTP = (%d, %v), want (%d, true)", step, ok, previous)
+	}
+	if _, ok := ValidateTOTP(secret, code, now, 0); ok {
+		t.Fatal("code of the previous step accepted without skew")
+	}
+	if _, ok := ValidateTOTP(strings.ToLower(secret), code[:3]+" "+code[3:], now, 1); !ok {
+		t.Fatal("lower-case secret or spaced code rejected")
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("JBSWY3DPEHPK3PXP", "Banking Backend", "alice@example.com")
+	want := "otpauth://totp/Banking%20Backend:alice@example.com?algorithm=SHA1&digits=6&issuer=Banking+Backend&period=30&secret=JBSWY3DPEHPK3PXP"
+	if uri != want {
+		t.Fatalf("uri = %s, want %s", uri, want)
+	}
+}